@@ -17,6 +17,17 @@ const shutdownTimeout = 10 * time.Second
 
 func main() {
 	cfg := config.Load(cfgPath)
+
+	// Подкоманды выполняются вместо запуска сервера
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			os.Exit(runReconcile(cfg, os.Args[2:]))
+		default:
+			log.Fatalf("Неизвестная команда %q, доступные команды: reconcile", os.Args[1])
+		}
+	}
+
 	application, err := app.StartServer(cfg)
 	if err != nil {
 		log.Fatalf("Не удалось запустить сервер: %v", err)
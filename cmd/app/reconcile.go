@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/devopesik/wallet-basic-operations/internal/config"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+)
+
+// Коды завершения команды reconcile
+const (
+	reconcileOK      = 0
+	reconcileFailed  = 1
+	reconcileDrifted = 2
+)
+
+// runReconcile сверяет балансы кошельков с журналом и печатает отчёт в JSON.
+// Завершается с кодом 2, если остались нескорректированные расхождения.
+func runReconcile(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	fix := flags.Bool("fix", cfg.ReconciliationAutoCorrect, "закрыть расхождения корректирующими проводками")
+	if err := flags.Parse(args); err != nil {
+		return reconcileFailed
+	}
+
+	pool, err := postgres.NewPool(cfg)
+	if err != nil {
+		log.Printf("Не удалось подключиться к БД: %v", err)
+		return reconcileFailed
+	}
+	defer pool.Close()
+
+	svc := service.NewReconciliationService(postgres.NewReconciliationRepository(pool, postgres.NewRetryPolicy(cfg)))
+	run, err := svc.Reconcile(context.Background(), *fix)
+	if err != nil {
+		log.Printf("Сверка балансов завершилась с ошибкой: %v", err)
+		return reconcileFailed
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(run); err != nil {
+		log.Printf("Не удалось вывести отчёт сверки: %v", err)
+		return reconcileFailed
+	}
+
+	for _, d := range run.Drifts {
+		if !d.Corrected {
+			return reconcileDrifted
+		}
+	}
+	return reconcileOK
+}
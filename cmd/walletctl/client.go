@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/google/uuid"
+)
+
+// requestTimeout - таймаут запроса к удалённому HTTP API
+const requestTimeout = 30 * time.Second
+
+// walletClient выполняет операции команд: через HTTP API запущенного сервиса (remoteClient)
+// или через сервис кошельков в этом процессе (localClient). Запросы и ответы - модели HTTP API
+type walletClient interface {
+	CreateWallet(ctx context.Context, req generated.CreateWalletRequest) (*generated.WalletBalanceResponse, error)
+	GetWallet(ctx context.Context, walletID uuid.UUID) (*generated.WalletBalanceResponse, error)
+	// ProcessOperation пополняет кошелёк или списывает с него средства; пустой idempotencyKey
+	// означает запрос без ключа идемпотентности
+	ProcessOperation(ctx context.Context, req generated.WalletOperationRequest, idempotencyKey string) (*generated.WalletOperationResponse, error)
+	ListWallets(ctx context.Context, params generated.ListWalletsParams) (*generated.WalletListResponse, error)
+	ListTransactions(ctx context.Context, walletID uuid.UUID, params generated.ListWalletTransactionsParams) (*generated.TransactionListResponse, error)
+}
+
+// remoteClient выполняет запросы к HTTP API удалённого сервиса
+type remoteClient struct {
+	baseURL string
+	http    *http.Client
+}
+
+// newRemoteClient создаёт клиента HTTP API сервиса по адресу baseURL
+func newRemoteClient(baseURL string) *remoteClient {
+	return &remoteClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// apiError - ответ API с ошибкой
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+func (c *remoteClient) CreateWallet(ctx context.Context, req generated.CreateWalletRequest) (*generated.WalletBalanceResponse, error) {
+	var wallet generated.WalletBalanceResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/wallets", nil, nil, req, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (c *remoteClient) GetWallet(ctx context.Context, walletID uuid.UUID) (*generated.WalletBalanceResponse, error) {
+	var wallet generated.WalletBalanceResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/wallets/"+walletID.String(), nil, nil, nil, &wallet); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (c *remoteClient) ProcessOperation(ctx context.Context, req generated.WalletOperationRequest, idempotencyKey string) (*generated.WalletOperationResponse, error) {
+	header := http.Header{}
+	if idempotencyKey != "" {
+		header.Set("Idempotency-Key", idempotencyKey)
+	}
+	var resp generated.WalletOperationResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/wallet", nil, header, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *remoteClient) ListWallets(ctx context.Context, params generated.ListWalletsParams) (*generated.WalletListResponse, error) {
+	query := url.Values{}
+	setQuery(query, "ownerId", params.OwnerId)
+	setQuery(query, "externalRef", params.ExternalRef)
+	setQuery(query, "status", params.Status)
+	setQuery(query, "currency", params.Currency)
+	setQuery(query, "minBalance", params.MinBalance)
+	setQuery(query, "maxBalance", params.MaxBalance)
+	setQuery(query, "createdFrom", params.CreatedFrom)
+	setQuery(query, "createdTo", params.CreatedTo)
+	setQuery(query, "sort", params.Sort)
+	setQuery(query, "order", params.Order)
+	setQuery(query, "limit", params.Limit)
+	setQuery(query, "cursor", params.Cursor)
+
+	var page generated.WalletListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/wallets", query, nil, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (c *remoteClient) ListTransactions(ctx context.Context, walletID uuid.UUID, params generated.ListWalletTransactionsParams) (*generated.TransactionListResponse, error) {
+	query := url.Values{}
+	setQuery(query, "type", params.Type)
+	setQuery(query, "from", params.From)
+	setQuery(query, "to", params.To)
+	setQuery(query, "limit", params.Limit)
+	setQuery(query, "cursor", params.Cursor)
+
+	var page generated.TransactionListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/wallets/"+walletID.String()+"/transactions", query, nil, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// do отправляет запрос с телом body в JSON и декодирует успешный ответ в out.
+// Ответ с ошибкой возвращается как *apiError с сообщением из тела ответа
+func (c *remoteClient) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr generated.Error
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Message == nil {
+			return &apiError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		return &apiError{StatusCode: resp.StatusCode, Message: *apiErr.Message}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("некорректный ответ API: %w", err)
+	}
+	return nil
+}
+
+// setQuery добавляет в query необязательный параметр запроса
+func setQuery[T any](query url.Values, name string, value *T) {
+	if value == nil {
+		return
+	}
+	switch v := any(*value).(type) {
+	case time.Time:
+		query.Set(name, v.Format(time.RFC3339Nano))
+	case int:
+		query.Set(name, strconv.Itoa(v))
+	case int64:
+		query.Set(name, strconv.FormatInt(v, 10))
+	default:
+		query.Set(name, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/google/uuid"
+)
+
+// listPageSize - размер страницы при выводе всех кошельков и выгрузке
+const listPageSize = 100
+
+// usageError - некорректные аргументы команды
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+// newFlagSet создаёт набор флагов команды; ошибки разбора возвращаются как usageError
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return flags
+}
+
+// parseFlags разбирает флаги и проверяет число позиционных аргументов команды
+func parseFlags(flags *flag.FlagSet, args []string, positional ...string) error {
+	if err := flags.Parse(args); err != nil {
+		return &usageError{message: fmt.Sprintf("%s: %v", flags.Name(), err)}
+	}
+	if flags.NArg() != len(positional) {
+		return &usageError{message: fmt.Sprintf("%s: ожидаются аргументы %v, флаги указываются перед ними", flags.Name(), positional)}
+	}
+	return nil
+}
+
+func runCreate(ctx context.Context, c walletClient, out *printer, args []string) error {
+	flags := newFlagSet("create")
+	currency := flags.String("currency", "", "код валюты ISO 4217, по умолчанию RUB")
+	id := flags.String("id", "", "идентификатор кошелька, по умолчанию генерируется сервером")
+	owner := flags.String("owner", "", "владелец externalRef")
+	externalRef := flags.String("external-ref", "", "идентификатор кошелька во внешней системе")
+	returnExisting := flags.Bool("return-existing", false, "вернуть существующий кошелёк вместо ошибки")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	var req generated.CreateWalletRequest
+	if *currency != "" {
+		req.Currency = currency
+	}
+	if *id != "" {
+		walletID, err := parseWalletID(*id)
+		if err != nil {
+			return err
+		}
+		req.WalletId = &walletID
+	}
+	if *owner != "" {
+		req.OwnerId = owner
+	}
+	if *externalRef != "" {
+		req.ExternalRef = externalRef
+	}
+	if *returnExisting {
+		req.ReturnExisting = returnExisting
+	}
+
+	wallet, err := c.CreateWallet(ctx, req)
+	if err != nil {
+		return err
+	}
+	return out.wallet(wallet)
+}
+
+func runBalance(ctx context.Context, c walletClient, out *printer, args []string) error {
+	flags := newFlagSet("balance")
+	if err := parseFlags(flags, args, "walletId"); err != nil {
+		return err
+	}
+	walletID, err := parseWalletID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	wallet, err := c.GetWallet(ctx, walletID)
+	if err != nil {
+		return err
+	}
+	return out.wallet(wallet)
+}
+
+// operationCommand возвращает команду пополнения или списания
+func operationCommand(name string, opType generated.OperationType) command {
+	return func(ctx context.Context, c walletClient, out *printer, args []string) error {
+		flags := newFlagSet(name)
+		currency := flags.String("currency", "", "код валюты ISO 4217; должен совпадать с валютой кошелька")
+		idempotencyKey := flags.String("idempotency-key", "", "ключ идемпотентности операции")
+		if err := parseFlags(flags, args, "walletId", "amount"); err != nil {
+			return err
+		}
+		walletID, err := parseWalletID(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseInt(flags.Arg(1), 10, 64)
+		if err != nil {
+			return &usageError{message: fmt.Sprintf("%s: сумма должна быть целым числом минимальных единиц валюты", name)}
+		}
+
+		req := generated.WalletOperationRequest{
+			WalletId:      walletID,
+			OperationType: opType,
+			Amount:        amount,
+		}
+		if *currency != "" {
+			req.Currency = currency
+		}
+		resp, err := c.ProcessOperation(ctx, req, *idempotencyKey)
+		if err != nil {
+			return err
+		}
+		return out.operation(resp)
+	}
+}
+
+func runList(ctx context.Context, c walletClient, out *printer, args []string) error {
+	flags := newFlagSet("list")
+	params := walletFilterFlags(flags)
+	all := flags.Bool("all", false, "вывести все страницы списка")
+	limit := flags.Int("limit", 0, "размер страницы, по умолчанию 50")
+	cursor := flags.String("cursor", "", "курсор страницы из предыдущего вывода")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *limit > 0 {
+		params.Limit = limit
+	}
+	if *cursor != "" {
+		params.Cursor = cursor
+	}
+
+	if !*all {
+		page, err := c.ListWallets(ctx, *params)
+		if err != nil {
+			return err
+		}
+		return out.walletPage(page)
+	}
+
+	var wallets []generated.WalletBalanceResponse
+	err := eachWallet(ctx, c, *params, func(wallet generated.WalletBalanceResponse) error {
+		wallets = append(wallets, wallet)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return out.walletPage(&generated.WalletListResponse{Items: wallets})
+}
+
+// walletFilterFlags регистрирует флаги фильтров и сортировки списка кошельков
+func walletFilterFlags(flags *flag.FlagSet) *generated.ListWalletsParams {
+	params := &generated.ListWalletsParams{}
+	optionalString(flags, "owner", "владелец кошельков", &params.OwnerId)
+	optionalString(flags, "external-ref", "идентификатор кошелька во внешней системе", &params.ExternalRef)
+	optionalString(flags, "currency", "код валюты ISO 4217", &params.Currency)
+	flags.Func("status", "статус кошельков: ACTIVE, FROZEN или CLOSED", func(s string) error {
+		status := generated.WalletStatus(s)
+		params.Status = &status
+		return nil
+	})
+	flags.Func("sort", "поле сортировки: createdAt или balance", func(s string) error {
+		sort := generated.ListWalletsParamsSort(s)
+		params.Sort = &sort
+		return nil
+	})
+	flags.BoolFunc("desc", "обратный порядок сортировки", func(s string) error {
+		desc, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		order := generated.Asc
+		if desc {
+			order = generated.Desc
+		}
+		params.Order = &order
+		return nil
+	})
+	return params
+}
+
+// eachWallet обходит все страницы списка кошельков
+func eachWallet(ctx context.Context, c walletClient, params generated.ListWalletsParams, fn func(generated.WalletBalanceResponse) error) error {
+	limit := listPageSize
+	params.Limit = &limit
+	for {
+		page, err := c.ListWallets(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, wallet := range page.Items {
+			if err := fn(wallet); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == nil {
+			return nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
+
+// optionalString регистрирует строковый флаг необязательного параметра: без флага target остаётся nil
+func optionalString(flags *flag.FlagSet, name, usage string, target **string) {
+	flags.Func(name, usage, func(s string) error {
+		*target = &s
+		return nil
+	})
+}
+
+// parseWalletID разбирает идентификатор кошелька из аргументов команды
+func parseWalletID(value string) (uuid.UUID, error) {
+	walletID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, &usageError{message: fmt.Sprintf("некорректный идентификатор кошелька %q", value)}
+	}
+	return walletID, nil
+}
+
+// isUsageError сообщает, что ошибка вызвана некорректными аргументами команды
+func isUsageError(err error) bool {
+	var usageErr *usageError
+	return errors.As(err, &usageErr)
+}
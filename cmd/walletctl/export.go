@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/google/uuid"
+)
+
+// exportedWallet - строка выгрузки: кошелёк и, с флагом -transactions, его история операций
+type exportedWallet struct {
+	generated.WalletBalanceResponse
+	Transactions *[]generated.Transaction `json:"transactions,omitempty"`
+}
+
+// runExport выгружает кошельки в NDJSON, по одному кошельку в строке, независимо от формата вывода.
+// Кошельки выбираются фильтрами списка или флагом -wallet
+func runExport(ctx context.Context, c walletClient, _ *printer, args []string) error {
+	flags := newFlagSet("export")
+	params := walletFilterFlags(flags)
+	walletFlag := flags.String("wallet", "", "выгрузить только этот кошелёк")
+	withTransactions := flags.Bool("transactions", false, "добавить историю операций кошельков")
+	var txParams generated.ListWalletTransactionsParams
+	timeFlag(flags, "from", "начало интервала истории операций (включительно), RFC 3339", &txParams.From)
+	timeFlag(flags, "to", "конец интервала истории операций (не включительно), RFC 3339", &txParams.To)
+	file := flags.String("file", "", "файл выгрузки, по умолчанию стандартный вывод")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	encoder := json.NewEncoder(w)
+
+	write := func(wallet generated.WalletBalanceResponse) error {
+		row := exportedWallet{WalletBalanceResponse: wallet}
+		if *withTransactions && wallet.WalletId != nil {
+			transactions, err := walletTransactions(ctx, c, *wallet.WalletId, txParams)
+			if err != nil {
+				return err
+			}
+			row.Transactions = &transactions
+		}
+		return encoder.Encode(row)
+	}
+
+	if *walletFlag != "" {
+		walletID, err := parseWalletID(*walletFlag)
+		if err != nil {
+			return err
+		}
+		wallet, err := c.GetWallet(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		return write(*wallet)
+	}
+	return eachWallet(ctx, c, *params, write)
+}
+
+// walletTransactions возвращает все операции кошелька по фильтрам params
+func walletTransactions(ctx context.Context, c walletClient, walletID uuid.UUID, params generated.ListWalletTransactionsParams) ([]generated.Transaction, error) {
+	transactions := []generated.Transaction{}
+	limit := listPageSize
+	params.Limit = &limit
+	for {
+		page, err := c.ListTransactions(ctx, walletID, params)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, page.Items...)
+		if page.NextCursor == nil {
+			return transactions, nil
+		}
+		params.Cursor = page.NextCursor
+	}
+}
+
+// timeFlag регистрирует флаг времени в формате RFC 3339: без флага target остаётся nil
+func timeFlag(flags *flag.FlagSet, name, usage string, target **time.Time) {
+	flags.Func(name, usage, func(s string) error {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return err
+		}
+		*target = &t
+		return nil
+	})
+}
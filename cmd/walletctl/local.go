@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+
+	"github.com/devopesik/wallet-basic-operations/internal/apimodel"
+	"github.com/devopesik/wallet-basic-operations/internal/config"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// localClient выполняет команды через сервис кошельков в этом процессе напрямую с базой данных.
+// Запросы и ответы - модели HTTP API: проверки сервиса, ключи идемпотентности и вывод команд
+// те же, что при работе через запущенный сервис
+type localClient struct {
+	wallets service.WalletService
+}
+
+// newLocalClient создаёт клиента для работы с базой данных из cfg. Пул закрывается вызывающим
+func newLocalClient(cfg *config.Config) (*localClient, *pgxpool.Pool, error) {
+	pool, err := postgres.NewPool(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	repo := postgres.NewWalletRepository(pool, postgres.NewRetryPolicy(cfg))
+	return &localClient{wallets: service.NewWalletService(repo)}, pool, nil
+}
+
+func (c *localClient) CreateWallet(ctx context.Context, req generated.CreateWalletRequest) (*generated.WalletBalanceResponse, error) {
+	params := repository.NewWallet{}
+	if req.Currency != nil {
+		params.Currency = *req.Currency
+	}
+	if req.WalletId != nil {
+		if *req.WalletId == uuid.Nil {
+			return nil, apperrors.ErrInvalidWalletID
+		}
+		params.ID = *req.WalletId
+	}
+	if req.OwnerId != nil {
+		params.OwnerID = *req.OwnerId
+	}
+	if req.ExternalRef != nil {
+		if *req.ExternalRef == "" {
+			return nil, apperrors.ErrInvalidExternalRef
+		}
+		params.ExternalRef = *req.ExternalRef
+	}
+
+	var (
+		wallet *repository.Wallet
+		err    error
+	)
+	if req.ReturnExisting != nil && *req.ReturnExisting {
+		wallet, _, err = c.wallets.GetOrCreateWallet(ctx, params)
+	} else {
+		wallet, err = c.wallets.CreateWallet(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp := apimodel.WalletBalanceResponse(wallet)
+	return &resp, nil
+}
+
+func (c *localClient) GetWallet(ctx context.Context, walletID uuid.UUID) (*generated.WalletBalanceResponse, error) {
+	wallet, err := c.wallets.GetWallet(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	resp := apimodel.WalletBalanceResponse(wallet)
+	return &resp, nil
+}
+
+func (c *localClient) ProcessOperation(ctx context.Context, req generated.WalletOperationRequest, idempotencyKey string) (*generated.WalletOperationResponse, error) {
+	op := repository.Operation{
+		WalletID: req.WalletId,
+		Amount:   req.Amount,
+	}
+	if req.Currency != nil {
+		op.Currency = *req.Currency
+	}
+	if idempotencyKey != "" {
+		key, err := apimodel.NewIdempotencyKey(idempotencyKey, req)
+		if err != nil {
+			return nil, err
+		}
+		op.IdempotencyKey = key
+	}
+
+	var (
+		entry *repository.Transaction
+		err   error
+	)
+	switch req.OperationType {
+	case generated.OperationTypeDEPOSIT:
+		entry, err = c.wallets.Deposit(ctx, op)
+	case generated.OperationTypeWITHDRAW:
+		entry, err = c.wallets.Withdraw(ctx, op)
+	default:
+		return nil, apperrors.ErrInvalidOperationType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := apimodel.WalletOperationResponse(entry, req)
+	return &resp, nil
+}
+
+func (c *localClient) ListWallets(ctx context.Context, params generated.ListWalletsParams) (*generated.WalletListResponse, error) {
+	query := service.WalletQuery{
+		OwnerID:     params.OwnerId,
+		ExternalRef: params.ExternalRef,
+		MinBalance:  params.MinBalance,
+		MaxBalance:  params.MaxBalance,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		// Как в HTTP API, по умолчанию сначала новые кошельки
+		Descending: true,
+	}
+	if params.Status != nil {
+		status := repository.WalletStatus(*params.Status)
+		query.Status = &status
+	}
+	if params.Currency != nil {
+		query.Currency = *params.Currency
+	}
+	if params.Sort != nil {
+		switch *params.Sort {
+		case generated.CreatedAt:
+			query.Sort = repository.WalletSortCreatedAt
+		case generated.Balance:
+			query.Sort = repository.WalletSortBalance
+		default:
+			return nil, apperrors.ErrInvalidSort
+		}
+	}
+	if params.Order != nil {
+		switch *params.Order {
+		case generated.Asc:
+			query.Descending = false
+		case generated.Desc:
+			query.Descending = true
+		default:
+			return nil, apperrors.ErrInvalidSort
+		}
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			return nil, apperrors.ErrInvalidLimit
+		}
+		query.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		query.Cursor = *params.Cursor
+	}
+
+	page, err := c.wallets.ListWallets(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	resp := &generated.WalletListResponse{
+		Items: make([]generated.WalletBalanceResponse, 0, len(page.Items)),
+	}
+	for i := range page.Items {
+		resp.Items = append(resp.Items, apimodel.WalletBalanceResponse(&page.Items[i]))
+	}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
+	}
+	return resp, nil
+}
+
+func (c *localClient) ListTransactions(ctx context.Context, walletID uuid.UUID, params generated.ListWalletTransactionsParams) (*generated.TransactionListResponse, error) {
+	query := service.TransactionQuery{
+		From: params.From,
+		To:   params.To,
+	}
+	if params.Type != nil {
+		txType := repository.TransactionType(*params.Type)
+		query.Type = &txType
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			return nil, apperrors.ErrInvalidLimit
+		}
+		query.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		query.Cursor = *params.Cursor
+	}
+
+	page, err := c.wallets.ListTransactions(ctx, walletID, query)
+	if err != nil {
+		return nil, err
+	}
+	resp := &generated.TransactionListResponse{
+		Items: make([]generated.Transaction, 0, len(page.Items)),
+	}
+	for _, t := range page.Items {
+		resp.Items = append(resp.Items, apimodel.TransactionResponse(t))
+	}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
+	}
+	return resp, nil
+}
@@ -0,0 +1,143 @@
+// walletctl - утилита администрирования кошельков. Команды выполняются через HTTP API запущенного
+// сервиса (флаг -api или переменная WALLETCTL_API) или напрямую с базой данных из файла конфигурации.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/devopesik/wallet-basic-operations/internal/config"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+)
+
+// Коды завершения
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `Использование: walletctl [флаги] <команда> [флаги команды] [аргументы]
+
+Команды:
+  create     [-currency RUB] [-id UUID] [-owner ID] [-external-ref REF] [-return-existing]
+  balance    <walletId>
+  deposit    [-currency RUB] [-idempotency-key KEY] <walletId> <amount>
+  withdraw   [-currency RUB] [-idempotency-key KEY] <walletId> <amount>
+  list       [фильтры] [-limit N] [-cursor CURSOR] [-all]
+  export     [фильтры] [-wallet UUID] [-transactions] [-from RFC3339] [-to RFC3339] [-file PATH]
+  migrate    up|down|status|redo
+
+Фильтры list и export: -owner, -external-ref, -status, -currency, -sort createdAt|balance, -desc.
+Суммы указываются в минимальных единицах валюты. export всегда выводит NDJSON.
+migrate работает только напрямую с базой данных.
+
+Флаги:
+`
+
+// command выполняет команду с аргументами args
+type command func(ctx context.Context, c walletClient, out *printer, args []string) error
+
+var commands = map[string]command{
+	"create":   runCreate,
+	"balance":  runBalance,
+	"deposit":  operationCommand("deposit", generated.OperationTypeDEPOSIT),
+	"withdraw": operationCommand("withdraw", generated.OperationTypeWITHDRAW),
+	"list":     runList,
+	"export":   runExport,
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("walletctl: ")
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("walletctl", flag.ContinueOnError)
+	apiURL := flags.String("api", os.Getenv("WALLETCTL_API"), "адрес HTTP API, например http://localhost:8080; без него команды выполняются напрямую с БД")
+	cfgPath := flags.String("config", "config.env", "файл конфигурации для работы напрямую с БД")
+	format := flags.String("o", formatTable, "формат вывода: table или json")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	out, err := newPrinter(os.Stdout, *format)
+	if err != nil {
+		log.Print(err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	name, cmdArgs := flags.Arg(0), flags.Args()[1:]
+	if name == "migrate" {
+		if *apiURL != "" {
+			log.Print("migrate работает только напрямую с БД, флаг -api не поддерживается")
+			return exitUsage
+		}
+		return exitCode(runMigrate(ctx, config.Load(*cfgPath), cmdArgs))
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		log.Printf("неизвестная команда %q", name)
+		flags.Usage()
+		return exitUsage
+	}
+
+	var c walletClient
+	if *apiURL != "" {
+		c = newRemoteClient(*apiURL)
+	} else {
+		local, pool, err := newLocalClient(config.Load(*cfgPath))
+		if err != nil {
+			log.Printf("не удалось подключиться к БД: %v", err)
+			return exitError
+		}
+		defer pool.Close()
+		c = local
+	}
+	return exitCode(cmd(ctx, c, out, cmdArgs))
+}
+
+// runMigrate выполняет команду миграций goose
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return &usageError{message: "migrate: ожидается одна из команд up, down, status, redo"}
+	}
+	switch args[0] {
+	case postgres.MigrateUp, postgres.MigrateDown, postgres.MigrateStatus, postgres.MigrateRedo:
+		return postgres.Migrate(ctx, cfg, args[0])
+	default:
+		return &usageError{message: fmt.Sprintf("migrate: неизвестная команда %q, доступные команды: up, down, status, redo", args[0])}
+	}
+}
+
+// exitCode выводит ошибку команды и возвращает код завершения
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case isUsageError(err):
+		log.Print(err)
+		return exitUsage
+	default:
+		log.Print(err)
+		return exitError
+	}
+}
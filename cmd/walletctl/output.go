@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+)
+
+// Форматы вывода команд
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// printer выводит результаты команд таблицей или в JSON. JSON совпадает с ответами HTTP API
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != formatTable && format != formatJSON {
+		return nil, fmt.Errorf("неизвестный формат вывода %q, доступные форматы: table, json", format)
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+// wallet выводит один кошелёк
+func (p *printer) wallet(wallet *generated.WalletBalanceResponse) error {
+	if p.format == formatJSON {
+		return p.json(wallet)
+	}
+	return p.walletTable([]generated.WalletBalanceResponse{*wallet})
+}
+
+// walletPage выводит страницу списка кошельков
+func (p *printer) walletPage(page *generated.WalletListResponse) error {
+	if p.format == formatJSON {
+		return p.json(page)
+	}
+	if err := p.walletTable(page.Items); err != nil {
+		return err
+	}
+	if page.NextCursor != nil {
+		_, err := fmt.Fprintf(p.w, "\nСледующая страница: -cursor %s\n", *page.NextCursor)
+		return err
+	}
+	return nil
+}
+
+// operation выводит результат пополнения или списания
+func (p *printer) operation(op *generated.WalletOperationResponse) error {
+	if p.format == formatJSON {
+		return p.json(op)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OPERATION\tWALLET\tTYPE\tAMOUNT\tBALANCE\tCREATED")
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n", op.OperationId, op.WalletId, op.OperationType,
+		op.Amount, op.Balance, op.CreatedAt.Format(time.RFC3339))
+	return tw.Flush()
+}
+
+func (p *printer) walletTable(wallets []generated.WalletBalanceResponse) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "WALLET\tBALANCE\tAVAILABLE\tCURRENCY\tSTATUS\tOWNER\tEXTERNAL REF\tVERSION\tCREATED")
+	for _, w := range wallets {
+		var created string
+		if w.CreatedAt != nil {
+			created = w.CreatedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			value(w.WalletId), formatAmount(w.Balance, w.MinorUnits), formatAmount(w.AvailableBalance, w.MinorUnits),
+			value(w.Currency), value(w.Status), value(w.OwnerId), value(w.ExternalRef), value(w.Version), created)
+	}
+	return tw.Flush()
+}
+
+func (p *printer) json(v any) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// formatAmount выводит сумму в минимальных единицах в единицах валюты: 12345 с двумя
+// знаками дробной части - 123.45. Без сведений о валюте сумма выводится как есть
+func formatAmount(amount *int64, minorUnits *int) string {
+	if amount == nil {
+		return ""
+	}
+	if minorUnits == nil || *minorUnits == 0 {
+		return strconv.FormatInt(*amount, 10)
+	}
+
+	digits := strconv.FormatInt(*amount, 10)
+	sign := ""
+	if *amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	exp := *minorUnits
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// value выводит необязательное поле ответа; отсутствующее поле - пустая строка
+func value[T any](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
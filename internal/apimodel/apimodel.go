@@ -0,0 +1,86 @@
+// Package apimodel содержит общее для HTTP API и его клиентов: проверку ключа идемпотентности
+// с отпечатком запроса и конвертацию моделей репозитория в модели ответов API.
+// Через него walletctl без -api отвечает и считает отпечатки так же, как HTTP API.
+package apimodel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/devopesik/wallet-basic-operations/internal/currency"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+// MaxIdempotencyKeyLength - максимальная длина заголовка Idempotency-Key
+const MaxIdempotencyKeyLength = 255
+
+// NewIdempotencyKey валидирует ключ идемпотентности и вычисляет отпечаток запроса.
+// Отпечаток считается по декодированному запросу, поэтому не зависит от форматирования JSON.
+func NewIdempotencyKey(key string, req any) (*repository.IdempotencyKey, error) {
+	if len(key) == 0 || len(key) > MaxIdempotencyKeyLength {
+		return nil, apperrors.ErrInvalidIdempotencyKey
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, apperrors.ErrInvalidJSON
+	}
+	sum := sha256.Sum256(data)
+	return &repository.IdempotencyKey{
+		Key:         key,
+		Fingerprint: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// WalletBalanceResponse конвертирует кошелёк в модель ответа API
+func WalletBalanceResponse(wallet *repository.Wallet) generated.WalletBalanceResponse {
+	available := wallet.Available()
+	creditUsed := wallet.CreditUsed()
+	status := generated.WalletStatus(wallet.Status)
+	resp := generated.WalletBalanceResponse{
+		WalletId:         &wallet.ID,
+		Balance:          &wallet.Balance,
+		AvailableBalance: &available,
+		CreditLimit:      &wallet.CreditLimit,
+		CreditUsed:       &creditUsed,
+		Currency:         &wallet.Currency,
+		Status:           &status,
+		ExternalRef:      wallet.ExternalRef,
+		CreatedAt:        &wallet.CreatedAt,
+		Version:          &wallet.Version,
+	}
+	if wallet.OwnerID != "" {
+		resp.OwnerId = &wallet.OwnerID
+	}
+	if c, ok := currency.Lookup(wallet.Currency); ok {
+		resp.MinorUnits = &c.Exponent
+	}
+	return resp
+}
+
+// WalletOperationResponse конвертирует запись истории пополнения или списания в модель ответа API
+func WalletOperationResponse(entry *repository.Transaction, req generated.WalletOperationRequest) generated.WalletOperationResponse {
+	return generated.WalletOperationResponse{
+		OperationId:   entry.ID,
+		WalletId:      entry.WalletID,
+		OperationType: req.OperationType,
+		Amount:        req.Amount,
+		Balance:       entry.BalanceAfter,
+		CreatedAt:     entry.CreatedAt,
+	}
+}
+
+// TransactionResponse конвертирует запись истории в модель ответа API
+func TransactionResponse(t repository.Transaction) generated.Transaction {
+	return generated.Transaction{
+		Id:                   t.ID,
+		WalletId:             t.WalletID,
+		Type:                 generated.TransactionType(t.Type),
+		Amount:               t.Amount,
+		BalanceAfter:         t.BalanceAfter,
+		CounterpartyWalletId: t.CounterpartyWalletID,
+		CreatedAt:            t.CreatedAt,
+	}
+}
@@ -2,43 +2,107 @@ package app
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/devopesik/wallet-basic-operations/internal/config"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
 	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/generated/walletpb"
+	"github.com/devopesik/wallet-basic-operations/internal/groupcommit"
+	"github.com/devopesik/wallet-basic-operations/internal/grpcapi"
 	"github.com/devopesik/wallet-basic-operations/internal/handlers"
 	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
 	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/devopesik/wallet-basic-operations/internal/webhook"
+	"github.com/devopesik/wallet-basic-operations/internal/worker"
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 )
 
-// App представляет приложение с сервером и пулом БД
+// holdExpiryBatchSize - максимальное число блокировок, снимаемых за один запуск задачи
+const holdExpiryBatchSize = 1000
+
+// balanceSnapshotLag - отставание момента снимка балансов от текущего времени. Запись истории
+// получает время начала транзакции, поэтому снимок ждёт фиксации транзакций, начатых до него.
+const balanceSnapshotLag = 5 * time.Minute
+
+// outboxCleanupInterval - период удаления доставленных событий из outbox
+const outboxCleanupInterval = time.Hour
+
+// App представляет приложение с HTTP и gRPC серверами, пулом БД и фоновыми задачами
 type App struct {
-	Server *http.Server
-	Pool   *pgxpool.Pool
+	Server     *http.Server
+	GRPCServer *grpc.Server
+	Pool       *pgxpool.Pool
+
+	grpcAPI   *grpcapi.Server
+	publisher events.EventPublisher
+	stopJobs  context.CancelFunc
+	jobs      sync.WaitGroup
 }
 
-// StartServer создает и запускает HTTP сервер
+// StartServer создает и запускает HTTP и gRPC серверы
 func StartServer(cfg *config.Config) (*App, error) {
 	if err := postgres.RunMigrations(cfg); err != nil {
 		return nil, err
 	}
 
+	publisher, err := newEventPublisher(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	pool, err := postgres.NewPool(cfg)
 	if err != nil {
+		closePublisher(publisher)
 		return nil, err
 	}
 
-	repo := postgres.NewWalletRepository(pool)
+	txRetry := postgres.NewRetryPolicy(cfg)
+	repo := postgres.NewWalletRepository(pool, txRetry)
+	if cfg.DepositCoalesceWindow != 0 {
+		coalescer, err := groupcommit.NewDepositCoalescer(repo, cfg.DepositCoalesceWindow, cfg.DepositCoalesceMaxBatch, cfg.DepositCoalesceFlushTimeout)
+		if err != nil {
+			pool.Close()
+			closePublisher(publisher)
+			return nil, err
+		}
+		repo = coalescer
+	}
 	svc := service.NewWalletService(repo)
-	hdl := handler.NewWalletHandler(svc)
+	holdRepo := postgres.NewHoldRepository(pool, txRetry)
+	holdSvc := service.NewHoldService(holdRepo, cfg.HoldDefaultTTL, cfg.HoldMaxTTL)
+	batchSvc := service.NewBatchService(repo, cfg.BatchMaxSize)
+	reconciliationSvc := service.NewReconciliationService(postgres.NewReconciliationRepository(pool, txRetry))
+	webhookRepo := postgres.NewWebhookRepository(pool, txRetry)
+	webhookSvc := service.NewWebhookService(webhookRepo)
+	limitSvc := service.NewLimitService(postgres.NewLimitRepository(pool, txRetry))
+	hdl := handler.NewWalletHandler(svc, holdSvc, batchSvc, reconciliationSvc, webhookSvc, limitSvc)
 
 	r := chi.NewRouter()
 	generated.HandlerFromMux(hdl, r)
+	// Счётчики приложения в формате expvar
+	r.Method(http.MethodGet, "/debug/vars", expvar.Handler())
+
+	// Порт gRPC занимается до запуска HTTP сервера, чтобы ошибка вернулась вызывающему
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		pool.Close()
+		closePublisher(publisher)
+		return nil, fmt.Errorf("не удалось открыть порт gRPC: %w", err)
+	}
+	grpcAPI := grpcapi.NewServer(svc, cfg.GRPCWatchInterval)
+	grpcServer := grpc.NewServer()
+	walletpb.RegisterWalletServiceServer(grpcServer, grpcAPI)
 
 	server := &http.Server{
 		Addr:         ":" + cfg.ServerPort,
@@ -58,24 +122,185 @@ func StartServer(cfg *config.Config) (*App, error) {
 			log.Fatalf("Сервер завершил работу с ошибкой: %v", err)
 		}
 	}()
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("gRPC сервер завершил работу с ошибкой: %v", err)
+		}
+	}()
+
+	application := &App{
+		Server:     server,
+		GRPCServer: grpcServer,
+		Pool:       pool,
+		grpcAPI:    grpcAPI,
+		publisher:  publisher,
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	application.stopJobs = stopJobs
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "очистка ключей идемпотентности", cfg.IdempotencyCleanupInterval, func(ctx context.Context) error {
+			deleted, err := repo.DeleteExpiredIdempotencyKeys(ctx, time.Now().Add(-cfg.IdempotencyKeyTTL))
+			if deleted > 0 {
+				log.Printf("Удалено устаревших ключей идемпотентности: %d", deleted)
+			}
+			return err
+		})
+	})
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "снятие истёкших блокировок средств", cfg.HoldExpiryInterval, func(ctx context.Context) error {
+			expired, err := holdRepo.ExpireHolds(ctx, time.Now(), holdExpiryBatchSize)
+			if expired > 0 {
+				log.Printf("Снято истёкших блокировок средств: %d", expired)
+			}
+			return err
+		})
+	})
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "снятие снимков балансов", cfg.BalanceSnapshotInterval, func(ctx context.Context) error {
+			taken, err := repo.SnapshotBalances(ctx, time.Now().Add(-balanceSnapshotLag))
+			if taken > 0 {
+				log.Printf("Снято снимков балансов: %d", taken)
+			}
+			return err
+		})
+	})
+	// События из outbox всегда передаются подписчикам вебхуков и, если он настроен, внешнему публикатору
+	relayPublisher := events.MultiPublisher{webhook.NewPublisher(webhookRepo)}
+	if publisher != nil {
+		relayPublisher = append(relayPublisher, publisher)
+	}
+	outboxRepo := postgres.NewOutboxRepository(pool, txRetry)
+	relay := events.NewRelay(outboxRepo, relayPublisher, cfg.OutboxBatchSize)
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "доставка событий из outbox", cfg.OutboxRelayInterval, func(ctx context.Context) error {
+			_, err := relay.RelayPending(ctx)
+			return err
+		})
+	})
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "очистка доставленных событий", outboxCleanupInterval, func(ctx context.Context) error {
+			deleted, err := outboxRepo.DeletePublishedOutboxEvents(ctx, time.Now().Add(-cfg.OutboxRetention))
+			if deleted > 0 {
+				log.Printf("Удалено доставленных событий: %d", deleted)
+			}
+			return err
+		})
+	})
+	dispatcher := webhook.NewDispatcher(webhookRepo, webhook.RetryPolicy{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BaseDelay:   cfg.WebhookRetryBaseDelay,
+		MaxDelay:    cfg.WebhookRetryMaxDelay,
+	}, cfg.WebhookTimeout, cfg.WebhookBatchSize)
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "отправка событий подписчикам", cfg.WebhookDispatchInterval, func(ctx context.Context) error {
+			_, err := dispatcher.DispatchDue(ctx)
+			return err
+		})
+	})
+	application.runJob(func() {
+		worker.RunPeriodic(jobsCtx, "сверка балансов с журналом", cfg.ReconciliationInterval, func(ctx context.Context) error {
+			run, err := reconciliationSvc.Reconcile(ctx, cfg.ReconciliationAutoCorrect)
+			if err != nil {
+				return err
+			}
+			if len(run.Drifts) > 0 {
+				log.Printf("Сверка балансов: расхождений %d из %d кошельков", len(run.Drifts), run.WalletsChecked)
+			}
+			return nil
+		})
+	})
 
-	return &App{
-		Server: server,
-		Pool:   pool,
-	}, nil
+	return application, nil
 }
 
-// Shutdown корректно останавливает сервер и закрывает пул БД
+// newEventPublisher создаёт публикатор доменных событий по конфигурации.
+// Для EVENT_PUBLISHER=none возвращает nil: события доставляются только подписчикам вебхуков.
+// Незаданный публикатор - ошибка: доставленное событие отмечается в outbox и больше не отправляется,
+// поэтому с публикатором по умолчанию забытая настройка незаметно теряла бы события.
+func newEventPublisher(cfg *config.Config) (events.EventPublisher, error) {
+	switch cfg.EventPublisher {
+	case "":
+		return nil, errors.New("не задан EVENT_PUBLISHER: укажите stdout, file, http или none")
+	case "stdout":
+		return events.NewStdoutPublisher(), nil
+	case "file":
+		return events.NewFilePublisher(cfg.EventFilePath)
+	case "http":
+		if cfg.EventHTTPURL == "" {
+			return nil, errors.New("для EVENT_PUBLISHER=http необходимо задать EVENT_HTTP_URL")
+		}
+		return events.NewHTTPPublisher(cfg.EventHTTPURL, cfg.EventHTTPTimeout), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("неизвестный публикатор событий %q", cfg.EventPublisher)
+	}
+}
+
+// closePublisher освобождает ресурсы публикатора, если они у него есть
+func closePublisher(publisher events.EventPublisher) {
+	if closer, ok := publisher.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("ошибка при закрытии публикатора событий: %v", err)
+		}
+	}
+}
+
+// runJob запускает фоновую задачу, завершения которой дожидается Shutdown
+func (a *App) runJob(job func()) {
+	a.jobs.Add(1)
+	go func() {
+		defer a.jobs.Done()
+		job()
+	}()
+}
+
+// Shutdown корректно останавливает серверы, фоновые задачи и закрывает пул БД
 func (a *App) Shutdown(ctx context.Context) error {
+	if a.GRPCServer != nil {
+		if err := a.stopGRPC(ctx); err != nil {
+			return err
+		}
+	}
+
 	if a.Server != nil {
 		if err := a.Server.Shutdown(ctx); err != nil {
 			return err
 		}
 	}
 
+	if a.stopJobs != nil {
+		a.stopJobs()
+		a.jobs.Wait()
+	}
+
+	// Публикатор закрывается после остановки задачи доставки событий
+	closePublisher(a.publisher)
+
 	if a.Pool != nil {
 		a.Pool.Close()
 	}
 
 	return nil
 }
+
+// stopGRPC завершает потоки WatchBalance и дожидается выполняющихся вызовов gRPC.
+// Если ctx истекает раньше, оставшиеся вызовы прерываются.
+func (a *App) stopGRPC(ctx context.Context) error {
+	a.grpcAPI.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		a.GRPCServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		a.GRPCServer.Stop()
+		return fmt.Errorf("остановка gRPC сервера: %w", ctx.Err())
+	}
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Config struct {
 	DBHost         string `env:"DB_HOST" envDefault:"localhost"`
 	DBPort         string `env:"DB_PORT" envDefault:"5432"`
@@ -8,4 +10,58 @@ type Config struct {
 	DBName         string `env:"DB_NAME,required"`
 	ServerPort     string `env:"SERVER_PORT" envDefault:"8080"`
 	MigrationsPath string `env:"MIGRATIONS_PATH" envDefault:"migrations"`
+
+	// Порт gRPC API и период опроса кошелька в потоках WatchBalance
+	GRPCPort          string        `env:"GRPC_PORT" envDefault:"9090"`
+	GRPCWatchInterval time.Duration `env:"GRPC_WATCH_INTERVAL" envDefault:"1s"`
+
+	// Срок хранения ключей идемпотентности и период их очистки
+	IdempotencyKeyTTL          time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+	IdempotencyCleanupInterval time.Duration `env:"IDEMPOTENCY_CLEANUP_INTERVAL" envDefault:"1h"`
+
+	// Срок действия блокировок средств и период снятия истёкших блокировок
+	HoldDefaultTTL     time.Duration `env:"HOLD_DEFAULT_TTL" envDefault:"15m"`
+	HoldMaxTTL         time.Duration `env:"HOLD_MAX_TTL" envDefault:"168h"`
+	HoldExpiryInterval time.Duration `env:"HOLD_EXPIRY_INTERVAL" envDefault:"1m"`
+
+	// Максимальное число операций в пакетном запросе
+	BatchMaxSize int `env:"BATCH_MAX_SIZE" envDefault:"1000"`
+
+	// Период снятия снимков балансов для запросов баланса на момент времени
+	BalanceSnapshotInterval time.Duration `env:"BALANCE_SNAPSHOT_INTERVAL" envDefault:"1h"`
+
+	// Доставка доменных событий из outbox: публикатор (stdout, file, http или none),
+	// его параметры, период и размер пакета доставки, срок хранения доставленных событий.
+	// Публикатор не имеет значения по умолчанию: без явного выбора сервер не запускается
+	EventPublisher      string        `env:"EVENT_PUBLISHER"`
+	EventFilePath       string        `env:"EVENT_FILE_PATH" envDefault:"events.ndjson"`
+	EventHTTPURL        string        `env:"EVENT_HTTP_URL"`
+	EventHTTPTimeout    time.Duration `env:"EVENT_HTTP_TIMEOUT" envDefault:"5s"`
+	OutboxRelayInterval time.Duration `env:"OUTBOX_RELAY_INTERVAL" envDefault:"1s"`
+	OutboxBatchSize     int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	OutboxRetention     time.Duration `env:"OUTBOX_RETENTION" envDefault:"168h"`
+
+	// Доставка событий подписчикам: период и размер пакета отправки, таймаут запроса,
+	// число попыток до перевода доставки в DEAD и границы экспоненциальной задержки между попытками
+	WebhookDispatchInterval time.Duration `env:"WEBHOOK_DISPATCH_INTERVAL" envDefault:"1s"`
+	WebhookBatchSize        int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"100"`
+	WebhookTimeout          time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"10s"`
+	WebhookMaxAttempts      int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"10"`
+	WebhookRetryBaseDelay   time.Duration `env:"WEBHOOK_RETRY_BASE_DELAY" envDefault:"10s"`
+	WebhookRetryMaxDelay    time.Duration `env:"WEBHOOK_RETRY_MAX_DELAY" envDefault:"1h"`
+
+	// Повторы транзакций, прерванных ошибкой сериализации или взаимоблокировкой: число попыток,
+	// включая первую, и границы экспоненциальной задержки со случайным разбросом между ними
+	TxRetryMaxAttempts int           `env:"TX_RETRY_MAX_ATTEMPTS" envDefault:"5"`
+	TxRetryBaseDelay   time.Duration `env:"TX_RETRY_BASE_DELAY" envDefault:"10ms"`
+	TxRetryMaxDelay    time.Duration `env:"TX_RETRY_MAX_DELAY" envDefault:"500ms"`
+	// Группировка пополнений одного кошелька: окно сбора, максимальный размер группы и срок
+	// применения группы. Нулевое окно выключает группировку, каждое пополнение выполняется своей транзакцией.
+	DepositCoalesceWindow       time.Duration `env:"DEPOSIT_COALESCE_WINDOW" envDefault:"0"`
+	DepositCoalesceMaxBatch     int           `env:"DEPOSIT_COALESCE_MAX_BATCH" envDefault:"100"`
+	DepositCoalesceFlushTimeout time.Duration `env:"DEPOSIT_COALESCE_FLUSH_TIMEOUT" envDefault:"10s"`
+
+	// Период сверки балансов с журналом и автоматическая корректировка расхождений
+	ReconciliationInterval    time.Duration `env:"RECONCILIATION_INTERVAL" envDefault:"1h"`
+	ReconciliationAutoCorrect bool          `env:"RECONCILIATION_AUTO_CORRECT" envDefault:"false"`
 }
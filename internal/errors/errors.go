@@ -11,6 +11,7 @@ type AppError struct {
 	Message    string // Сообщение ошибки
 	Err        error  // Оригинальная ошибка (опционально)
 	StatusCode int    // HTTP статус код для ответа
+	Details    any    // Дополнительные данные для тела ответа (опционально)
 }
 
 // Error реализует интерфейс error
@@ -26,6 +27,13 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
+// Is считает равными ошибки с одним кодом, поэтому errors.Is находит ошибки,
+// созданные конструкторами с контекстом, по их базовым значениям
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
 // HTTPStatus возвращает HTTP статус код ошибки
 func (e *AppError) HTTPStatus() int {
 	return e.StatusCode
@@ -80,6 +88,286 @@ var ErrInvalidWalletID = &AppError{
 	StatusCode: http.StatusBadRequest,
 }
 
+// ErrInvalidCursor - некорректный курсор пагинации
+var ErrInvalidCursor = &AppError{
+	Code:       ErrorCodeInvalidCursor,
+	Message:    "некорректный cursor",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidLimit - некорректный размер страницы
+var ErrInvalidLimit = &AppError{
+	Code:       ErrorCodeInvalidLimit,
+	Message:    "limit должен быть в диапазоне от 1 до 100",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidTimeRange - некорректный временной интервал
+var ErrInvalidTimeRange = &AppError{
+	Code:       ErrorCodeInvalidTimeRange,
+	Message:    "начало интервала должно быть раньше его конца",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidIdempotencyKey - некорректный ключ идемпотентности
+var ErrInvalidIdempotencyKey = &AppError{
+	Code:       ErrorCodeInvalidIdempotencyKey,
+	Message:    "Idempotency-Key должен содержать от 1 до 255 символов",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrIdempotencyKeyMismatch - ключ идемпотентности использован с другим запросом
+var ErrIdempotencyKeyMismatch = &AppError{
+	Code:       ErrorCodeIdempotencyKeyMismatch,
+	Message:    "Idempotency-Key уже использован с другим телом запроса",
+	StatusCode: http.StatusUnprocessableEntity,
+}
+
+// ErrSameWallet - перевод на тот же кошелёк
+var ErrSameWallet = &AppError{
+	Code:       ErrorCodeSameWallet,
+	Message:    "кошельки отправителя и получателя должны различаться",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidCurrency - код валюты отсутствует в реестре ISO 4217
+var ErrInvalidCurrency = &AppError{
+	Code:       ErrorCodeInvalidCurrency,
+	Message:    "неизвестный код валюты ISO 4217",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrCurrencyMismatch - валюта операции не совпадает с валютой кошелька
+var ErrCurrencyMismatch = &AppError{
+	Code:       ErrorCodeCurrencyMismatch,
+	Message:    "валюта операции не совпадает с валютой кошелька",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrHoldNotFound - блокировка средств не найдена
+var ErrHoldNotFound = &AppError{
+	Code:       ErrorCodeHoldNotFound,
+	Message:    "блокировка средств не найдена",
+	StatusCode: http.StatusNotFound,
+}
+
+// ErrHoldNotActive - блокировка уже списана, отменена или истекла
+var ErrHoldNotActive = &AppError{
+	Code:       ErrorCodeHoldNotActive,
+	Message:    "блокировка средств не активна",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrCaptureExceedsHold - сумма списания больше заблокированной
+var ErrCaptureExceedsHold = &AppError{
+	Code:       ErrorCodeCaptureExceedsHold,
+	Message:    "сумма списания превышает заблокированную сумму",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidHoldTTL - некорректный срок действия блокировки
+var ErrInvalidHoldTTL = &AppError{
+	Code:       ErrorCodeInvalidHoldTTL,
+	Message:    "некорректный срок действия блокировки",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrWalletFrozen - кошелёк заморожен, операции с балансом запрещены
+var ErrWalletFrozen = &AppError{
+	Code:       ErrorCodeWalletFrozen,
+	Message:    "кошелёк заморожен",
+	StatusCode: http.StatusLocked,
+}
+
+// ErrWalletClosed - кошелёк закрыт
+var ErrWalletClosed = &AppError{
+	Code:       ErrorCodeWalletClosed,
+	Message:    "кошелёк закрыт",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrInvalidWalletStatus - неизвестный статус кошелька или недопустимые параметры смены статуса
+var ErrInvalidWalletStatus = &AppError{
+	Code:       ErrorCodeInvalidWalletStatus,
+	Message:    "недопустимый статус кошелька",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrStatusReasonRequired - не указана причина смены статуса
+var ErrStatusReasonRequired = &AppError{
+	Code:       ErrorCodeStatusReasonRequired,
+	Message:    "необходимо указать причину смены статуса",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidStatusTransition - переход в указанный статус невозможен из текущего
+var ErrInvalidStatusTransition = &AppError{
+	Code:       ErrorCodeInvalidStatusTransition,
+	Message:    "недопустимая смена статуса кошелька",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrWalletNotEmpty - закрытие кошелька с остатком без кошелька для перевода остатка
+var ErrWalletNotEmpty = &AppError{
+	Code:       ErrorCodeWalletNotEmpty,
+	Message:    "для закрытия кошелька баланс должен быть нулевым или указан кошелёк для перевода остатка",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrWalletHasActiveHolds - закрытие кошелька с активными блокировками средств
+var ErrWalletHasActiveHolds = &AppError{
+	Code:       ErrorCodeWalletHasActiveHolds,
+	Message:    "на кошельке есть активные блокировки средств",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrInvalidExternalRef - некорректный внешний идентификатор кошелька или владельца
+var ErrInvalidExternalRef = &AppError{
+	Code:       ErrorCodeInvalidExternalRef,
+	Message:    "некорректный externalRef или ownerId",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidBalanceRange - некорректный диапазон баланса в фильтре
+var ErrInvalidBalanceRange = &AppError{
+	Code:       ErrorCodeInvalidBalanceRange,
+	Message:    "некорректный диапазон баланса",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidSort - неизвестное поле или направление сортировки
+var ErrInvalidSort = &AppError{
+	Code:       ErrorCodeInvalidSort,
+	Message:    "недопустимая сортировка",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidBatchSize - пустой пакет операций или пакет больше допустимого размера
+var ErrInvalidBatchSize = &AppError{
+	Code:       ErrorCodeInvalidBatchSize,
+	Message:    "недопустимый размер пакета операций",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidBatchMode - неизвестный режим выполнения пакета
+var ErrInvalidBatchMode = &AppError{
+	Code:       ErrorCodeInvalidBatchMode,
+	Message:    "недопустимый режим выполнения пакета",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrReconciliationNotFound - сверка балансов ещё не выполнялась
+var ErrReconciliationNotFound = &AppError{
+	Code:       ErrorCodeReconciliationNotFound,
+	Message:    "сверка балансов ещё не выполнялась",
+	StatusCode: http.StatusNotFound,
+}
+
+// ErrInvalidBalanceTime - баланс запрошен на момент в будущем
+var ErrInvalidBalanceTime = &AppError{
+	Code:       ErrorCodeInvalidBalanceTime,
+	Message:    "момент времени для баланса не может быть в будущем",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrWebhookNotFound - подписка на события не найдена
+var ErrWebhookNotFound = &AppError{
+	Code:       ErrorCodeWebhookNotFound,
+	Message:    "подписка на события не найдена",
+	StatusCode: http.StatusNotFound,
+}
+
+// ErrInvalidWebhookURL - адрес получателя не является абсолютным http(s) URL
+var ErrInvalidWebhookURL = &AppError{
+	Code:       ErrorCodeInvalidWebhookURL,
+	Message:    "адрес получателя должен быть абсолютным http или https URL",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidEventType - неизвестный тип события
+var ErrInvalidEventType = &AppError{
+	Code:       ErrorCodeInvalidEventType,
+	Message:    "недопустимый тип события",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidDeliveryStatus - неизвестный статус доставки события
+var ErrInvalidDeliveryStatus = &AppError{
+	Code:       ErrorCodeInvalidDeliveryStatus,
+	Message:    "недопустимый статус доставки",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrLimitExceeded - операция превышает лимит кошелька
+var ErrLimitExceeded = &AppError{
+	Code:       ErrorCodeLimitExceeded,
+	Message:    "превышен лимит операций",
+	StatusCode: http.StatusForbidden,
+}
+
+// ErrInvalidLimitOperation - неизвестный вид операций для лимитов
+var ErrInvalidLimitOperation = &AppError{
+	Code:       ErrorCodeInvalidLimitOperation,
+	Message:    "недопустимый вид операций для лимитов",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrInvalidOperationLimits - значение лимита не положительное
+var ErrInvalidOperationLimits = &AppError{
+	Code:       ErrorCodeInvalidOperationLimits,
+	Message:    "значение лимита должно быть положительным",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrLimitNotFound - лимиты для вида операций не заданы
+var ErrLimitNotFound = &AppError{
+	Code:       ErrorCodeLimitNotFound,
+	Message:    "лимиты не заданы",
+	StatusCode: http.StatusNotFound,
+}
+
+// ErrInvalidCreditLimit - отрицательный кредитный лимит
+var ErrInvalidCreditLimit = &AppError{
+	Code:       ErrorCodeInvalidCreditLimit,
+	Message:    "кредитный лимит не может быть отрицательным",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrCreditLimitInUse - новый кредитный лимит меньше использованного кредита
+var ErrCreditLimitInUse = &AppError{
+	Code:       ErrorCodeCreditLimitInUse,
+	Message:    "кредитный лимит не может быть меньше использованного кредита",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrWalletHasDebt - закрытие кошелька с отрицательным балансом
+var ErrWalletHasDebt = &AppError{
+	Code:       ErrorCodeWalletHasDebt,
+	Message:    "кошелёк с отрицательным балансом нельзя закрыть",
+	StatusCode: http.StatusConflict,
+}
+
+// ErrInvalidBalanceBuckets - число корзин баланса вне допустимого диапазона
+var ErrInvalidBalanceBuckets = &AppError{
+	Code:       ErrorCodeInvalidBalanceBuckets,
+	Message:    "число корзин баланса должно быть от 0 до 64",
+	StatusCode: http.StatusBadRequest,
+}
+
+// ErrVersionMismatch - кошелёк изменился после чтения версии, указанной в If-Match
+var ErrVersionMismatch = &AppError{
+	Code:       ErrorCodeVersionMismatch,
+	Message:    "версия кошелька не совпадает с If-Match",
+	StatusCode: http.StatusPreconditionFailed,
+}
+
+// ErrInvalidStatementFormat - неизвестный формат выписки
+var ErrInvalidStatementFormat = &AppError{
+	Code:       ErrorCodeInvalidStatementFormat,
+	Message:    "формат выписки должен быть csv, json или ndjson",
+	StatusCode: http.StatusBadRequest,
+}
+
 // ErrDatabaseError - ошибка базы данных
 var ErrDatabaseError = &AppError{
 	Code:       ErrorCodeDatabaseError,
@@ -89,14 +377,54 @@ var ErrDatabaseError = &AppError{
 
 // Коды ошибок
 const (
-	ErrorCodeWalletNotFound       = 1001
-	ErrorCodeInsufficientFunds    = 1002
-	ErrorCodeInvalidAmount        = 1003
-	ErrorCodeInvalidOperationType = 1004
-	ErrorCodeWalletAlreadyExists  = 1005
-	ErrorCodeInvalidJSON          = 1006
-	ErrorCodeInvalidWalletID      = 1007
-	ErrorCodeDatabaseError        = 2001
+	ErrorCodeWalletNotFound          = 1001
+	ErrorCodeInsufficientFunds       = 1002
+	ErrorCodeInvalidAmount           = 1003
+	ErrorCodeInvalidOperationType    = 1004
+	ErrorCodeWalletAlreadyExists     = 1005
+	ErrorCodeInvalidJSON             = 1006
+	ErrorCodeInvalidWalletID         = 1007
+	ErrorCodeInvalidCursor           = 1008
+	ErrorCodeInvalidLimit            = 1009
+	ErrorCodeInvalidTimeRange        = 1010
+	ErrorCodeInvalidIdempotencyKey   = 1011
+	ErrorCodeIdempotencyKeyMismatch  = 1012
+	ErrorCodeSameWallet              = 1013
+	ErrorCodeInvalidCurrency         = 1014
+	ErrorCodeCurrencyMismatch        = 1015
+	ErrorCodeHoldNotFound            = 1016
+	ErrorCodeHoldNotActive           = 1017
+	ErrorCodeCaptureExceedsHold      = 1018
+	ErrorCodeInvalidHoldTTL          = 1019
+	ErrorCodeWalletFrozen            = 1020
+	ErrorCodeWalletClosed            = 1021
+	ErrorCodeInvalidWalletStatus     = 1022
+	ErrorCodeStatusReasonRequired    = 1023
+	ErrorCodeInvalidStatusTransition = 1024
+	ErrorCodeWalletNotEmpty          = 1025
+	ErrorCodeWalletHasActiveHolds    = 1026
+	ErrorCodeInvalidExternalRef      = 1027
+	ErrorCodeInvalidBalanceRange     = 1028
+	ErrorCodeInvalidSort             = 1029
+	ErrorCodeInvalidBatchSize        = 1030
+	ErrorCodeInvalidBatchMode        = 1031
+	ErrorCodeReconciliationNotFound  = 1032
+	ErrorCodeInvalidBalanceTime      = 1033
+	ErrorCodeWebhookNotFound         = 1034
+	ErrorCodeInvalidWebhookURL       = 1035
+	ErrorCodeInvalidEventType        = 1036
+	ErrorCodeInvalidDeliveryStatus   = 1037
+	ErrorCodeLimitExceeded           = 1038
+	ErrorCodeInvalidLimitOperation   = 1039
+	ErrorCodeInvalidOperationLimits  = 1040
+	ErrorCodeLimitNotFound           = 1041
+	ErrorCodeInvalidCreditLimit      = 1042
+	ErrorCodeCreditLimitInUse        = 1043
+	ErrorCodeWalletHasDebt           = 1044
+	ErrorCodeInvalidBalanceBuckets   = 1045
+	ErrorCodeVersionMismatch         = 1046
+	ErrorCodeInvalidStatementFormat  = 1047
+	ErrorCodeDatabaseError           = 2001
 )
 
 // Вспомогательные функции для создания ошибок с контекстом
@@ -149,6 +477,28 @@ func NewWalletAlreadyExists(err error) *AppError {
 	}
 }
 
+// LimitExceededDetails - нарушенный лимит для тела ответа: вид операций, ограничение
+// (OPERATION, DAILY, WEEKLY или MONTHLY), его значение, использованная сумма
+// и сумма, которую кошелёк ещё может провести
+type LimitExceededDetails struct {
+	Operation string
+	Period    string
+	Limit     int64
+	Used      int64
+	Remaining int64
+}
+
+// NewLimitExceeded возвращает ошибку превышения лимита с доступным остатком
+func NewLimitExceeded(details LimitExceededDetails) *AppError {
+	return &AppError{
+		Code: ErrorCodeLimitExceeded,
+		Message: fmt.Sprintf("%s: %s, ограничение %s, доступно %d",
+			ErrLimitExceeded.Message, details.Operation, details.Period, details.Remaining),
+		StatusCode: ErrLimitExceeded.StatusCode,
+		Details:    details,
+	}
+}
+
 // NewDatabaseError возвращает ошибку базы данных с контекстом
 func NewDatabaseError(operation string, err error) *AppError {
 	return &AppError{
@@ -0,0 +1,96 @@
+// Package events описывает доменные события кошельков и их доставку внешним потребителям.
+// События записываются в outbox в той же транзакции, что и изменение кошелька,
+// и доставляются Relay с семантикой at-least-once и сохранением порядка в пределах кошелька.
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Type - тип доменного события
+type Type string
+
+const (
+	WalletCreated       Type = "WalletCreated"
+	WalletStatusChanged Type = "WalletStatusChanged"
+	Deposited           Type = "Deposited"
+	Withdrawn           Type = "Withdrawn"
+	TransferSent        Type = "TransferSent"
+	TransferReceived    Type = "TransferReceived"
+	HoldCaptured        Type = "HoldCaptured"
+	BalanceAdjusted     Type = "BalanceAdjusted"
+)
+
+// TypeForTransaction возвращает тип события для записи истории операций
+func TypeForTransaction(t repository.TransactionType) Type {
+	switch t {
+	case repository.TransactionDeposit:
+		return Deposited
+	case repository.TransactionWithdraw:
+		return Withdrawn
+	case repository.TransactionTransferOut:
+		return TransferSent
+	case repository.TransactionTransferIn:
+		return TransferReceived
+	case repository.TransactionHoldCapture:
+		return HoldCaptured
+	case repository.TransactionAdjustment:
+		return BalanceAdjusted
+	default:
+		return Type(t)
+	}
+}
+
+// Event - событие в том виде, в котором оно доставляется потребителям.
+// Sequence монотонно растёт в порядке записи событий; ID стабилен между повторными доставками
+// и служит потребителям ключом дедупликации.
+type Event struct {
+	ID         uuid.UUID       `json:"id"`
+	Sequence   int64           `json:"sequence"`
+	Type       Type            `json:"type"`
+	WalletID   uuid.UUID       `json:"walletId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// FromOutbox конвертирует запись outbox в событие для доставки
+func FromOutbox(e repository.OutboxEvent) Event {
+	return Event{
+		ID:         e.EventID,
+		Sequence:   e.Sequence,
+		Type:       Type(e.Type),
+		WalletID:   e.WalletID,
+		OccurredAt: e.CreatedAt,
+		Payload:    e.Payload,
+	}
+}
+
+// WalletCreatedPayload - данные события WalletCreated
+type WalletCreatedPayload struct {
+	Currency    string  `json:"currency"`
+	OwnerID     string  `json:"ownerId,omitempty"`
+	ExternalRef *string `json:"externalRef,omitempty"`
+}
+
+// StatusChangedPayload - данные события WalletStatusChanged
+type StatusChangedPayload struct {
+	From   repository.WalletStatus `json:"from"`
+	To     repository.WalletStatus `json:"to"`
+	Reason string                  `json:"reason"`
+}
+
+// BalanceChangedPayload - данные событий изменения баланса: Deposited, Withdrawn,
+// TransferSent, TransferReceived, HoldCaptured и BalanceAdjusted. Amount отрицательный для списаний.
+// BalanceAfter операций через корзины шардированного кошелька приблизителен.
+type BalanceChangedPayload struct {
+	TransactionID        uuid.UUID  `json:"transactionId"`
+	Amount               int64      `json:"amount"`
+	BalanceAfter         int64      `json:"balanceAfter"`
+	Currency             string     `json:"currency"`
+	CounterpartyWalletID *uuid.UUID `json:"counterpartyWalletId,omitempty"`
+	JournalEntryID       *uuid.UUID `json:"journalEntryId,omitempty"`
+}
@@ -0,0 +1,128 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// EventPublisher доставляет событие потребителям. Publish возвращает nil, только когда
+// событие надёжно принято: после этого оно считается доставленным и повторно не отправляется.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// WriterPublisher пишет события в io.Writer по одному JSON-объекту на строку
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// NewStdoutPublisher возвращает публикатор, который пишет события в стандартный вывод
+func NewStdoutPublisher() *WriterPublisher {
+	return NewWriterPublisher(os.Stdout)
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("сериализация события %s: %w", event.ID, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(data, '\n'))
+	return err
+}
+
+// FilePublisher дописывает события в локальный файл по одному JSON-объекту на строку.
+// Каждое событие сбрасывается на диск до подтверждения доставки.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть файл событий %s: %w", path, err)
+	}
+	return &FilePublisher{file: file}, nil
+}
+
+func (p *FilePublisher) Publish(_ context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("сериализация события %s: %w", event.ID, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+// HTTPPublisher отправляет каждое событие POST-запросом с JSON-телом.
+// Доставленным считается событие, на которое получен ответ 2xx.
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPPublisher(url string, timeout time.Duration) *HTTPPublisher {
+	return &HTTPPublisher{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("сериализация события %s: %w", event.ID, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Потребитель дедуплицирует повторные доставки по идентификатору события
+	req.Header.Set("X-Event-Id", event.ID.String())
+	req.Header.Set("X-Event-Type", string(event.Type))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("получатель событий ответил статусом %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiPublisher передаёт событие нескольким публикаторам по порядку. Событие считается
+// доставленным, только когда его приняли все; при повторе оно снова передаётся всем,
+// поэтому каждый публикатор должен переносить повторные доставки.
+type MultiPublisher []EventPublisher
+
+func (p MultiPublisher) Publish(ctx context.Context, event Event) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
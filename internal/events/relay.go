@@ -0,0 +1,37 @@
+package events
+
+import (
+	"context"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+// Relay доставляет события из outbox публикатору. Событие отмечается доставленным
+// только после успешного Publish, поэтому при сбоях возможны повторы (at-least-once);
+// события одного кошелька доставляются в порядке их записи.
+type Relay struct {
+	repo      repository.OutboxRepository
+	publisher EventPublisher
+	batchSize int
+}
+
+func NewRelay(repo repository.OutboxRepository, publisher EventPublisher, batchSize int) *Relay {
+	return &Relay{repo: repo, publisher: publisher, batchSize: batchSize}
+}
+
+// RelayPending доставляет накопившиеся события пакетами по batchSize, пока очередь не опустеет
+// или доставка не начнёт завершаться ошибками. Возвращает число доставленных событий.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	publish := func(e repository.OutboxEvent) error {
+		return r.publisher.Publish(ctx, FromOutbox(e))
+	}
+
+	total := 0
+	for {
+		published, err := r.repo.RelayOutbox(ctx, r.batchSize, publish)
+		total += published
+		if err != nil || published < r.batchSize {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,812 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: wallet/v1/wallet.proto
+
+// gRPC API кошельков. Методы повторяют одноимённые операции REST API (api/openapi.yaml)
+// и выполняются тем же сервисом, что и HTTP-запросы.
+
+package walletpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WalletStatus int32
+
+const (
+	WalletStatus_WALLET_STATUS_UNSPECIFIED WalletStatus = 0
+	// Кошелёк доступен для всех операций
+	WalletStatus_WALLET_STATUS_ACTIVE WalletStatus = 1
+	// Операции с балансом запрещены до разморозки
+	WalletStatus_WALLET_STATUS_FROZEN WalletStatus = 2
+	// Кошелёк закрыт окончательно
+	WalletStatus_WALLET_STATUS_CLOSED WalletStatus = 3
+)
+
+// Enum value maps for WalletStatus.
+var (
+	WalletStatus_name = map[int32]string{
+		0: "WALLET_STATUS_UNSPECIFIED",
+		1: "WALLET_STATUS_ACTIVE",
+		2: "WALLET_STATUS_FROZEN",
+		3: "WALLET_STATUS_CLOSED",
+	}
+	WalletStatus_value = map[string]int32{
+		"WALLET_STATUS_UNSPECIFIED": 0,
+		"WALLET_STATUS_ACTIVE":      1,
+		"WALLET_STATUS_FROZEN":      2,
+		"WALLET_STATUS_CLOSED":      3,
+	}
+)
+
+func (x WalletStatus) Enum() *WalletStatus {
+	p := new(WalletStatus)
+	*p = x
+	return p
+}
+
+func (x WalletStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WalletStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_wallet_v1_wallet_proto_enumTypes[0].Descriptor()
+}
+
+func (WalletStatus) Type() protoreflect.EnumType {
+	return &file_wallet_v1_wallet_proto_enumTypes[0]
+}
+
+func (x WalletStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WalletStatus.Descriptor instead.
+func (WalletStatus) EnumDescriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{0}
+}
+
+type OperationType int32
+
+const (
+	OperationType_OPERATION_TYPE_UNSPECIFIED OperationType = 0
+	OperationType_OPERATION_TYPE_DEPOSIT     OperationType = 1
+	OperationType_OPERATION_TYPE_WITHDRAW    OperationType = 2
+)
+
+// Enum value maps for OperationType.
+var (
+	OperationType_name = map[int32]string{
+		0: "OPERATION_TYPE_UNSPECIFIED",
+		1: "OPERATION_TYPE_DEPOSIT",
+		2: "OPERATION_TYPE_WITHDRAW",
+	}
+	OperationType_value = map[string]int32{
+		"OPERATION_TYPE_UNSPECIFIED": 0,
+		"OPERATION_TYPE_DEPOSIT":     1,
+		"OPERATION_TYPE_WITHDRAW":    2,
+	}
+)
+
+func (x OperationType) Enum() *OperationType {
+	p := new(OperationType)
+	*p = x
+	return p
+}
+
+func (x OperationType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OperationType) Descriptor() protoreflect.EnumDescriptor {
+	return file_wallet_v1_wallet_proto_enumTypes[1].Descriptor()
+}
+
+func (OperationType) Type() protoreflect.EnumType {
+	return &file_wallet_v1_wallet_proto_enumTypes[1]
+}
+
+func (x OperationType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OperationType.Descriptor instead.
+func (OperationType) EnumDescriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{1}
+}
+
+// Wallet - баланс и состояние кошелька, аналог WalletBalanceResponse
+type Wallet struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WalletId string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	// Баланс в минимальных единицах валюты
+	Balance int64 `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// Сумма, доступная для списания - баланс за вычетом активных блокировок плюс кредитный лимит
+	AvailableBalance int64 `protobuf:"varint,3,opt,name=available_balance,json=availableBalance,proto3" json:"available_balance,omitempty"`
+	// Кредитный лимит - баланс за вычетом блокировок может опускаться до -credit_limit
+	CreditLimit int64 `protobuf:"varint,4,opt,name=credit_limit,json=creditLimit,proto3" json:"credit_limit,omitempty"`
+	// Использованная часть кредитного лимита
+	CreditUsed int64 `protobuf:"varint,5,opt,name=credit_used,json=creditUsed,proto3" json:"credit_used,omitempty"`
+	// Код валюты ISO 4217
+	Currency    string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status      WalletStatus           `protobuf:"varint,7,opt,name=status,proto3,enum=wallet.v1.WalletStatus" json:"status,omitempty"`
+	OwnerId     string                 `protobuf:"bytes,8,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	ExternalRef *string                `protobuf:"bytes,9,opt,name=external_ref,json=externalRef,proto3,oneof" json:"external_ref,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Количество знаков дробной части валюты (экспонента ISO 4217)
+	MinorUnits *int32 `protobuf:"varint,11,opt,name=minor_units,json=minorUnits,proto3,oneof" json:"minor_units,omitempty"`
+	// Версия кошелька, растёт при каждом изменении баланса, блокировок, кредитного лимита или статуса
+	Version       int64 `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Wallet) Reset() {
+	*x = Wallet{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Wallet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Wallet) ProtoMessage() {}
+
+func (x *Wallet) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Wallet.ProtoReflect.Descriptor instead.
+func (*Wallet) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Wallet) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *Wallet) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *Wallet) GetAvailableBalance() int64 {
+	if x != nil {
+		return x.AvailableBalance
+	}
+	return 0
+}
+
+func (x *Wallet) GetCreditLimit() int64 {
+	if x != nil {
+		return x.CreditLimit
+	}
+	return 0
+}
+
+func (x *Wallet) GetCreditUsed() int64 {
+	if x != nil {
+		return x.CreditUsed
+	}
+	return 0
+}
+
+func (x *Wallet) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Wallet) GetStatus() WalletStatus {
+	if x != nil {
+		return x.Status
+	}
+	return WalletStatus_WALLET_STATUS_UNSPECIFIED
+}
+
+func (x *Wallet) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Wallet) GetExternalRef() string {
+	if x != nil && x.ExternalRef != nil {
+		return *x.ExternalRef
+	}
+	return ""
+}
+
+func (x *Wallet) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Wallet) GetMinorUnits() int32 {
+	if x != nil && x.MinorUnits != nil {
+		return *x.MinorUnits
+	}
+	return 0
+}
+
+func (x *Wallet) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CreateWalletRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Код валюты ISO 4217, по умолчанию RUB
+	Currency string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Идентификатор кошелька; пустой - генерируется сервером
+	WalletId string `protobuf:"bytes,2,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	// Владелец external_ref, например идентификатор внешнего сервиса
+	OwnerId string `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// Идентификатор кошелька во внешней системе, уникален в пределах owner_id
+	ExternalRef *string `protobuf:"bytes,4,opt,name=external_ref,json=externalRef,proto3,oneof" json:"external_ref,omitempty"`
+	// Вернуть существующий кошелёк вместо ошибки ALREADY_EXISTS
+	ReturnExisting bool `protobuf:"varint,5,opt,name=return_existing,json=returnExisting,proto3" json:"return_existing,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateWalletRequest) Reset() {
+	*x = CreateWalletRequest{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWalletRequest) ProtoMessage() {}
+
+func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWalletRequest.ProtoReflect.Descriptor instead.
+func (*CreateWalletRequest) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateWalletRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateWalletRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *CreateWalletRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *CreateWalletRequest) GetExternalRef() string {
+	if x != nil && x.ExternalRef != nil {
+		return *x.ExternalRef
+	}
+	return ""
+}
+
+func (x *CreateWalletRequest) GetReturnExisting() bool {
+	if x != nil {
+		return x.ReturnExisting
+	}
+	return false
+}
+
+type CreateWalletResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Wallet *Wallet                `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	// Кошелёк создан этим запросом; false - возвращён существующий
+	Created       bool `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWalletResponse) Reset() {
+	*x = CreateWalletResponse{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWalletResponse) ProtoMessage() {}
+
+func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWalletResponse.ProtoReflect.Descriptor instead.
+func (*CreateWalletResponse) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateWalletResponse) GetWallet() *Wallet {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+func (x *CreateWalletResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type GetWalletBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWalletBalanceRequest) Reset() {
+	*x = GetWalletBalanceRequest{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWalletBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWalletBalanceRequest) ProtoMessage() {}
+
+func (x *GetWalletBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWalletBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetWalletBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetWalletBalanceRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+type ProcessWalletOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	OperationType OperationType          `protobuf:"varint,2,opt,name=operation_type,json=operationType,proto3,enum=wallet.v1.OperationType" json:"operation_type,omitempty"`
+	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Код валюты ISO 4217; если указан, должен совпадать с валютой кошелька
+	Currency string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Ключ идемпотентности: повтор запроса с тем же ключом и телом возвращает результат
+	// первого выполнения без повторного изменения баланса
+	IdempotencyKey string `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Операция выполняется, только если версия кошелька равна expected_version (аналог If-Match),
+	// иначе возвращается ABORTED
+	ExpectedVersion *int64 `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProcessWalletOperationRequest) Reset() {
+	*x = ProcessWalletOperationRequest{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessWalletOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessWalletOperationRequest) ProtoMessage() {}
+
+func (x *ProcessWalletOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessWalletOperationRequest.ProtoReflect.Descriptor instead.
+func (*ProcessWalletOperationRequest) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProcessWalletOperationRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *ProcessWalletOperationRequest) GetOperationType() OperationType {
+	if x != nil {
+		return x.OperationType
+	}
+	return OperationType_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *ProcessWalletOperationRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ProcessWalletOperationRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ProcessWalletOperationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *ProcessWalletOperationRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type ProcessWalletOperationResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Идентификатор записи в истории операций кошелька
+	OperationId   string        `protobuf:"bytes,1,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	WalletId      string        `protobuf:"bytes,2,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	OperationType OperationType `protobuf:"varint,3,opt,name=operation_type,json=operationType,proto3,enum=wallet.v1.OperationType" json:"operation_type,omitempty"`
+	Amount        int64         `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Баланс кошелька после операции
+	Balance       int64                  `protobuf:"varint,5,opt,name=balance,proto3" json:"balance,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessWalletOperationResponse) Reset() {
+	*x = ProcessWalletOperationResponse{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessWalletOperationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessWalletOperationResponse) ProtoMessage() {}
+
+func (x *ProcessWalletOperationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessWalletOperationResponse.ProtoReflect.Descriptor instead.
+func (*ProcessWalletOperationResponse) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProcessWalletOperationResponse) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+func (x *ProcessWalletOperationResponse) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+func (x *ProcessWalletOperationResponse) GetOperationType() OperationType {
+	if x != nil {
+		return x.OperationType
+	}
+	return OperationType_OPERATION_TYPE_UNSPECIFIED
+}
+
+func (x *ProcessWalletOperationResponse) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ProcessWalletOperationResponse) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *ProcessWalletOperationResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type WatchBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      string                 `protobuf:"bytes,1,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchBalanceRequest) Reset() {
+	*x = WatchBalanceRequest{}
+	mi := &file_wallet_v1_wallet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchBalanceRequest) ProtoMessage() {}
+
+func (x *WatchBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_v1_wallet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchBalanceRequest.ProtoReflect.Descriptor instead.
+func (*WatchBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_wallet_v1_wallet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchBalanceRequest) GetWalletId() string {
+	if x != nil {
+		return x.WalletId
+	}
+	return ""
+}
+
+var File_wallet_v1_wallet_proto protoreflect.FileDescriptor
+
+const file_wallet_v1_wallet_proto_rawDesc = "" +
+	"\n" +
+	"\x16wallet/v1/wallet.proto\x12\twallet.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdc\x03\n" +
+	"\x06Wallet\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x03R\abalance\x12+\n" +
+	"\x11available_balance\x18\x03 \x01(\x03R\x10availableBalance\x12!\n" +
+	"\fcredit_limit\x18\x04 \x01(\x03R\vcreditLimit\x12\x1f\n" +
+	"\vcredit_used\x18\x05 \x01(\x03R\n" +
+	"creditUsed\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12/\n" +
+	"\x06status\x18\a \x01(\x0e2\x17.wallet.v1.WalletStatusR\x06status\x12\x19\n" +
+	"\bowner_id\x18\b \x01(\tR\aownerId\x12&\n" +
+	"\fexternal_ref\x18\t \x01(\tH\x00R\vexternalRef\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12$\n" +
+	"\vminor_units\x18\v \x01(\x05H\x01R\n" +
+	"minorUnits\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversionB\x0f\n" +
+	"\r_external_refB\x0e\n" +
+	"\f_minor_units\"\xcb\x01\n" +
+	"\x13CreateWalletRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x1b\n" +
+	"\twallet_id\x18\x02 \x01(\tR\bwalletId\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12&\n" +
+	"\fexternal_ref\x18\x04 \x01(\tH\x00R\vexternalRef\x88\x01\x01\x12'\n" +
+	"\x0freturn_existing\x18\x05 \x01(\bR\x0ereturnExistingB\x0f\n" +
+	"\r_external_ref\"[\n" +
+	"\x14CreateWalletResponse\x12)\n" +
+	"\x06wallet\x18\x01 \x01(\v2\x11.wallet.v1.WalletR\x06wallet\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"6\n" +
+	"\x17GetWalletBalanceRequest\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\"\x9f\x02\n" +
+	"\x1dProcessWalletOperationRequest\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId\x12?\n" +
+	"\x0eoperation_type\x18\x02 \x01(\x0e2\x18.wallet.v1.OperationTypeR\roperationType\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\x12.\n" +
+	"\x10expected_version\x18\x06 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\x8e\x02\n" +
+	"\x1eProcessWalletOperationResponse\x12!\n" +
+	"\foperation_id\x18\x01 \x01(\tR\voperationId\x12\x1b\n" +
+	"\twallet_id\x18\x02 \x01(\tR\bwalletId\x12?\n" +
+	"\x0eoperation_type\x18\x03 \x01(\x0e2\x18.wallet.v1.OperationTypeR\roperationType\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x18\n" +
+	"\abalance\x18\x05 \x01(\x03R\abalance\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"2\n" +
+	"\x13WatchBalanceRequest\x12\x1b\n" +
+	"\twallet_id\x18\x01 \x01(\tR\bwalletId*{\n" +
+	"\fWalletStatus\x12\x1d\n" +
+	"\x19WALLET_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14WALLET_STATUS_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14WALLET_STATUS_FROZEN\x10\x02\x12\x18\n" +
+	"\x14WALLET_STATUS_CLOSED\x10\x03*h\n" +
+	"\rOperationType\x12\x1e\n" +
+	"\x1aOPERATION_TYPE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16OPERATION_TYPE_DEPOSIT\x10\x01\x12\x1b\n" +
+	"\x17OPERATION_TYPE_WITHDRAW\x10\x022\xdf\x02\n" +
+	"\rWalletService\x12O\n" +
+	"\fCreateWallet\x12\x1e.wallet.v1.CreateWalletRequest\x1a\x1f.wallet.v1.CreateWalletResponse\x12I\n" +
+	"\x10GetWalletBalance\x12\".wallet.v1.GetWalletBalanceRequest\x1a\x11.wallet.v1.Wallet\x12m\n" +
+	"\x16ProcessWalletOperation\x12(.wallet.v1.ProcessWalletOperationRequest\x1a).wallet.v1.ProcessWalletOperationResponse\x12C\n" +
+	"\fWatchBalance\x12\x1e.wallet.v1.WatchBalanceRequest\x1a\x11.wallet.v1.Wallet0\x01BJZHgithub.com/devopesik/wallet-basic-operations/internal/generated/walletpbb\x06proto3"
+
+var (
+	file_wallet_v1_wallet_proto_rawDescOnce sync.Once
+	file_wallet_v1_wallet_proto_rawDescData []byte
+)
+
+func file_wallet_v1_wallet_proto_rawDescGZIP() []byte {
+	file_wallet_v1_wallet_proto_rawDescOnce.Do(func() {
+		file_wallet_v1_wallet_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_wallet_v1_wallet_proto_rawDesc), len(file_wallet_v1_wallet_proto_rawDesc)))
+	})
+	return file_wallet_v1_wallet_proto_rawDescData
+}
+
+var file_wallet_v1_wallet_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_wallet_v1_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_wallet_v1_wallet_proto_goTypes = []any{
+	(WalletStatus)(0),                      // 0: wallet.v1.WalletStatus
+	(OperationType)(0),                     // 1: wallet.v1.OperationType
+	(*Wallet)(nil),                         // 2: wallet.v1.Wallet
+	(*CreateWalletRequest)(nil),            // 3: wallet.v1.CreateWalletRequest
+	(*CreateWalletResponse)(nil),           // 4: wallet.v1.CreateWalletResponse
+	(*GetWalletBalanceRequest)(nil),        // 5: wallet.v1.GetWalletBalanceRequest
+	(*ProcessWalletOperationRequest)(nil),  // 6: wallet.v1.ProcessWalletOperationRequest
+	(*ProcessWalletOperationResponse)(nil), // 7: wallet.v1.ProcessWalletOperationResponse
+	(*WatchBalanceRequest)(nil),            // 8: wallet.v1.WatchBalanceRequest
+	(*timestamppb.Timestamp)(nil),          // 9: google.protobuf.Timestamp
+}
+var file_wallet_v1_wallet_proto_depIdxs = []int32{
+	0,  // 0: wallet.v1.Wallet.status:type_name -> wallet.v1.WalletStatus
+	9,  // 1: wallet.v1.Wallet.created_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: wallet.v1.CreateWalletResponse.wallet:type_name -> wallet.v1.Wallet
+	1,  // 3: wallet.v1.ProcessWalletOperationRequest.operation_type:type_name -> wallet.v1.OperationType
+	1,  // 4: wallet.v1.ProcessWalletOperationResponse.operation_type:type_name -> wallet.v1.OperationType
+	9,  // 5: wallet.v1.ProcessWalletOperationResponse.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 6: wallet.v1.WalletService.CreateWallet:input_type -> wallet.v1.CreateWalletRequest
+	5,  // 7: wallet.v1.WalletService.GetWalletBalance:input_type -> wallet.v1.GetWalletBalanceRequest
+	6,  // 8: wallet.v1.WalletService.ProcessWalletOperation:input_type -> wallet.v1.ProcessWalletOperationRequest
+	8,  // 9: wallet.v1.WalletService.WatchBalance:input_type -> wallet.v1.WatchBalanceRequest
+	4,  // 10: wallet.v1.WalletService.CreateWallet:output_type -> wallet.v1.CreateWalletResponse
+	2,  // 11: wallet.v1.WalletService.GetWalletBalance:output_type -> wallet.v1.Wallet
+	7,  // 12: wallet.v1.WalletService.ProcessWalletOperation:output_type -> wallet.v1.ProcessWalletOperationResponse
+	2,  // 13: wallet.v1.WalletService.WatchBalance:output_type -> wallet.v1.Wallet
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_wallet_v1_wallet_proto_init() }
+func file_wallet_v1_wallet_proto_init() {
+	if File_wallet_v1_wallet_proto != nil {
+		return
+	}
+	file_wallet_v1_wallet_proto_msgTypes[0].OneofWrappers = []any{}
+	file_wallet_v1_wallet_proto_msgTypes[1].OneofWrappers = []any{}
+	file_wallet_v1_wallet_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wallet_v1_wallet_proto_rawDesc), len(file_wallet_v1_wallet_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_wallet_v1_wallet_proto_goTypes,
+		DependencyIndexes: file_wallet_v1_wallet_proto_depIdxs,
+		EnumInfos:         file_wallet_v1_wallet_proto_enumTypes,
+		MessageInfos:      file_wallet_v1_wallet_proto_msgTypes,
+	}.Build()
+	File_wallet_v1_wallet_proto = out.File
+	file_wallet_v1_wallet_proto_goTypes = nil
+	file_wallet_v1_wallet_proto_depIdxs = nil
+}
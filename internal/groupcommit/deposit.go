@@ -0,0 +1,210 @@
+// Package groupcommit группирует параллельные пополнения одного кошелька: пополнения,
+// пришедшие в течение короткого окна, применяются одной транзакцией, каждое со своей
+// записью истории и проводкой журнала.
+package groupcommit
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/metrics"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+// depositKey - пополнения группируются по кошельку и указанной в запросе валюте
+type depositKey struct {
+	walletID uuid.UUID
+	currency string
+}
+
+// pendingDeposit - пополнение, ожидающее применения в группе
+type pendingDeposit struct {
+	ctx  context.Context
+	op   repository.Operation
+	done chan depositResult
+}
+
+type depositResult struct {
+	transaction *repository.Transaction
+	err         error
+}
+
+// depositGroup - пополнения одного кошелька, собранные за окно. taken - группу забрал flush:
+// её пополнения могут быть зафиксированы, поэтому отмена запроса их уже не отзывает.
+type depositGroup struct {
+	deposits []*pendingDeposit
+	timer    *time.Timer
+	taken    bool
+}
+
+// DepositCoalescer - репозиторий, который группирует пополнения и применяет группу одним
+// пакетом операций. Остальные методы передаются исходному репозиторию.
+type DepositCoalescer struct {
+	repository.WalletRepository
+
+	window       time.Duration
+	maxBatch     int
+	flushTimeout time.Duration
+
+	mu     sync.Mutex
+	groups map[depositKey]*depositGroup
+}
+
+// NewDepositCoalescer создаёт репозиторий, который собирает пополнения кошелька в течение window,
+// но не больше maxBatch, и применяет их одной транзакцией через ApplyBatch репозитория repo.
+// Применение группы ограничено flushTimeout: группа не зависит от отмены запросов, и без срока
+// зависшая база держала бы все ожидающие запросы. window и flushTimeout должны быть положительными,
+// maxBatch - не меньше 1.
+func NewDepositCoalescer(repo repository.WalletRepository, window time.Duration, maxBatch int, flushTimeout time.Duration) (*DepositCoalescer, error) {
+	if window <= 0 {
+		return nil, errors.New("окно группировки пополнений должно быть положительным")
+	}
+	if maxBatch < 1 {
+		return nil, errors.New("размер группы пополнений должен быть не меньше 1")
+	}
+	if flushTimeout <= 0 {
+		return nil, errors.New("таймаут применения группы пополнений должен быть положительным")
+	}
+	return &DepositCoalescer{
+		WalletRepository: repo,
+		window:           window,
+		maxBatch:         maxBatch,
+		flushTimeout:     flushTimeout,
+		groups:           make(map[depositKey]*depositGroup),
+	}, nil
+}
+
+// Deposit добавляет пополнение в группу кошелька и возвращает результат после фиксации транзакции группы.
+// Пополнения с ключом идемпотентности выполняются отдельно: ключ резервируется в транзакции операции.
+// Отдельно выполняются и пополнения с проверкой версии: пакет операций версию не проверяет.
+func (c *DepositCoalescer) Deposit(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	if op.IdempotencyKey != nil || op.ExpectedVersions != nil {
+		return c.WalletRepository.Deposit(ctx, op)
+	}
+
+	deposit := &pendingDeposit{ctx: ctx, op: op, done: make(chan depositResult, 1)}
+	key := depositKey{walletID: op.WalletID, currency: op.Currency}
+
+	c.mu.Lock()
+	group, ok := c.groups[key]
+	if !ok {
+		group = &depositGroup{}
+		group.timer = time.AfterFunc(c.window, func() { c.flush(key, group) })
+		c.groups[key] = group
+	}
+	group.deposits = append(group.deposits, deposit)
+	// Заполненная группа больше не принимает пополнения и применяется, не дожидаясь окна
+	full := len(group.deposits) >= c.maxBatch
+	if full {
+		delete(c.groups, key)
+	}
+	c.mu.Unlock()
+
+	if full && group.timer.Stop() {
+		go c.flush(key, group)
+	}
+
+	select {
+	case result := <-deposit.done:
+		return result.transaction, result.err
+	case <-ctx.Done():
+	}
+
+	// Пополнение из ещё не забранной группы отзывается. Забранная группа уже применяется
+	// и может зафиксировать пополнение, поэтому ответ об отмене был бы неверным: ждём результата,
+	// flush сам вернёт ошибку отмены, если не успел включить пополнение в транзакцию
+	c.mu.Lock()
+	taken := group.taken
+	if !taken {
+		group.deposits = slices.DeleteFunc(group.deposits, func(d *pendingDeposit) bool { return d == deposit })
+	}
+	c.mu.Unlock()
+	if !taken {
+		return nil, ctx.Err()
+	}
+	result := <-deposit.done
+	return result.transaction, result.err
+}
+
+// flush забирает группу и применяет её пополнения
+func (c *DepositCoalescer) flush(key depositKey, group *depositGroup) {
+	c.mu.Lock()
+	if c.groups[key] == group {
+		delete(c.groups, key)
+	}
+	group.taken = true
+	deposits := group.deposits
+	c.mu.Unlock()
+
+	// Пополнения, чей запрос уже отменён, не применяются
+	active := deposits[:0]
+	for _, d := range deposits {
+		if err := d.ctx.Err(); err != nil {
+			d.done <- depositResult{err: err}
+			continue
+		}
+		active = append(active, d)
+	}
+	if len(active) == 0 {
+		return
+	}
+
+	// Транзакция группы не должна прерываться, если отменён запрос, открывший группу,
+	// но ограничена собственным сроком
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(active[0].ctx), c.flushTimeout)
+	defer cancel()
+	metrics.DepositsCoalesced.Add(int64(len(active)))
+	if len(active) == 1 {
+		c.depositEach(ctx, active)
+		return
+	}
+
+	// Пакет операций блокирует кошелёк и переносит корзины шардированного баланса в его строку,
+	// поэтому пополнения шардированного кошелька не объединяются, а параллельно идут через корзины.
+	// Если кошелёк прочитать не удалось, пополнения тоже выполняются по отдельности: каждое вернёт
+	// свою ошибку (например, ErrWalletNotFound) так же, как без группировки
+	wallet, err := c.WalletRepository.GetWallet(ctx, key.walletID)
+	if err != nil || wallet.BalanceBuckets > 0 {
+		c.depositEach(ctx, active)
+		return
+	}
+
+	metrics.DepositBatches.Add(1)
+
+	ops := make([]repository.BatchOperation, len(active))
+	for i, d := range active {
+		ops[i] = repository.BatchOperation{
+			Type:     repository.TransactionDeposit,
+			WalletID: d.op.WalletID,
+			Amount:   d.op.Amount,
+			Currency: d.op.Currency,
+		}
+	}
+	results, err := c.WalletRepository.ApplyBatch(ctx, ops, false)
+	for i, d := range active {
+		if err != nil {
+			d.done <- depositResult{err: err}
+			continue
+		}
+		d.done <- depositResult{transaction: results[i].Transaction, err: results[i].Err}
+	}
+}
+
+// depositEach выполняет пополнения отдельными параллельными транзакциями
+func (c *DepositCoalescer) depositEach(ctx context.Context, deposits []*pendingDeposit) {
+	metrics.DepositBatches.Add(int64(len(deposits)))
+	var wg sync.WaitGroup
+	for _, d := range deposits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			transaction, err := c.WalletRepository.Deposit(ctx, d.op)
+			d.done <- depositResult{transaction: transaction, err: err}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,70 @@
+package grpcapi
+
+import (
+	"context"
+	stderrors "errors"
+	"log"
+	"net/http"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatusCode возвращает код статуса gRPC для ошибки сервиса. Коды, у которых есть точный
+// аналог в gRPC, переводятся напрямую, остальные - по HTTP статусу ошибки
+func StatusCode(err error) codes.Code {
+	switch {
+	case stderrors.Is(err, context.Canceled):
+		return codes.Canceled
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok {
+		return codes.Internal
+	}
+	switch appErr.Code {
+	case apperrors.ErrorCodeWalletAlreadyExists:
+		return codes.AlreadyExists
+	case apperrors.ErrorCodeVersionMismatch:
+		return codes.Aborted
+	case apperrors.ErrorCodeLimitExceeded:
+		return codes.ResourceExhausted
+	}
+	switch status := appErr.HTTPStatus(); {
+	case status == http.StatusBadRequest:
+		return codes.InvalidArgument
+	case status == http.StatusNotFound:
+		return codes.NotFound
+	case status == http.StatusForbidden:
+		return codes.PermissionDenied
+	case status >= http.StatusInternalServerError:
+		return codes.Internal
+	default:
+		// 409, 412, 422, 423: запрос корректен, но недопустим в текущем состоянии кошелька
+		return codes.FailedPrecondition
+	}
+}
+
+// statusError переводит ошибку сервиса в статус gRPC. Сообщения внутренних ошибок
+// клиенту не передаются, как и в HTTP API
+func statusError(err error) error {
+	code := StatusCode(err)
+	if code == codes.Canceled || code == codes.DeadlineExceeded {
+		return status.Error(code, err.Error())
+	}
+
+	appErr, ok := apperrors.AsAppError(err)
+	if !ok {
+		log.Printf("internal error: %v", err)
+		return status.Error(codes.Internal, "внутренняя ошибка")
+	}
+	if code == codes.Internal {
+		log.Printf("server error [%s]: %s: %v", code, appErr.Message, appErr.Err)
+	} else {
+		log.Printf("client error [%s]: %s", code, appErr.Message)
+	}
+	return status.Error(code, appErr.Message)
+}
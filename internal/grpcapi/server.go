@@ -0,0 +1,247 @@
+// Package grpcapi реализует gRPC API кошельков (api/proto/wallet/v1/wallet.proto)
+// поверх того же сервиса, что и HTTP API
+package grpcapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/currency"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated/walletpb"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxIdempotencyKeyLength - максимальная длина ключа идемпотентности
+const maxIdempotencyKeyLength = 255
+
+// Server реализует walletpb.WalletServiceServer
+type Server struct {
+	walletpb.UnimplementedWalletServiceServer
+
+	service       service.WalletService
+	watchInterval time.Duration
+
+	// closed закрывается в Close и завершает открытые потоки WatchBalance
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewServer создаёт gRPC сервер кошельков. watchInterval - период опроса кошелька в WatchBalance
+func NewServer(svc service.WalletService, watchInterval time.Duration) *Server {
+	return &Server{service: svc, watchInterval: watchInterval, closed: make(chan struct{})}
+}
+
+// Close завершает открытые потоки WatchBalance. Потоки бесконечны, поэтому без Close
+// корректная остановка gRPC сервера ждала бы их до истечения таймаута
+func (s *Server) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+func (s *Server) CreateWallet(ctx context.Context, req *walletpb.CreateWalletRequest) (*walletpb.CreateWalletResponse, error) {
+	params := repository.NewWallet{
+		Currency: req.GetCurrency(),
+		OwnerID:  req.GetOwnerId(),
+	}
+	if req.GetWalletId() != "" {
+		walletID, err := parseWalletID(req.GetWalletId())
+		if err != nil || walletID == uuid.Nil {
+			return nil, statusError(apperrors.ErrInvalidWalletID)
+		}
+		params.ID = walletID
+	}
+	if req.ExternalRef != nil {
+		if req.GetExternalRef() == "" {
+			return nil, statusError(apperrors.ErrInvalidExternalRef)
+		}
+		params.ExternalRef = req.GetExternalRef()
+	}
+
+	if req.GetReturnExisting() {
+		wallet, created, err := s.service.GetOrCreateWallet(ctx, params)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		return &walletpb.CreateWalletResponse{Wallet: toWallet(wallet), Created: created}, nil
+	}
+
+	wallet, err := s.service.CreateWallet(ctx, params)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &walletpb.CreateWalletResponse{Wallet: toWallet(wallet), Created: true}, nil
+}
+
+func (s *Server) GetWalletBalance(ctx context.Context, req *walletpb.GetWalletBalanceRequest) (*walletpb.Wallet, error) {
+	walletID, err := parseWalletID(req.GetWalletId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	wallet, err := s.service.GetWallet(ctx, walletID)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toWallet(wallet), nil
+}
+
+func (s *Server) ProcessWalletOperation(ctx context.Context, req *walletpb.ProcessWalletOperationRequest) (*walletpb.ProcessWalletOperationResponse, error) {
+	walletID, err := parseWalletID(req.GetWalletId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if req.GetAmount() <= 0 {
+		return nil, statusError(apperrors.ErrInvalidAmount)
+	}
+
+	op := repository.Operation{
+		WalletID: walletID,
+		Amount:   req.GetAmount(),
+		Currency: req.GetCurrency(),
+	}
+	if req.GetIdempotencyKey() != "" {
+		op.IdempotencyKey, err = newIdempotencyKey(req)
+		if err != nil {
+			return nil, statusError(err)
+		}
+	}
+	if req.ExpectedVersion != nil {
+		op.ExpectedVersions = []int64{req.GetExpectedVersion()}
+	}
+
+	var entry *repository.Transaction
+	switch req.GetOperationType() {
+	case walletpb.OperationType_OPERATION_TYPE_DEPOSIT:
+		entry, err = s.service.Deposit(ctx, op)
+	case walletpb.OperationType_OPERATION_TYPE_WITHDRAW:
+		entry, err = s.service.Withdraw(ctx, op)
+	default:
+		err = apperrors.ErrInvalidOperationType
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &walletpb.ProcessWalletOperationResponse{
+		OperationId:   entry.ID.String(),
+		WalletId:      entry.WalletID.String(),
+		OperationType: req.GetOperationType(),
+		Amount:        req.GetAmount(),
+		Balance:       entry.BalanceAfter,
+		CreatedAt:     timestamppb.New(entry.CreatedAt),
+	}, nil
+}
+
+// WatchBalance опрашивает кошелёк с периодом watchInterval и отправляет его состояние,
+// когда меняется версия. Поток завершается при отмене запроса клиентом, остановке сервера
+// или ошибке чтения кошелька
+func (s *Server) WatchBalance(req *walletpb.WatchBalanceRequest, stream walletpb.WalletService_WatchBalanceServer) error {
+	walletID, err := parseWalletID(req.GetWalletId())
+	if err != nil {
+		return statusError(err)
+	}
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(s.watchInterval)
+	defer ticker.Stop()
+
+	sent := false
+	var version int64
+	for {
+		wallet, err := s.service.GetWallet(ctx, walletID)
+		if err != nil {
+			return statusError(err)
+		}
+		if !sent || wallet.Version != version {
+			if err := stream.Send(toWallet(wallet)); err != nil {
+				return err
+			}
+			sent, version = true, wallet.Version
+		}
+
+		select {
+		case <-ctx.Done():
+			return statusError(ctx.Err())
+		case <-s.closed:
+			return status.Error(codes.Unavailable, "сервер останавливается")
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseWalletID разбирает идентификатор кошелька из запроса
+func parseWalletID(value string) (uuid.UUID, error) {
+	walletID, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, apperrors.ErrInvalidWalletID
+	}
+	return walletID, nil
+}
+
+// newIdempotencyKey валидирует ключ идемпотентности и вычисляет отпечаток запроса.
+// Отпечаток считается по детерминированной сериализации запроса без самого ключа,
+// поэтому повтор с тем же ключом и другими полями отклоняется, как и в HTTP API.
+// Отпечатки HTTP и gRPC запросов различаются: один ключ не стоит использовать в обоих API.
+func newIdempotencyKey(req *walletpb.ProcessWalletOperationRequest) (*repository.IdempotencyKey, error) {
+	key := req.GetIdempotencyKey()
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, apperrors.ErrInvalidIdempotencyKey
+	}
+
+	body := proto.Clone(req).(*walletpb.ProcessWalletOperationRequest)
+	body.IdempotencyKey = ""
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return &repository.IdempotencyKey{
+		Key:         key,
+		Fingerprint: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// toWallet конвертирует кошелёк в сообщение gRPC API
+func toWallet(wallet *repository.Wallet) *walletpb.Wallet {
+	msg := &walletpb.Wallet{
+		WalletId:         wallet.ID.String(),
+		Balance:          wallet.Balance,
+		AvailableBalance: wallet.Available(),
+		CreditLimit:      wallet.CreditLimit,
+		CreditUsed:       wallet.CreditUsed(),
+		Currency:         wallet.Currency,
+		Status:           toWalletStatus(wallet.Status),
+		OwnerId:          wallet.OwnerID,
+		ExternalRef:      wallet.ExternalRef,
+		CreatedAt:        timestamppb.New(wallet.CreatedAt),
+		Version:          wallet.Version,
+	}
+	if c, ok := currency.Lookup(wallet.Currency); ok {
+		minorUnits := int32(c.Exponent)
+		msg.MinorUnits = &minorUnits
+	}
+	return msg
+}
+
+// toWalletStatus конвертирует статус кошелька в перечисление gRPC API
+func toWalletStatus(s repository.WalletStatus) walletpb.WalletStatus {
+	switch s {
+	case repository.WalletActive:
+		return walletpb.WalletStatus_WALLET_STATUS_ACTIVE
+	case repository.WalletFrozen:
+		return walletpb.WalletStatus_WALLET_STATUS_FROZEN
+	case repository.WalletClosed:
+		return walletpb.WalletStatus_WALLET_STATUS_CLOSED
+	default:
+		return walletpb.WalletStatus_WALLET_STATUS_UNSPECIFIED
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/devopesik/wallet-basic-operations/internal/apimodel"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func (h *walletHandler) ChangeWalletStatus(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var req generated.ChangeWalletStatusRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	change := repository.StatusChange{
+		WalletID:        walletID,
+		Status:          repository.WalletStatus(req.Status),
+		Reason:          req.Reason,
+		SweepToWalletID: req.SweepToWalletId,
+	}
+
+	wallet, err := h.service.ChangeWalletStatus(r.Context(), change)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, apimodel.WalletBalanceResponse(wallet), http.StatusOK)
+}
+
+func (h *walletHandler) SetWalletCreditLimit(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var req generated.SetCreditLimitRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	wallet, err := h.service.SetCreditLimit(r.Context(), walletID, req.CreditLimit)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, apimodel.WalletBalanceResponse(wallet), http.StatusOK)
+}
+
+func (h *walletHandler) SetWalletBalanceBuckets(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var req generated.SetBalanceBucketsRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	wallet, err := h.service.SetBalanceBuckets(r.Context(), walletID, req.Buckets)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, apimodel.WalletBalanceResponse(wallet), http.StatusOK)
+}
+
+func (h *walletHandler) GetLastReconciliation(w http.ResponseWriter, r *http.Request) {
+	run, err := h.reconciliation.LastRun(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toReconciliationReport(run), http.StatusOK)
+}
+
+// toReconciliationReport конвертирует результат сверки в модель ответа API
+func toReconciliationReport(run *repository.ReconciliationRun) generated.ReconciliationReport {
+	resp := generated.ReconciliationReport{
+		Id:             run.ID,
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+		AutoCorrect:    run.AutoCorrect,
+		WalletsChecked: run.WalletsChecked,
+		Drifts:         make([]generated.BalanceDrift, 0, len(run.Drifts)),
+	}
+	for _, d := range run.Drifts {
+		drift := generated.BalanceDrift{
+			WalletId:      d.WalletID,
+			Currency:      d.Currency,
+			Balance:       d.Balance,
+			LedgerBalance: d.LedgerBalance,
+			Drift:         d.Drift,
+			Corrected:     d.Corrected,
+		}
+		if d.Error != "" {
+			drift.Error = &d.Error
+		}
+		resp.Drifts = append(resp.Drifts, drift)
+	}
+	return resp
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+)
+
+func (h *walletHandler) ProcessWalletBatch(w http.ResponseWriter, r *http.Request) {
+	var req generated.BatchOperationRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	mode := generated.ATOMIC
+	if req.Mode != nil {
+		mode = *req.Mode
+	}
+
+	ops := make([]repository.BatchOperation, len(req.Items))
+	for i, item := range req.Items {
+		ops[i] = repository.BatchOperation{
+			Type:     repository.TransactionType(item.OperationType),
+			WalletID: item.WalletId,
+			Amount:   item.Amount,
+		}
+		if item.Currency != nil {
+			ops[i].Currency = *item.Currency
+		}
+	}
+
+	results, err := h.batches.ProcessBatch(r.Context(), service.BatchMode(mode), ops)
+	if err != nil {
+		// Отмена атомарного пакета: отвечаем кодом ошибки операции, из-за которой он отменён
+		var itemErr *repository.BatchItemError
+		if stderrors.As(err, &itemErr) {
+			if appErr, ok := apperrors.AsAppError(itemErr.Err); ok && appErr.HTTPStatus() < 500 {
+				resp := generated.BatchOperationResponse{
+					Mode:    mode,
+					Failed:  1,
+					Results: []generated.BatchItemResult{failedBatchItem(itemErr.Index, appErr)},
+				}
+				writeJSON(w, resp, appErr.HTTPStatus())
+				return
+			}
+		}
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.BatchOperationResponse{
+		Mode:    mode,
+		Results: make([]generated.BatchItemResult, len(results)),
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			resp.Failed++
+			resp.Results[i] = failedBatchItem(i, result.Err)
+			continue
+		}
+		resp.Succeeded++
+		t := result.Transaction
+		resp.Results[i] = generated.BatchItemResult{
+			Index:  i,
+			Status: generated.SUCCEEDED,
+			Operation: &generated.WalletOperationResponse{
+				OperationId:   t.ID,
+				WalletId:      t.WalletID,
+				OperationType: req.Items[i].OperationType,
+				Amount:        req.Items[i].Amount,
+				Balance:       t.BalanceAfter,
+				CreatedAt:     t.CreatedAt,
+			},
+		}
+	}
+	writeJSON(w, resp, http.StatusOK)
+}
+
+// failedBatchItem формирует результат неуспешной операции пакета
+func failedBatchItem(index int, err error) generated.BatchItemResult {
+	message := "внутренняя ошибка"
+	if appErr, ok := apperrors.AsAppError(err); ok {
+		message = appErr.Message
+	}
+	return generated.BatchItemResult{
+		Index:  index,
+		Status: generated.FAILED,
+		Error:  &generated.Error{Message: &message},
+	}
+}
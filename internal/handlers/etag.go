@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+)
+
+// walletETag возвращает сильный ETag кошелька с версией version
+func walletETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// etagVersions разбирает список ETag заголовков If-Match и If-None-Match (RFC 9110) в версии кошелька.
+// wildcard - значение "*". Слабые ETag (W/"...") учитываются только при weak: If-Match сравнивает
+// ETag строго, If-None-Match - слабо. ETag не нашего формата ни с одной версией не совпадают и пропускаются.
+func etagVersions(header string, weak bool) (versions []int64, wildcard bool) {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil, true
+		}
+		if rest, ok := strings.CutPrefix(tag, "W/"); ok {
+			if !weak {
+				continue
+			}
+			tag = rest
+		}
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, false
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func (h *walletHandler) CreateHold(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var req generated.CreateHoldRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := validateAmount(req.Amount); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	holdReq := service.HoldRequest{
+		WalletID: walletID,
+		Amount:   req.Amount,
+	}
+	if req.Currency != nil {
+		holdReq.Currency = *req.Currency
+	}
+	if req.TtlSeconds != nil {
+		holdReq.TTL = time.Duration(*req.TtlSeconds) * time.Second
+	}
+
+	hold, err := h.holds.CreateHold(r.Context(), holdReq)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toHoldResponse(hold), http.StatusCreated)
+}
+
+func (h *walletHandler) GetHold(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, holdId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	hold, err := h.holds.GetHold(r.Context(), walletID, holdId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toHoldResponse(hold), http.StatusOK)
+}
+
+func (h *walletHandler) CaptureHold(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, holdId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// Тело запроса необязательно: без него списывается вся заблокированная сумма
+	var req generated.CaptureHoldRequest
+	if err := decodeOptionalJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var amount int64
+	if req.Amount != nil {
+		if err := validateAmount(*req.Amount); err != nil {
+			handleError(w, err)
+			return
+		}
+		amount = *req.Amount
+	}
+
+	hold, err := h.holds.CaptureHold(r.Context(), walletID, holdId, amount)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toHoldResponse(hold), http.StatusOK)
+}
+
+func (h *walletHandler) VoidHold(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, holdId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	hold, err := h.holds.VoidHold(r.Context(), walletID, holdId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toHoldResponse(hold), http.StatusOK)
+}
+
+// toHoldResponse конвертирует блокировку средств в модель ответа API
+func toHoldResponse(hold *repository.Hold) generated.Hold {
+	resp := generated.Hold{
+		HoldId:             hold.ID,
+		WalletId:           hold.WalletID,
+		Amount:             hold.Amount,
+		Status:             generated.HoldStatus(hold.Status),
+		CaptureOperationId: hold.CaptureTransactionID,
+		ExpiresAt:          hold.ExpiresAt,
+		CreatedAt:          hold.CreatedAt,
+	}
+	if hold.Status == repository.HoldCaptured {
+		resp.CapturedAmount = &hold.CapturedAmount
+	}
+	return resp
+}
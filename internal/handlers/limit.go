@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func (h *walletHandler) ListDefaultLimits(w http.ResponseWriter, r *http.Request) {
+	limits, err := h.limits.ListDefaultLimits(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.OperationLimitListResponse{Items: make([]generated.OperationLimit, len(limits))}
+	for i := range limits {
+		resp.Items[i] = toOperationLimitResponse(&limits[i])
+	}
+	writeJSON(w, resp, http.StatusOK)
+}
+
+func (h *walletHandler) SetDefaultLimit(w http.ResponseWriter, r *http.Request, operation generated.LimitOperation) {
+	h.setLimit(w, r, nil, operation)
+}
+
+func (h *walletHandler) DeleteDefaultLimit(w http.ResponseWriter, r *http.Request, operation generated.LimitOperation) {
+	if err := h.limits.DeleteLimit(r.Context(), nil, repository.LimitOperation(operation)); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *walletHandler) GetWalletLimits(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	limits, err := h.limits.GetWalletLimits(r.Context(), walletID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.WalletLimitListResponse{Items: make([]generated.WalletLimit, len(limits))}
+	for i, l := range limits {
+		resp.Items[i] = toWalletLimitResponse(l)
+	}
+	writeJSON(w, resp, http.StatusOK)
+}
+
+func (h *walletHandler) SetWalletLimit(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, operation generated.LimitOperation) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	h.setLimit(w, r, &walletID, operation)
+}
+
+func (h *walletHandler) DeleteWalletLimit(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, operation generated.LimitOperation) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := h.limits.DeleteLimit(r.Context(), &walletID, repository.LimitOperation(operation)); err != nil {
+		handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setLimit сохраняет лимиты из тела запроса: лимиты по умолчанию, если walletID nil, иначе лимиты кошелька
+func (h *walletHandler) setLimit(w http.ResponseWriter, r *http.Request, walletID *uuid.UUID, operation generated.LimitOperation) {
+	var req generated.OperationLimitsRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	limit, err := h.limits.SetLimit(r.Context(), repository.OperationLimit{
+		WalletID:  walletID,
+		Operation: repository.LimitOperation(operation),
+		Limits: repository.OperationLimits{
+			MaxAmount: req.MaxAmount,
+			Daily:     req.Daily,
+			Weekly:    req.Weekly,
+			Monthly:   req.Monthly,
+		},
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toOperationLimitResponse(limit), http.StatusOK)
+}
+
+// toOperationLimitResponse конвертирует лимиты в модель ответа API
+func toOperationLimitResponse(l *repository.OperationLimit) generated.OperationLimit {
+	return generated.OperationLimit{
+		Operation: generated.LimitOperation(l.Operation),
+		WalletId:  l.WalletID,
+		MaxAmount: l.Limits.MaxAmount,
+		Daily:     l.Limits.Daily,
+		Weekly:    l.Limits.Weekly,
+		Monthly:   l.Limits.Monthly,
+		UpdatedAt: l.UpdatedAt,
+	}
+}
+
+// toWalletLimitResponse конвертирует действующие лимиты кошелька в модель ответа API
+func toWalletLimitResponse(l service.WalletLimit) generated.WalletLimit {
+	return generated.WalletLimit{
+		Operation: generated.LimitOperation(l.Operation),
+		Override:  l.Override,
+		MaxAmount: l.Limits.MaxAmount,
+		Daily:     l.Limits.Daily,
+		Weekly:    l.Limits.Weekly,
+		Monthly:   l.Limits.Monthly,
+		Usage: generated.LimitUsage{
+			Daily:   l.Usage.Daily,
+			Weekly:  l.Usage.Weekly,
+			Monthly: l.Usage.Monthly,
+		},
+		Remaining: l.Remaining,
+	}
+}
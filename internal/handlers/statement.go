@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/currency"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// statementWriteTimeout - время на отправку очередных statementDeadlineRows строк выписки.
+// Общий WriteTimeout сервера не даёт передать выписку с большой историей, поэтому срок
+// записи продлевается по мере отправки строк
+const (
+	statementWriteTimeout = 30 * time.Second
+	statementDeadlineRows = 1000
+)
+
+// statementFileTime - формат границ периода в имени файла выписки
+const statementFileTime = "20060102T150405Z"
+
+// statementCSVHeader - заголовок выписки в CSV, имена полей StatementRecord
+var statementCSVHeader = []string{"record", "walletId", "currency", "createdAt", "id", "type", "amount", "balance", "counterpartyWalletId"}
+
+func (h *walletHandler) GetWalletStatement(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, params generated.GetWalletStatementParams) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	format := generated.Json
+	if params.Format != nil {
+		format = *params.Format
+	}
+	if format != generated.Csv && format != generated.Json && format != generated.Ndjson {
+		handleError(w, apperrors.ErrInvalidStatementFormat)
+		return
+	}
+
+	stream := &statementStream{w: w, rc: http.NewResponseController(w), format: format}
+	err = h.service.StreamStatement(r.Context(), walletID, service.StatementQuery{From: params.From, To: params.To}, stream)
+	if err == nil {
+		return
+	}
+	if !stream.started {
+		handleError(w, err)
+		return
+	}
+	// Заголовки и часть выписки уже отправлены: ответ обрывается, чтобы клиент
+	// не принял неполную выписку за полную
+	log.Printf("выписка кошелька %s прервана: %v", walletID, err)
+	panic(http.ErrAbortHandler)
+}
+
+// statementHead - поля документа WalletStatement, известные до чтения операций
+type statementHead struct {
+	WalletId       openapi_types.UUID `json:"walletId"`
+	Currency       string             `json:"currency"`
+	MinorUnits     *int               `json:"minorUnits,omitempty"`
+	From           time.Time          `json:"from"`
+	To             time.Time          `json:"to"`
+	OpeningBalance int64              `json:"openingBalance"`
+}
+
+// statementStream записывает выписку в ответ по мере чтения из БД. Статус и заголовки
+// отправляются в Begin, до этого ошибку ещё можно вернуть обычным ответом
+type statementStream struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	format    generated.GetWalletStatementParamsFormat
+	statement repository.Statement
+	started   bool
+	rows      int
+
+	csv  *csv.Writer
+	json *json.Encoder
+	// first - следующая операция первая в массиве transactions формата json
+	first bool
+}
+
+func (s *statementStream) Begin(statement repository.Statement) error {
+	s.statement = statement
+	s.started = true
+	s.extendDeadline()
+
+	contentType := "application/json"
+	switch s.format {
+	case generated.Csv:
+		contentType = "text/csv; charset=utf-8"
+	case generated.Ndjson:
+		contentType = "application/x-ndjson"
+	}
+	filename := "statement-" + statement.WalletID.String() + "-" + statement.From.UTC().Format(statementFileTime) +
+		"-" + statement.To.UTC().Format(statementFileTime) + "." + string(s.format)
+	s.w.Header().Set("Content-Type", contentType)
+	s.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	s.w.WriteHeader(http.StatusOK)
+
+	switch s.format {
+	case generated.Csv:
+		s.csv = csv.NewWriter(s.w)
+		if err := s.csv.Write(statementCSVHeader); err != nil {
+			return err
+		}
+		return s.record(s.summaryRecord(generated.OPENINGBALANCE, statement.From, statement.OpeningBalance))
+	case generated.Ndjson:
+		s.json = json.NewEncoder(s.w)
+		return s.record(s.summaryRecord(generated.OPENINGBALANCE, statement.From, statement.OpeningBalance))
+	default:
+		// Документ WalletStatement пишется частями: заголовок, операции по одной, итоговый баланс
+		head := statementHead{
+			WalletId:       statement.WalletID,
+			Currency:       statement.Currency,
+			From:           statement.From,
+			To:             statement.To,
+			OpeningBalance: statement.OpeningBalance,
+		}
+		if c, ok := currency.Lookup(statement.Currency); ok {
+			head.MinorUnits = &c.Exponent
+		}
+		data, err := json.Marshal(head)
+		if err != nil {
+			return err
+		}
+		// Поля transactions и closingBalance дописываются после операций
+		data = data[:len(data)-1]
+		data = append(data, `,"transactions":[`...)
+		s.first = true
+		_, err = s.w.Write(data)
+		return err
+	}
+}
+
+func (s *statementStream) Entry(e repository.StatementEntry) error {
+	s.rows++
+	if s.rows%statementDeadlineRows == 0 {
+		s.extendDeadline()
+	}
+
+	if s.format != generated.Json {
+		record := s.summaryRecord(generated.TRANSACTION, e.CreatedAt, e.Balance)
+		record.Id = &e.ID
+		txType := generated.TransactionType(e.Type)
+		record.Type = &txType
+		record.Amount = &e.Amount
+		record.CounterpartyWalletId = e.CounterpartyWalletID
+		return s.record(record)
+	}
+
+	data, err := json.Marshal(generated.StatementEntry{
+		Id:                   e.ID,
+		Type:                 generated.TransactionType(e.Type),
+		Amount:               e.Amount,
+		Balance:              e.Balance,
+		CounterpartyWalletId: e.CounterpartyWalletID,
+		CreatedAt:            e.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if !s.first {
+		data = append([]byte{','}, data...)
+	}
+	s.first = false
+	_, err = s.w.Write(data)
+	return err
+}
+
+func (s *statementStream) End(closingBalance int64) error {
+	switch s.format {
+	case generated.Csv:
+		if err := s.record(s.summaryRecord(generated.CLOSINGBALANCE, s.statement.To, closingBalance)); err != nil {
+			return err
+		}
+		s.csv.Flush()
+		return s.csv.Error()
+	case generated.Ndjson:
+		return s.record(s.summaryRecord(generated.CLOSINGBALANCE, s.statement.To, closingBalance))
+	default:
+		_, err := io.WriteString(s.w, `],"closingBalance":`+strconv.FormatInt(closingBalance, 10)+"}\n")
+		return err
+	}
+}
+
+// summaryRecord возвращает строку выписки с общими для всех строк полями
+func (s *statementStream) summaryRecord(recordType generated.StatementRecordType, at time.Time, balance int64) generated.StatementRecord {
+	return generated.StatementRecord{
+		Record:    recordType,
+		WalletId:  s.statement.WalletID,
+		Currency:  s.statement.Currency,
+		CreatedAt: at,
+		Balance:   balance,
+	}
+}
+
+// record записывает строку выписки в формате ndjson или csv
+func (s *statementStream) record(r generated.StatementRecord) error {
+	if s.json != nil {
+		return s.json.Encode(r)
+	}
+	return s.csv.Write([]string{
+		string(r.Record),
+		r.WalletId.String(),
+		r.Currency,
+		r.CreatedAt.Format(time.RFC3339Nano),
+		csvValue(r.Id),
+		csvValue(r.Type),
+		csvValue(r.Amount),
+		strconv.FormatInt(r.Balance, 10),
+		csvValue(r.CounterpartyWalletId),
+	})
+}
+
+// extendDeadline продлевает срок записи ответа. Если ResponseWriter не поддерживает сроки
+// записи, действует только общий WriteTimeout сервера
+func (s *statementStream) extendDeadline() {
+	_ = s.rc.SetWriteDeadline(time.Now().Add(statementWriteTimeout))
+}
+
+// csvValue выводит необязательное поле строки выписки; отсутствующее поле - пустая ячейка
+func csvValue[T any](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
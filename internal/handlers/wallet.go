@@ -2,25 +2,37 @@ package handler
 
 import (
 	"encoding/json"
+	stderrors "errors"
+	"io"
 	"log"
 	"net/http"
+	"slices"
+	"strings"
 
+	"github.com/devopesik/wallet-basic-operations/internal/apimodel"
 	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
 	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
 	"github.com/devopesik/wallet-basic-operations/internal/service"
 	"github.com/google/uuid"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
 type walletHandler struct {
-	service service.WalletService
+	service        service.WalletService
+	holds          service.HoldService
+	batches        service.BatchService
+	reconciliation service.ReconciliationService
+	webhooks       service.WebhookService
+	limits         service.LimitService
 }
 
-func NewWalletHandler(svc service.WalletService) generated.ServerInterface {
-	return &walletHandler{service: svc}
+func NewWalletHandler(svc service.WalletService, holds service.HoldService, batches service.BatchService,
+	reconciliation service.ReconciliationService, webhooks service.WebhookService, limits service.LimitService) generated.ServerInterface {
+	return &walletHandler{service: svc, holds: holds, batches: batches, reconciliation: reconciliation, webhooks: webhooks, limits: limits}
 }
 
-func (h *walletHandler) ProcessWalletOperation(w http.ResponseWriter, r *http.Request) {
+func (h *walletHandler) ProcessWalletOperation(w http.ResponseWriter, r *http.Request, params generated.ProcessWalletOperationParams) {
 	req, err := validateWalletOperationRequest(r)
 	if err != nil {
 		handleError(w, err)
@@ -43,11 +55,38 @@ func (h *walletHandler) ProcessWalletOperation(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	op := repository.Operation{
+		WalletID: walletID,
+		Amount:   req.Amount,
+	}
+	if req.Currency != nil {
+		op.Currency = *req.Currency
+	}
+	if params.IdempotencyKey != nil {
+		op.IdempotencyKey, err = apimodel.NewIdempotencyKey(*params.IdempotencyKey, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+	if params.IfMatch != nil {
+		versions, wildcard := etagVersions(*params.IfMatch, false)
+		if !wildcard {
+			// Ни один ETag не может совпасть с версией кошелька: условие заведомо ложно
+			if len(versions) == 0 {
+				handleError(w, apperrors.ErrVersionMismatch)
+				return
+			}
+			op.ExpectedVersions = versions
+		}
+	}
+
+	var entry *repository.Transaction
 	switch req.OperationType {
-	case generated.DEPOSIT:
-		err = h.service.Deposit(r.Context(), walletID, req.Amount)
-	case generated.WITHDRAW:
-		err = h.service.Withdraw(r.Context(), walletID, req.Amount)
+	case generated.OperationTypeDEPOSIT:
+		entry, err = h.service.Deposit(r.Context(), op)
+	case generated.OperationTypeWITHDRAW:
+		entry, err = h.service.Withdraw(r.Context(), op)
 	}
 
 	if err != nil {
@@ -55,10 +94,78 @@ func (h *walletHandler) ProcessWalletOperation(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	if preferMinimal(params.Prefer) {
+		writeNoContent(w)
+		return
+	}
+
+	writeJSON(w, apimodel.WalletOperationResponse(entry, *req), http.StatusOK)
+}
+
+func (h *walletHandler) TransferFunds(w http.ResponseWriter, r *http.Request, params generated.TransferFundsParams) {
+	var req generated.TransferRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	fromWalletID, err := validateWalletID(req.FromWalletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	toWalletID, err := validateWalletID(req.ToWalletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := validateAmount(req.Amount); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	transfer := repository.Transfer{
+		FromWalletID: fromWalletID,
+		ToWalletID:   toWalletID,
+		Amount:       req.Amount,
+	}
+	if req.Currency != nil {
+		transfer.Currency = *req.Currency
+	}
+	if params.IdempotencyKey != nil {
+		transfer.IdempotencyKey, err = apimodel.NewIdempotencyKey(*params.IdempotencyKey, req)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+
+	result, err := h.service.Transfer(r.Context(), transfer)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if preferMinimal(params.Prefer) {
+		writeNoContent(w)
+		return
+	}
+
+	resp := generated.TransferResponse{
+		OperationId:  result.Debit.ID,
+		FromWalletId: result.Debit.WalletID,
+		ToWalletId:   result.Credit.WalletID,
+		Amount:       req.Amount,
+		FromBalance:  result.Debit.BalanceAfter,
+		ToBalance:    result.Credit.BalanceAfter,
+		CreatedAt:    result.Debit.CreatedAt,
+	}
+	writeJSON(w, resp, http.StatusOK)
 }
 
-func (h *walletHandler) GetWalletBalance(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID) {
+func (h *walletHandler) GetWalletBalance(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, params generated.GetWalletBalanceParams) {
 	walletID, err := validateWalletID(walletId)
 	if err != nil {
 		handleError(w, err)
@@ -71,27 +178,209 @@ func (h *walletHandler) GetWalletBalance(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	resp := generated.WalletBalanceResponse{
-		WalletId: &walletId,
-		Balance:  &wallet.Balance,
+	w.Header().Set("ETag", walletETag(wallet.Version))
+	if params.IfNoneMatch != nil {
+		versions, wildcard := etagVersions(*params.IfNoneMatch, true)
+		if wildcard || slices.Contains(versions, wallet.Version) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	writeJSON(w, apimodel.WalletBalanceResponse(wallet), http.StatusOK)
+}
+
+func (h *walletHandler) GetWalletBalanceAt(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, params generated.GetWalletBalanceAtParams) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	balance, err := h.service.GetBalanceAt(r.Context(), walletID, params.At)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.WalletHistoricalBalanceResponse{
+		WalletId: balance.WalletID,
+		Currency: balance.Currency,
+		At:       balance.At,
+		Balance:  balance.Balance,
+	}
+	writeJSON(w, resp, http.StatusOK)
+}
+
+func (h *walletHandler) ListWalletTransactions(w http.ResponseWriter, r *http.Request, walletId openapi_types.UUID, params generated.ListWalletTransactionsParams) {
+	walletID, err := validateWalletID(walletId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	query := service.TransactionQuery{
+		From: params.From,
+		To:   params.To,
+	}
+	if params.Type != nil {
+		if err := validateTransactionType(*params.Type); err != nil {
+			handleError(w, err)
+			return
+		}
+		txType := repository.TransactionType(*params.Type)
+		query.Type = &txType
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			handleError(w, apperrors.ErrInvalidLimit)
+			return
+		}
+		query.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		query.Cursor = *params.Cursor
+	}
+
+	page, err := h.service.ListTransactions(r.Context(), walletID, query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.TransactionListResponse{
+		Items: make([]generated.Transaction, 0, len(page.Items)),
+	}
+	for _, t := range page.Items {
+		resp.Items = append(resp.Items, apimodel.TransactionResponse(t))
+	}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
 	}
 	writeJSON(w, resp, http.StatusOK)
 }
 
 func (h *walletHandler) CreateWallet(w http.ResponseWriter, r *http.Request) {
-	wallet, err := h.service.CreateWallet(r.Context())
+	// Тело запроса необязательно: без него создаётся кошелёк в валюте по умолчанию
+	var req generated.CreateWalletRequest
+	if err := decodeOptionalJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	params := repository.NewWallet{}
+	if req.Currency != nil {
+		params.Currency = *req.Currency
+	}
+	if req.WalletId != nil {
+		walletID, err := validateWalletID(*req.WalletId)
+		if err != nil || walletID == uuid.Nil {
+			handleError(w, apperrors.ErrInvalidWalletID)
+			return
+		}
+		params.ID = walletID
+	}
+	if req.OwnerId != nil {
+		params.OwnerID = *req.OwnerId
+	}
+	if req.ExternalRef != nil {
+		if *req.ExternalRef == "" {
+			handleError(w, apperrors.ErrInvalidExternalRef)
+			return
+		}
+		params.ExternalRef = *req.ExternalRef
+	}
+
+	if req.ReturnExisting != nil && *req.ReturnExisting {
+		wallet, created, err := h.service.GetOrCreateWallet(r.Context(), params)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		writeJSON(w, apimodel.WalletBalanceResponse(wallet), status)
+		return
+	}
+
+	wallet, err := h.service.CreateWallet(r.Context(), params)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, apimodel.WalletBalanceResponse(wallet), http.StatusCreated)
+}
+
+func (h *walletHandler) ListWallets(w http.ResponseWriter, r *http.Request, params generated.ListWalletsParams) {
+	query := service.WalletQuery{
+		OwnerID:     params.OwnerId,
+		ExternalRef: params.ExternalRef,
+		MinBalance:  params.MinBalance,
+		MaxBalance:  params.MaxBalance,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		// По умолчанию сначала новые кошельки
+		Descending: true,
+	}
+	if params.Status != nil {
+		status := repository.WalletStatus(*params.Status)
+		query.Status = &status
+	}
+	if params.Currency != nil {
+		query.Currency = *params.Currency
+	}
+	if params.Sort != nil {
+		switch *params.Sort {
+		case generated.CreatedAt:
+			query.Sort = repository.WalletSortCreatedAt
+		case generated.Balance:
+			query.Sort = repository.WalletSortBalance
+		default:
+			handleError(w, apperrors.ErrInvalidSort)
+			return
+		}
+	}
+	if params.Order != nil {
+		switch *params.Order {
+		case generated.Asc:
+			query.Descending = false
+		case generated.Desc:
+			query.Descending = true
+		default:
+			handleError(w, apperrors.ErrInvalidSort)
+			return
+		}
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			handleError(w, apperrors.ErrInvalidLimit)
+			return
+		}
+		query.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		query.Cursor = *params.Cursor
+	}
+
+	page, err := h.service.ListWallets(r.Context(), query)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	// Конвертируем uuid.UUID в openapi_types.UUID для ответа
-	walletIdResponse := openapi_types.UUID(wallet.ID)
-	resp := generated.WalletBalanceResponse{
-		WalletId: &walletIdResponse,
-		Balance:  &wallet.Balance,
+	resp := generated.WalletListResponse{
+		Items: make([]generated.WalletBalanceResponse, 0, len(page.Items)),
 	}
-	writeJSON(w, resp, http.StatusCreated)
+	for i := range page.Items {
+		resp.Items = append(resp.Items, apimodel.WalletBalanceResponse(&page.Items[i]))
+	}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
+	}
+	writeJSON(w, resp, http.StatusOK)
 }
 
 func (h *walletHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
@@ -112,6 +401,12 @@ func writeJSON(w http.ResponseWriter, v interface{}, status int) {
 	}
 }
 
+// writeNoContent отвечает 204 и сообщает клиенту, что предпочтение return=minimal учтено
+func writeNoContent(w http.ResponseWriter) {
+	w.Header().Set("Preference-Applied", "return=minimal")
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func writeJSONError(w http.ResponseWriter, message string, status int) {
 	errResp := generated.Error{Message: &message}
 	writeJSON(w, errResp, status)
@@ -119,15 +414,35 @@ func writeJSONError(w http.ResponseWriter, message string, status int) {
 
 // validateWalletOperationRequest валидирует и декодирует запрос на операцию с кошельком
 func validateWalletOperationRequest(r *http.Request) (*generated.WalletOperationRequest, error) {
+	var req generated.WalletOperationRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// decodeOptionalJSONBody декодирует необязательное JSON тело запроса, пустое тело допустимо
+func decodeOptionalJSONBody(r *http.Request, v any) error {
 	// Ограничиваем размер тела запроса для защиты от больших запросов (1MB)
 	r.Body = http.MaxBytesReader(nil, r.Body, 1<<20)
 	defer r.Body.Close()
 
-	var req generated.WalletOperationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		return nil, apperrors.ErrInvalidJSON
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil && !stderrors.Is(err, io.EOF) {
+		return apperrors.ErrInvalidJSON
 	}
-	return &req, nil
+	return nil
+}
+
+// decodeJSONBody декодирует JSON тело запроса в v
+func decodeJSONBody(r *http.Request, v any) error {
+	// Ограничиваем размер тела запроса для защиты от больших запросов (1MB)
+	r.Body = http.MaxBytesReader(nil, r.Body, 1<<20)
+	defer r.Body.Close()
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return apperrors.ErrInvalidJSON
+	}
+	return nil
 }
 
 // validateWalletID валидирует и конвертирует openapi_types.UUID в uuid.UUID
@@ -148,9 +463,36 @@ func validateAmount(amount int64) error {
 }
 
 // validateOperationType валидирует тип операции
-func validateOperationType(opType generated.WalletOperationRequestOperationType) error {
+func validateOperationType(opType generated.OperationType) error {
 	switch opType {
-	case generated.DEPOSIT, generated.WITHDRAW:
+	case generated.OperationTypeDEPOSIT, generated.OperationTypeWITHDRAW:
+		return nil
+	default:
+		return apperrors.ErrInvalidOperationType
+	}
+}
+
+// preferMinimal проверяет, запросил ли клиент ответ без тела через заголовок Prefer (RFC 7240)
+func preferMinimal(prefer *string) bool {
+	if prefer == nil {
+		return false
+	}
+	for _, pref := range strings.Split(*prefer, ",") {
+		// Параметры предпочтения отделяются точкой с запятой и для return не используются
+		token, _, _ := strings.Cut(pref, ";")
+		if strings.EqualFold(strings.ReplaceAll(token, " ", ""), "return=minimal") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTransactionType валидирует тип записи истории операций
+func validateTransactionType(txType generated.TransactionType) error {
+	switch txType {
+	case generated.TransactionTypeDEPOSIT, generated.TransactionTypeWITHDRAW,
+		generated.TransactionTypeTRANSFERIN, generated.TransactionTypeTRANSFEROUT,
+		generated.TransactionTypeHOLDCAPTURE, generated.TransactionTypeADJUSTMENT:
 		return nil
 	default:
 		return apperrors.ErrInvalidOperationType
@@ -178,5 +520,18 @@ func handleError(w http.ResponseWriter, err error) {
 		log.Printf("client error [%d]: %s", statusCode, appErr.Message)
 	}
 
+	// Превышение лимита возвращается вместе с доступным остатком
+	if details, ok := appErr.Details.(apperrors.LimitExceededDetails); ok {
+		writeJSON(w, generated.LimitExceededError{
+			Message:   appErr.Message,
+			Operation: generated.LimitOperation(details.Operation),
+			Period:    generated.LimitPeriod(details.Period),
+			Limit:     details.Limit,
+			Used:      details.Used,
+			Remaining: details.Remaining,
+		}, statusCode)
+		return
+	}
+
 	writeJSONError(w, appErr.Message, statusCode)
 }
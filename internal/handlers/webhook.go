@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func (h *walletHandler) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.webhooks.ListSubscriptions(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.WebhookSubscriptionListResponse{
+		Items: make([]generated.WebhookSubscription, 0, len(subs)),
+	}
+	for i := range subs {
+		resp.Items = append(resp.Items, toWebhookSubscriptionResponse(&subs[i]))
+	}
+	writeJSON(w, resp, http.StatusOK)
+}
+
+func (h *walletHandler) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var req generated.WebhookSubscriptionRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sub, err := h.webhooks.CreateSubscription(r.Context(), toWebhookSubscriptionParams(req))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// Ключ подписи показывается только при создании подписки
+	resp := toWebhookSubscriptionResponse(sub)
+	resp.Secret = &sub.Secret
+	writeJSON(w, resp, http.StatusCreated)
+}
+
+func (h *walletHandler) GetWebhookSubscription(w http.ResponseWriter, r *http.Request, subscriptionId openapi_types.UUID) {
+	sub, err := h.webhooks.GetSubscription(r.Context(), subscriptionId)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toWebhookSubscriptionResponse(sub), http.StatusOK)
+}
+
+func (h *walletHandler) UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request, subscriptionId openapi_types.UUID) {
+	var req generated.WebhookSubscriptionRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sub, err := h.webhooks.UpdateSubscription(r.Context(), subscriptionId, toWebhookSubscriptionParams(req))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, toWebhookSubscriptionResponse(sub), http.StatusOK)
+}
+
+func (h *walletHandler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request, subscriptionId openapi_types.UUID) {
+	if err := h.webhooks.DeleteSubscription(r.Context(), subscriptionId); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *walletHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request, subscriptionId openapi_types.UUID, params generated.ListWebhookDeliveriesParams) {
+	var query service.WebhookDeliveryQuery
+	if params.Status != nil {
+		status := repository.WebhookDeliveryStatus(*params.Status)
+		query.Status = &status
+	}
+	if params.Limit != nil {
+		if *params.Limit < 1 {
+			handleError(w, apperrors.ErrInvalidLimit)
+			return
+		}
+		query.Limit = *params.Limit
+	}
+	if params.Cursor != nil {
+		query.Cursor = *params.Cursor
+	}
+
+	page, err := h.webhooks.ListDeliveries(r.Context(), subscriptionId, query)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := generated.WebhookDeliveryListResponse{
+		Items: make([]generated.WebhookDelivery, 0, len(page.Items)),
+	}
+	for _, d := range page.Items {
+		resp.Items = append(resp.Items, generated.WebhookDelivery{
+			Id:             d.ID,
+			SubscriptionId: d.SubscriptionID,
+			EventId:        d.EventID,
+			EventType:      generated.EventType(d.EventType),
+			Status:         generated.WebhookDeliveryStatus(d.Status),
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			LastStatusCode: d.LastStatusCode,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		})
+	}
+	if page.NextCursor != "" {
+		resp.NextCursor = &page.NextCursor
+	}
+	writeJSON(w, resp, http.StatusOK)
+}
+
+func (h *walletHandler) ReplayWebhookDeliveries(w http.ResponseWriter, r *http.Request, subscriptionId openapi_types.UUID) {
+	// Тело запроса необязательно: без него повторяются все доставки в статусе DEAD
+	var req generated.ReplayWebhookDeliveriesRequest
+	if err := decodeOptionalJSONBody(r, &req); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var deliveryIDs []openapi_types.UUID
+	if req.DeliveryIds != nil {
+		deliveryIDs = *req.DeliveryIds
+	}
+	replayed, err := h.webhooks.ReplayDeliveries(r.Context(), subscriptionId, deliveryIDs)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, generated.ReplayWebhookDeliveriesResponse{Replayed: replayed}, http.StatusOK)
+}
+
+// toWebhookSubscriptionParams конвертирует запрос API в настройки подписки.
+// Подписка без явного active создаётся активной.
+func toWebhookSubscriptionParams(req generated.WebhookSubscriptionRequest) repository.WebhookSubscriptionParams {
+	params := repository.WebhookSubscriptionParams{
+		URL:      req.Url,
+		WalletID: req.WalletId,
+		Active:   req.Active == nil || *req.Active,
+	}
+	if req.EventTypes != nil {
+		for _, t := range *req.EventTypes {
+			params.EventTypes = append(params.EventTypes, string(t))
+		}
+	}
+	return params
+}
+
+// toWebhookSubscriptionResponse конвертирует подписку в модель ответа API без ключа подписи
+func toWebhookSubscriptionResponse(sub *repository.WebhookSubscription) generated.WebhookSubscription {
+	resp := generated.WebhookSubscription{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: make([]generated.EventType, 0, len(sub.EventTypes)),
+		WalletId:   sub.WalletID,
+		Active:     sub.Active,
+		CreatedAt:  sub.CreatedAt,
+		UpdatedAt:  sub.UpdatedAt,
+	}
+	for _, t := range sub.EventTypes {
+		resp.EventTypes = append(resp.EventTypes, generated.EventType(t))
+	}
+	return resp
+}
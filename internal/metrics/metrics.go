@@ -0,0 +1,33 @@
+// Package metrics содержит счётчики приложения. Счётчики публикуются через expvar
+// и доступны в JSON на /debug/vars.
+package metrics
+
+import "expvar"
+
+var (
+	// DepositBatches - число транзакций, в которых группировка применила пополнения: пакетов
+	// и отдельных транзакций одиночных пополнений и пополнений шардированных кошельков
+	DepositBatches = expvar.NewInt("deposit_batches_total")
+	// DepositsCoalesced - число пополнений, применённых через группировку, включая выполненные отдельно
+	DepositsCoalesced = expvar.NewInt("deposits_coalesced_total")
+	// TxRetries - число повторов транзакций по кодам SQLSTATE вызвавших их ошибок
+	TxRetries = expvar.NewMap("tx_retries_total")
+	// TxRetriesExhausted - число транзакций, завершившихся ошибкой конфликта без повтора:
+	// попытки исчерпаны или повтор не укладывается в срок запроса
+	TxRetriesExhausted = expvar.NewInt("tx_retries_exhausted_total")
+)
+
+func init() {
+	// Коэффициент группировки - среднее число пополнений в одной транзакции
+	expvar.Publish("deposit_coalescing_ratio", expvar.Func(func() any {
+		return ratio(DepositsCoalesced.Value(), DepositBatches.Value())
+	}))
+}
+
+// ratio возвращает n/d или 0, если d равен нулю
+func ratio(n, d int64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
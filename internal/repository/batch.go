@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// BatchOperation описывает одну операцию пакета: пополнение (TransactionDeposit)
+// или списание (TransactionWithdraw). Currency необязательна.
+type BatchOperation struct {
+	Type     TransactionType
+	WalletID uuid.UUID
+	Amount   int64
+	Currency string
+}
+
+// BatchItemResult - результат операции пакета: запись истории при успехе или ошибка
+type BatchItemResult struct {
+	Transaction *Transaction
+	Err         error
+}
+
+// BatchItemError сообщает, какая операция атомарного пакета привела к его отмене
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("операция %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HoldStatus представляет состояние блокировки средств
+type HoldStatus string
+
+const (
+	HoldActive   HoldStatus = "ACTIVE"
+	HoldCaptured HoldStatus = "CAPTURED"
+	HoldVoided   HoldStatus = "VOIDED"
+	HoldExpired  HoldStatus = "EXPIRED"
+)
+
+// Hold представляет блокировку средств кошелька.
+// Активная блокировка уменьшает доступный баланс, но не меняет баланс кошелька;
+// баланс уменьшается только при списании (capture) заблокированной суммы.
+type Hold struct {
+	ID                   uuid.UUID
+	WalletID             uuid.UUID
+	Amount               int64
+	CapturedAmount       int64
+	Status               HoldStatus
+	CaptureTransactionID *uuid.UUID
+	ExpiresAt            time.Time
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// NewHold описывает параметры создания блокировки.
+// Currency необязательна: если задана, она должна совпадать с валютой кошелька.
+type NewHold struct {
+	WalletID  uuid.UUID
+	Amount    int64
+	Currency  string
+	ExpiresAt time.Time
+}
+
+type HoldRepository interface {
+	CreateHold(ctx context.Context, params NewHold) (*Hold, error)
+	GetHold(ctx context.Context, walletID, holdID uuid.UUID) (*Hold, error)
+	// CaptureHold списывает amount из активной блокировки и снимает её остаток
+	CaptureHold(ctx context.Context, walletID, holdID uuid.UUID, amount int64) (*Hold, error)
+	// VoidHold снимает активную блокировку без списания
+	VoidHold(ctx context.Context, walletID, holdID uuid.UUID) (*Hold, error)
+	// ExpireHolds снимает активные блокировки со сроком действия до now, не больше limit за вызов
+	ExpireHolds(ctx context.Context, now time.Time, limit int) (int64, error)
+}
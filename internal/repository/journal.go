@@ -0,0 +1,28 @@
+package repository
+
+// SystemAccount - код системного счёта журнала двойной записи.
+// Системные счета - вторая сторона операций, которые вводят деньги в систему или выводят их из неё.
+type SystemAccount string
+
+const (
+	// SystemAccountExternalFunding - источник пополнений кошельков
+	SystemAccountExternalFunding SystemAccount = "EXTERNAL_FUNDING"
+	// SystemAccountPayouts - получатель списаний с кошельков
+	SystemAccountPayouts SystemAccount = "PAYOUTS"
+	// SystemAccountFees - комиссии сервиса
+	SystemAccountFees SystemAccount = "FEES"
+	// SystemAccountReconciliation - расхождения, выявленные сверкой балансов, до их разбора
+	SystemAccountReconciliation SystemAccount = "RECONCILIATION"
+)
+
+// JournalEntryType представляет тип проводки журнала
+type JournalEntryType string
+
+const (
+	JournalOpeningBalance JournalEntryType = "OPENING_BALANCE"
+	JournalDeposit        JournalEntryType = "DEPOSIT"
+	JournalWithdraw       JournalEntryType = "WITHDRAW"
+	JournalTransfer       JournalEntryType = "TRANSFER"
+	JournalHoldCapture    JournalEntryType = "HOLD_CAPTURE"
+	JournalAdjustment     JournalEntryType = "ADJUSTMENT"
+)
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LimitOperation - вид операций, на который действуют лимиты
+type LimitOperation string
+
+const (
+	// LimitDeposit - пополнения
+	LimitDeposit LimitOperation = "DEPOSIT"
+	// LimitWithdraw - списания, в том числе списания заблокированных средств
+	LimitWithdraw LimitOperation = "WITHDRAW"
+	// LimitTransfer - исходящие переводы
+	LimitTransfer LimitOperation = "TRANSFER"
+)
+
+// LimitOperations - все виды операций с лимитами
+var LimitOperations = []LimitOperation{LimitDeposit, LimitWithdraw, LimitTransfer}
+
+// LimitPeriod - ограничение, которое нарушила операция
+type LimitPeriod string
+
+const (
+	LimitPerOperation LimitPeriod = "OPERATION"
+	LimitDaily        LimitPeriod = "DAILY"
+	LimitWeekly       LimitPeriod = "WEEKLY"
+	LimitMonthly      LimitPeriod = "MONTHLY"
+)
+
+// OperationLimits - лимиты одного вида операций: максимальная сумма операции и суммы операций
+// за скользящие 24 часа, 7 и 30 дней. nil означает отсутствие ограничения.
+type OperationLimits struct {
+	MaxAmount *int64
+	Daily     *int64
+	Weekly    *int64
+	Monthly   *int64
+}
+
+// LimitUsage - суммы операций кошелька за скользящие окна лимитов
+type LimitUsage struct {
+	Daily   int64
+	Weekly  int64
+	Monthly int64
+}
+
+// LimitViolation описывает ограничение, которое не позволяет выполнить операцию.
+// Remaining - сумма, которую кошелёк может провести прямо сейчас с учётом всех лимитов.
+type LimitViolation struct {
+	Operation LimitOperation
+	Period    LimitPeriod
+	Limit     int64
+	Used      int64
+	Remaining int64
+}
+
+// Allowance возвращает сумму, которую можно провести одной операцией при использовании usage,
+// и самое строгое ограничение. limited равен false, если лимиты не заданы.
+func (l OperationLimits) Allowance(usage LimitUsage) (remaining int64, period LimitPeriod, limited bool) {
+	candidates := []struct {
+		period LimitPeriod
+		limit  *int64
+		used   int64
+	}{
+		{LimitPerOperation, l.MaxAmount, 0},
+		{LimitDaily, l.Daily, usage.Daily},
+		{LimitWeekly, l.Weekly, usage.Weekly},
+		{LimitMonthly, l.Monthly, usage.Monthly},
+	}
+	for _, c := range candidates {
+		if c.limit == nil {
+			continue
+		}
+		left := max(*c.limit-c.used, 0)
+		if !limited || left < remaining {
+			remaining, period, limited = left, c.period, true
+		}
+	}
+	return remaining, period, limited
+}
+
+// Check проверяет, что операция на amount укладывается в лимиты при использовании usage.
+// Возвращает nil, если операция допустима.
+func (l OperationLimits) Check(operation LimitOperation, amount int64, usage LimitUsage) *LimitViolation {
+	remaining, period, limited := l.Allowance(usage)
+	if !limited || amount <= remaining {
+		return nil
+	}
+	violation := &LimitViolation{Operation: operation, Period: period, Remaining: remaining}
+	switch period {
+	case LimitPerOperation:
+		violation.Limit = *l.MaxAmount
+	case LimitDaily:
+		violation.Limit, violation.Used = *l.Daily, usage.Daily
+	case LimitWeekly:
+		violation.Limit, violation.Used = *l.Weekly, usage.Weekly
+	case LimitMonthly:
+		violation.Limit, violation.Used = *l.Monthly, usage.Monthly
+	}
+	return violation
+}
+
+// OperationLimit - лимиты вида операций: глобальные по умолчанию (WalletID nil) или кошелька
+type OperationLimit struct {
+	WalletID  *uuid.UUID
+	Operation LimitOperation
+	Limits    OperationLimits
+	UpdatedAt time.Time
+}
+
+// WalletLimitStatus - действующие лимиты вида операций кошелька и их использование.
+// Override равен true, если лимиты заданы для кошелька, а не взяты по умолчанию.
+type WalletLimitStatus struct {
+	Operation LimitOperation
+	Limits    OperationLimits
+	Override  bool
+	Usage     LimitUsage
+}
+
+type LimitRepository interface {
+	// ListDefaultLimits возвращает глобальные лимиты по умолчанию
+	ListDefaultLimits(ctx context.Context) ([]OperationLimit, error)
+	// SetLimit создаёт или заменяет лимиты: глобальные, если WalletID nil, иначе лимиты кошелька
+	SetLimit(ctx context.Context, limit OperationLimit) (*OperationLimit, error)
+	// DeleteLimit удаляет лимиты; после удаления лимитов кошелька действуют лимиты по умолчанию
+	DeleteLimit(ctx context.Context, walletID *uuid.UUID, operation LimitOperation) error
+	// GetWalletLimits возвращает действующие лимиты и их использование по каждому виду операций
+	GetWalletLimits(ctx context.Context, walletID uuid.UUID) ([]WalletLimitStatus, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent - доменное событие, записанное в outbox в транзакции изменения кошелька.
+// Sequence задаёт порядок доставки, Attempts - число неудачных попыток доставки.
+type OutboxEvent struct {
+	Sequence  int64
+	EventID   uuid.UUID
+	WalletID  uuid.UUID
+	Type      string
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+type OutboxRepository interface {
+	// RelayOutbox передаёт publish до limit неопубликованных событий в порядке записи и отмечает
+	// доставленные. Доставляет только события, до которых завершены все транзакции, получившие
+	// sequence, поэтому событие не доставляется раньше события с меньшим sequence. Транзакции,
+	// записывающие события, доставку не ждут. После ошибки доставки остальные события того же
+	// кошелька в этом вызове пропускаются, чтобы не нарушить порядок. publish вызывается вне
+	// транзакций базы данных. Одновременно события доставляет только один вызов,
+	// в остальных RelayOutbox сразу возвращает 0. Возвращает число доставленных событий.
+	RelayOutbox(ctx context.Context, limit int, publish func(OutboxEvent) error) (int, error)
+	// DeletePublishedOutboxEvents удаляет события, доставленные раньше before
+	DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error)
+}
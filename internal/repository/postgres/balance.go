@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *walletRepository) GetBalanceAt(ctx context.Context, walletID uuid.UUID, at time.Time) (*repository.HistoricalBalance, error) {
+	// Суммируются только записи после ближайшего снимка, поэтому время запроса
+	// ограничено периодом снимков, а не длиной всей истории кошелька
+	balance := &repository.HistoricalBalance{WalletID: walletID, At: at}
+	err := r.pool.QueryRow(ctx, `SELECT w.currency,
+			COALESCE(s.balance, 0) + COALESCE((
+				SELECT sum(t.amount)
+				FROM wallet_transactions t
+				WHERE t.wallet_id = w.id
+					AND t.created_at > COALESCE(s.taken_at, '-infinity')
+					AND t.created_at <= $2
+			), 0)
+		FROM wallets w
+		LEFT JOIN LATERAL (
+			SELECT balance, taken_at
+			FROM wallet_balance_snapshots
+			WHERE wallet_id = w.id AND taken_at <= $2
+			ORDER BY taken_at DESC
+			LIMIT 1
+		) s ON true
+		WHERE w.id = $1 AND w.created_at <= $2`,
+		walletID, at).Scan(&balance.Currency, &balance.Balance)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrWalletNotFound
+		}
+		return nil, apperrors.NewDatabaseError("получении баланса на момент времени", err)
+	}
+	return balance, nil
+}
+
+func (r *walletRepository) SnapshotBalances(ctx context.Context, upTo time.Time) (int64, error) {
+	// После каждого запуска последний снимок любого кошелька учитывает его историю до момента запуска,
+	// поэтому новый снимок нужен только кошелькам с операциями после самого позднего снимка
+	result, err := r.pool.Exec(ctx, `WITH since AS (
+			SELECT COALESCE(max(taken_at), '-infinity') AS taken_at FROM wallet_balance_snapshots
+		), delta AS (
+			SELECT wallet_id, sum(amount) AS amount
+			FROM wallet_transactions
+			WHERE created_at > (SELECT taken_at FROM since) AND created_at <= $1
+			GROUP BY wallet_id
+		)
+		INSERT INTO wallet_balance_snapshots (wallet_id, taken_at, balance)
+		SELECT d.wallet_id, $1, COALESCE(s.balance, 0) + d.amount
+		FROM delta d
+		LEFT JOIN LATERAL (
+			SELECT balance
+			FROM wallet_balance_snapshots
+			WHERE wallet_id = d.wallet_id
+			ORDER BY taken_at DESC
+			LIMIT 1
+		) s ON true
+		ON CONFLICT (wallet_id, taken_at) DO NOTHING`, upTo)
+	if err != nil {
+		return 0, apperrors.NewDatabaseError("снятии снимков балансов", err)
+	}
+	return result.RowsAffected(), nil
+}
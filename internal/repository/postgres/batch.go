@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *walletRepository) ApplyBatch(ctx context.Context, ops []repository.BatchOperation, atomic bool) ([]repository.BatchItemResult, error) {
+	return runTx(ctx, r.tx, "пакета операций", readCommitted, func(tx pgx.Tx) ([]repository.BatchItemResult, error) {
+		// Все кошельки пакета блокируются одним запросом в порядке возрастания id
+		ids := make([]uuid.UUID, 0, len(ops))
+		seen := make(map[uuid.UUID]bool, len(ops))
+		for _, op := range ops {
+			if !seen[op.WalletID] {
+				seen[op.WalletID] = true
+				ids = append(ids, op.WalletID)
+			}
+		}
+		wallets, err := lockWallets(ctx, tx, ids...)
+		if err != nil {
+			return nil, err
+		}
+
+		// Лимиты и их использование читаются один раз, дальше использование учитывается в памяти
+		keys := make([]limitKey, 0, len(ops))
+		for _, op := range ops {
+			keys = append(keys, limitKey{walletID: op.WalletID, operation: batchLimitOperation(op.Type)})
+		}
+		limits, err := loadLimitStates(ctx, tx, keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		// Операции применяются к заблокированным кошелькам в памяти по порядку,
+		// поэтому списание может использовать средства пополнения из того же пакета
+		results := make([]repository.BatchItemResult, len(ops))
+		var (
+			postings []batchPosting
+			changed  []*repository.Wallet
+		)
+		touched := make(map[uuid.UUID]bool, len(ids))
+		for i, op := range ops {
+			wallet, err := applyBatchOperation(wallets, limits, op)
+			if err != nil {
+				if atomic {
+					return nil, &repository.BatchItemError{Index: i, Err: err}
+				}
+				results[i].Err = err
+				continue
+			}
+
+			entry := &repository.Transaction{
+				WalletID:     op.WalletID,
+				Type:         op.Type,
+				Amount:       op.Amount,
+				BalanceAfter: wallet.Balance,
+			}
+			entryType := repository.JournalDeposit
+			lines := []journalLine{
+				walletLine(wallet.ID, wallet.Currency, op.Amount),
+				systemLine(repository.SystemAccountExternalFunding, wallet.Currency, -op.Amount),
+			}
+			if op.Type == repository.TransactionWithdraw {
+				entry.Amount = -op.Amount
+				entryType = repository.JournalWithdraw
+				lines = []journalLine{
+					walletLine(wallet.ID, wallet.Currency, -op.Amount),
+					systemLine(repository.SystemAccountPayouts, wallet.Currency, op.Amount),
+				}
+			}
+			results[i].Transaction = entry
+			postings = append(postings, batchPosting{entryType: entryType, lines: lines, entry: entry})
+			if !touched[wallet.ID] {
+				touched[wallet.ID] = true
+				changed = append(changed, wallet)
+			}
+		}
+
+		if len(postings) == 0 {
+			return results, nil
+		}
+
+		// Итоговые балансы, проводки, записи истории и события отправляются одним пакетом pgx за один обмен с сервером
+		batch := &pgx.Batch{}
+		for _, w := range changed {
+			batch.Queue("UPDATE wallets SET balance = $1 WHERE id = $2", w.Balance, w.ID)
+		}
+		for _, p := range postings {
+			if err := queueJournalEntry(batch, p.entryType, p.lines, p.entry); err != nil {
+				return nil, err
+			}
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return nil, apperrors.NewDatabaseError("выполнении пакета операций", err)
+		}
+
+		return results, nil
+	})
+}
+
+// batchPosting - проводка успешной операции пакета вместе с её записью истории
+type batchPosting struct {
+	entryType repository.JournalEntryType
+	lines     []journalLine
+	entry     *repository.Transaction
+}
+
+// batchLimitOperation возвращает вид операций для лимитов по типу операции пакета
+func batchLimitOperation(t repository.TransactionType) repository.LimitOperation {
+	if t == repository.TransactionWithdraw {
+		return repository.LimitWithdraw
+	}
+	return repository.LimitDeposit
+}
+
+// applyBatchOperation проверяет операцию и применяет её к балансу заблокированного кошелька
+// и к использованию его лимитов
+func applyBatchOperation(wallets map[uuid.UUID]*repository.Wallet, limits map[limitKey]*repository.WalletLimitStatus,
+	op repository.BatchOperation) (*repository.Wallet, error) {
+	wallet, ok := wallets[op.WalletID]
+	if !ok {
+		return nil, apperrors.ErrWalletNotFound
+	}
+	if err := checkWalletActive(wallet.Status); err != nil {
+		return nil, err
+	}
+	if op.Currency != "" && op.Currency != wallet.Currency {
+		return nil, apperrors.ErrCurrencyMismatch
+	}
+
+	switch op.Type {
+	case repository.TransactionDeposit:
+	case repository.TransactionWithdraw:
+		if wallet.Available() < op.Amount {
+			return nil, apperrors.ErrInsufficientFunds
+		}
+	default:
+		return nil, apperrors.ErrInvalidOperationType
+	}
+
+	operation := batchLimitOperation(op.Type)
+	state := limits[limitKey{walletID: op.WalletID, operation: operation}]
+	if err := limitError(state.Limits.Check(operation, op.Amount, state.Usage)); err != nil {
+		return nil, err
+	}
+	state.Usage.Daily += op.Amount
+	state.Usage.Weekly += op.Amount
+	state.Usage.Monthly += op.Amount
+
+	if op.Type == repository.TransactionDeposit {
+		wallet.Balance += op.Amount
+	} else {
+		wallet.Balance -= op.Amount
+	}
+	return wallet, nil
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+	"slices"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// errBalanceSharded - баланс кошелька распределён по корзинам и меняется через них
+var errBalanceSharded = stderrors.New("баланс кошелька распределён по корзинам")
+
+// foldBucketsQuery переносит остатки корзин кошельков $1 в wallets.balance, а их версии -
+// в wallets.version, поэтому полная версия кошелька при переносе не меняется
+const foldBucketsQuery = `WITH drained AS (
+		DELETE FROM wallet_balance_buckets WHERE wallet_id = ANY($1) RETURNING wallet_id, balance, version
+	)
+	UPDATE wallets w SET balance = w.balance + d.total, version = w.version + d.versions
+	FROM (SELECT wallet_id, sum(balance) AS total, sum(version) AS versions FROM drained GROUP BY wallet_id) d
+	WHERE w.id = d.wallet_id
+	RETURNING w.id, w.balance, w.version`
+
+// bucketDepositQuery зачисляет $2 в случайную корзину активного шардированного кошелька $1.
+// Строка кошелька блокируется FOR KEY SHARE: пополнения разных корзин не мешают друг другу,
+// но ждут операций, заблокировавших кошелёк целиком. Баланс после операции считается по снимку
+// запроса и не учитывает незафиксированные операции в других корзинах.
+const bucketDepositQuery = `WITH w AS (
+		SELECT id, balance_buckets FROM wallets
+		WHERE id = $1 AND status = 'ACTIVE' AND balance_buckets > 0
+		FOR KEY SHARE
+	), credited AS (
+		INSERT INTO wallet_balance_buckets (wallet_id, bucket, balance)
+		SELECT id, floor(random() * balance_buckets)::int, $2::bigint FROM w
+		ON CONFLICT (wallet_id, bucket) DO UPDATE SET balance = wallet_balance_buckets.balance + excluded.balance
+		RETURNING wallet_id
+	)
+	SELECT t.balance + $2 FROM credited JOIN wallets_with_buckets t ON t.id = credited.wallet_id`
+
+// bucketWithdrawQuery списывает $2 со случайной корзины активного шардированного кошелька $1,
+// в которой достаточно средств. Корзины, занятые параллельными операциями, пропускаются.
+const bucketWithdrawQuery = `WITH w AS (
+		SELECT id FROM wallets
+		WHERE id = $1 AND status = 'ACTIVE' AND balance_buckets > 0
+		FOR KEY SHARE
+	), bucket AS (
+		SELECT b.wallet_id, b.bucket FROM wallet_balance_buckets b JOIN w ON w.id = b.wallet_id
+		WHERE b.balance >= $2::bigint
+		ORDER BY random()
+		LIMIT 1
+		FOR UPDATE OF b SKIP LOCKED
+	), debited AS (
+		UPDATE wallet_balance_buckets b SET balance = b.balance - $2
+		FROM bucket
+		WHERE b.wallet_id = bucket.wallet_id AND b.bucket = bucket.bucket AND b.balance >= $2
+		RETURNING b.wallet_id
+	)
+	SELECT t.balance - $2 FROM debited JOIN wallets_with_buckets t ON t.id = debited.wallet_id`
+
+// foldBalanceBuckets переносит корзины шардированных кошельков в их строки и обновляет Balance и Version.
+// Строки кошельков должны быть заблокированы FOR UPDATE: тогда параллельных операций с корзинами нет.
+func foldBalanceBuckets(ctx context.Context, tx pgx.Tx, wallets ...*repository.Wallet) error {
+	sharded := make(map[uuid.UUID]*repository.Wallet)
+	ids := make([]uuid.UUID, 0, len(wallets))
+	for _, w := range wallets {
+		if w.BalanceBuckets > 0 {
+			sharded[w.ID] = w
+			ids = append(ids, w.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, foldBucketsQuery, ids)
+	if err != nil {
+		return apperrors.NewDatabaseError("переносе корзин баланса", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id      uuid.UUID
+			balance int64
+			version int64
+		)
+		if err := rows.Scan(&id, &balance, &version); err != nil {
+			return apperrors.NewDatabaseError("переносе корзин баланса", err)
+		}
+		sharded[id].Balance = balance
+		sharded[id].Version = version
+	}
+	if err := rows.Err(); err != nil {
+		return apperrors.NewDatabaseError("переносе корзин баланса", err)
+	}
+	return nil
+}
+
+// changeShardedBalance меняет баланс шардированного кошелька op.WalletID на delta через корзину
+// и возвращает баланс после изменения. Если ни в одной корзине не хватает средств, кошелёк меняет
+// статус, операция проверяет версию кошелька или на него действуют лимиты за периоды, требующие
+// сериализации операций, баланс меняется через заблокированную строку кошелька с переносом в неё всех корзин.
+func changeShardedBalance(ctx context.Context, tx pgx.Tx, op repository.Operation, delta int64, operation repository.LimitOperation) (int64, error) {
+	walletID, currency := op.WalletID, op.Currency
+	amount := max(delta, -delta)
+
+	// Использование лимитов за периоды считается по истории и верно только под блокировкой кошелька,
+	// лимит на одну операцию проверяется без неё
+	key := limitKey{walletID: walletID, operation: operation}
+	states, err := loadLimitStates(ctx, tx, key)
+	if err != nil {
+		return 0, err
+	}
+	limits := states[key].Limits
+	if op.ExpectedVersions == nil && limits.Daily == nil && limits.Weekly == nil && limits.Monthly == nil {
+		if err := limitError(limits.Check(operation, amount, repository.LimitUsage{})); err != nil {
+			return 0, err
+		}
+
+		query := bucketDepositQuery
+		if delta < 0 {
+			query = bucketWithdrawQuery
+		}
+		var balance int64
+		err := tx.QueryRow(ctx, query, walletID, amount).Scan(&balance)
+		if err == nil {
+			return balance, nil
+		}
+		if err != pgx.ErrNoRows {
+			return 0, apperrors.NewDatabaseError("изменении корзины баланса", err)
+		}
+	}
+
+	wallet, err := lockWallet(ctx, tx, walletID)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkWalletActive(wallet.Status); err != nil {
+		return 0, err
+	}
+	if currency != "" && currency != wallet.Currency {
+		return 0, apperrors.ErrCurrencyMismatch
+	}
+	// Перенос корзин сохраняет полную версию, поэтому версия строки сравнима с версией из If-Match
+	if op.ExpectedVersions != nil && !slices.Contains(op.ExpectedVersions, wallet.Version) {
+		return 0, apperrors.ErrVersionMismatch
+	}
+	if delta < 0 && wallet.Available() < amount {
+		return 0, apperrors.ErrInsufficientFunds
+	}
+	if err := checkLimit(ctx, tx, walletID, operation, amount); err != nil {
+		return 0, err
+	}
+
+	var balance int64
+	query := "UPDATE wallets SET balance = balance + $1 WHERE id = $2 RETURNING balance"
+	if err := tx.QueryRow(ctx, query, delta, walletID).Scan(&balance); err != nil {
+		return 0, apperrors.NewDatabaseError("изменении баланса", err)
+	}
+	return balance, nil
+}
+
+func (r *walletRepository) SetBalanceBuckets(ctx context.Context, walletID uuid.UUID, buckets int) (*repository.Wallet, error) {
+	return runTx(ctx, r.tx, "смены числа корзин баланса", readCommitted, func(tx pgx.Tx) (*repository.Wallet, error) {
+		// Блокировка переносит существующие корзины в строку кошелька, новые корзины заполняются пополнениями
+		wallet, err := lockWallet(ctx, tx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		if wallet.Status == repository.WalletClosed {
+			return nil, apperrors.ErrWalletClosed
+		}
+
+		wallet, err = scanWallet(tx.QueryRow(ctx,
+			"UPDATE wallets SET balance_buckets = $1 WHERE id = $2 RETURNING "+walletColumns, buckets, walletID))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("смене числа корзин баланса", err)
+		}
+
+		return wallet, nil
+	})
+}
@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *walletRepository) SetCreditLimit(ctx context.Context, walletID uuid.UUID, creditLimit int64) (*repository.Wallet, error) {
+	return runTx(ctx, r.tx, "смены кредитного лимита", readCommitted, func(tx pgx.Tx) (*repository.Wallet, error) {
+		wallet, err := lockWallet(ctx, tx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		if wallet.Status == repository.WalletClosed {
+			return nil, apperrors.ErrWalletClosed
+		}
+
+		// Снизить лимит ниже использованного кредита нельзя: сначала долг должен быть погашен
+		if creditLimit < wallet.CreditUsed() {
+			return nil, apperrors.ErrCreditLimitInUse
+		}
+
+		wallet, err = scanWallet(tx.QueryRow(ctx,
+			"UPDATE wallets SET credit_limit = $1 WHERE id = $2 RETURNING "+walletColumns, creditLimit, walletID))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("смене кредитного лимита", err)
+		}
+
+		return wallet, nil
+	})
+}
@@ -0,0 +1,217 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// holdColumns - колонки wallet_holds в порядке, ожидаемом scanHold
+const holdColumns = "id, wallet_id, amount, captured_amount, status, capture_transaction_id, expires_at, created_at, updated_at"
+
+func scanHold(row pgx.Row) (*repository.Hold, error) {
+	var h repository.Hold
+	err := row.Scan(&h.ID, &h.WalletID, &h.Amount, &h.CapturedAmount, &h.Status,
+		&h.CaptureTransactionID, &h.ExpiresAt, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+type holdRepository struct {
+	pool *pgxpool.Pool
+	tx   txRunner
+}
+
+// NewHoldRepository создаёт репозиторий; транзакции, прерванные конфликтом
+// с параллельными транзакциями, повторяются по policy
+func NewHoldRepository(pool *pgxpool.Pool, policy RetryPolicy) repository.HoldRepository {
+	return &holdRepository{pool: pool, tx: newTxRunner(pool, policy)}
+}
+
+func (r *holdRepository) CreateHold(ctx context.Context, params repository.NewHold) (*repository.Hold, error) {
+	return runTx(ctx, r.tx, "блокировки средств", readCommitted, func(tx pgx.Tx) (*repository.Hold, error) {
+		wallet, err := lockWallet(ctx, tx, params.WalletID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkWalletActive(wallet.Status); err != nil {
+			return nil, err
+		}
+
+		if params.Currency != "" && params.Currency != wallet.Currency {
+			return nil, apperrors.ErrCurrencyMismatch
+		}
+
+		// Блокировать можно только доступные средства
+		if wallet.Available() < params.Amount {
+			return nil, apperrors.ErrInsufficientFunds
+		}
+
+		if _, err := tx.Exec(ctx, "UPDATE wallets SET held = held + $1 WHERE id = $2", params.Amount, params.WalletID); err != nil {
+			return nil, apperrors.NewDatabaseError("блокировке средств", err)
+		}
+
+		hold, err := scanHold(tx.QueryRow(ctx,
+			"INSERT INTO wallet_holds (id, wallet_id, amount, expires_at) VALUES ($1, $2, $3, $4) RETURNING "+holdColumns,
+			uuid.New(), params.WalletID, params.Amount, params.ExpiresAt))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("создании блокировки", err)
+		}
+
+		return hold, nil
+	})
+}
+
+func (r *holdRepository) GetHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	hold, err := scanHold(r.pool.QueryRow(ctx,
+		"SELECT "+holdColumns+" FROM wallet_holds WHERE id = $1 AND wallet_id = $2", holdID, walletID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrHoldNotFound
+		}
+		return nil, apperrors.NewDatabaseError("получении блокировки", err)
+	}
+	return hold, nil
+}
+
+// lockActiveHold блокирует строку активной блокировки средств.
+// Блокировка строки hold берётся раньше строки кошелька, как и в ExpireHolds.
+func lockActiveHold(ctx context.Context, tx pgx.Tx, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	hold, err := scanHold(tx.QueryRow(ctx,
+		"SELECT "+holdColumns+" FROM wallet_holds WHERE id = $1 AND wallet_id = $2 FOR UPDATE", holdID, walletID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrHoldNotFound
+		}
+		return nil, apperrors.NewDatabaseError("блокировке строки блокировки средств", err)
+	}
+	if hold.Status != repository.HoldActive {
+		return nil, apperrors.ErrHoldNotActive
+	}
+	// Истёкшая, но ещё не обработанная фоновой задачей блокировка считается снятой
+	if !hold.ExpiresAt.After(time.Now()) {
+		return nil, apperrors.ErrHoldNotActive
+	}
+	return hold, nil
+}
+
+func (r *holdRepository) CaptureHold(ctx context.Context, walletID, holdID uuid.UUID, amount int64) (*repository.Hold, error) {
+	return runTx(ctx, r.tx, "списания блокировки", readCommitted, func(tx pgx.Tx) (*repository.Hold, error) {
+		hold, err := lockActiveHold(ctx, tx, walletID, holdID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Нулевая сумма означает списание всей заблокированной суммы
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return nil, apperrors.ErrCaptureExceedsHold
+		}
+
+		// Списываем amount с баланса и снимаем блокировку целиком, остаток становится доступен
+		var (
+			balance      int64
+			currencyCode string
+			status       repository.WalletStatus
+		)
+		err = tx.QueryRow(ctx,
+			"UPDATE wallets SET balance = balance - $1, held = held - $2 WHERE id = $3 RETURNING balance, currency, status",
+			amount, hold.Amount, walletID).Scan(&balance, &currencyCode, &status)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("списании заблокированных средств", err)
+		}
+
+		// Списание с замороженного кошелька запрещено, блокировка остаётся активной
+		if err := checkWalletActive(status); err != nil {
+			return nil, err
+		}
+
+		// Списание блокировки считается списанием для лимитов; при превышении блокировка остаётся активной
+		if err := checkLimit(ctx, tx, walletID, repository.LimitWithdraw, amount); err != nil {
+			return nil, err
+		}
+
+		entry := &repository.Transaction{
+			WalletID:     walletID,
+			Type:         repository.TransactionHoldCapture,
+			Amount:       -amount,
+			BalanceAfter: balance,
+		}
+		lines := []journalLine{
+			walletLine(walletID, currencyCode, -amount),
+			systemLine(repository.SystemAccountPayouts, currencyCode, amount),
+		}
+		if err := postJournalEntry(ctx, tx, repository.JournalHoldCapture, lines, entry); err != nil {
+			return nil, err
+		}
+
+		hold, err = scanHold(tx.QueryRow(ctx,
+			`UPDATE wallet_holds SET status = $2, captured_amount = $3, capture_transaction_id = $4, updated_at = now()
+			WHERE id = $1 RETURNING `+holdColumns,
+			holdID, repository.HoldCaptured, amount, entry.ID))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("обновлении блокировки", err)
+		}
+
+		return hold, nil
+	})
+}
+
+func (r *holdRepository) VoidHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	return runTx(ctx, r.tx, "отмены блокировки", readCommitted, func(tx pgx.Tx) (*repository.Hold, error) {
+		hold, err := lockActiveHold(ctx, tx, walletID, holdID)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(ctx, "UPDATE wallets SET held = held - $1 WHERE id = $2", hold.Amount, walletID); err != nil {
+			return nil, apperrors.NewDatabaseError("снятии блокировки средств", err)
+		}
+
+		hold, err = scanHold(tx.QueryRow(ctx,
+			"UPDATE wallet_holds SET status = $2, updated_at = now() WHERE id = $1 RETURNING "+holdColumns,
+			holdID, repository.HoldVoided))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("обновлении блокировки", err)
+		}
+
+		return hold, nil
+	})
+}
+
+func (r *holdRepository) ExpireHolds(ctx context.Context, now time.Time, limit int) (int64, error) {
+	// Блокировки, которые сейчас списываются или отменяются, пропускаются (SKIP LOCKED)
+	// и будут обработаны в следующий запуск, если останутся активными
+	query := `WITH expired AS (
+			UPDATE wallet_holds SET status = 'EXPIRED', updated_at = now()
+			WHERE id IN (
+				SELECT id FROM wallet_holds
+				WHERE status = 'ACTIVE' AND expires_at <= $1
+				ORDER BY expires_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING wallet_id, amount
+		), released AS (
+			UPDATE wallets w SET held = w.held - e.amount
+			FROM (SELECT wallet_id, SUM(amount) AS amount FROM expired GROUP BY wallet_id) e
+			WHERE w.id = e.wallet_id
+		)
+		SELECT count(*) FROM expired`
+
+	var expired int64
+	if err := r.pool.QueryRow(ctx, query, now, limit).Scan(&expired); err != nil {
+		return 0, apperrors.NewDatabaseError("снятии истёкших блокировок", err)
+	}
+	return expired, nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// claimIdempotencyKey резервирует ключ идемпотентности в транзакции операции.
+// Если ключ уже использован тем же запросом, возвращает replayed=true и сохранённый
+// результат первого выполнения в stored. Конкурентный запрос с тем же ключом
+// блокируется на INSERT до фиксации или отката первого.
+func claimIdempotencyKey(ctx context.Context, tx pgx.Tx, key *repository.IdempotencyKey, stored any) (replayed bool, err error) {
+	result, err := tx.Exec(ctx,
+		"INSERT INTO idempotency_keys (key, request_hash) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING",
+		key.Key, key.Fingerprint)
+	if err != nil {
+		return false, apperrors.NewDatabaseError("сохранении ключа идемпотентности", err)
+	}
+	if result.RowsAffected() == 1 {
+		return false, nil
+	}
+
+	var (
+		requestHash string
+		response    []byte
+	)
+	err = tx.QueryRow(ctx, "SELECT request_hash, response FROM idempotency_keys WHERE key = $1", key.Key).
+		Scan(&requestHash, &response)
+	if err != nil {
+		return false, apperrors.NewDatabaseError("получении ключа идемпотентности", err)
+	}
+	if requestHash != key.Fingerprint {
+		return false, apperrors.ErrIdempotencyKeyMismatch
+	}
+	if err := json.Unmarshal(response, stored); err != nil {
+		return false, apperrors.NewDatabaseError("чтении сохранённого ответа", err)
+	}
+	return true, nil
+}
+
+// saveIdempotencyResponse сохраняет результат операции для повторных запросов с тем же ключом
+func saveIdempotencyResponse(ctx context.Context, tx pgx.Tx, key *repository.IdempotencyKey, response any) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return apperrors.NewDatabaseError("сохранении ответа для ключа идемпотентности", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE idempotency_keys SET response = $2 WHERE key = $1", key.Key, data); err != nil {
+		return apperrors.NewDatabaseError("сохранении ответа для ключа идемпотентности", err)
+	}
+	return nil
+}
+
+func (r *walletRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE created_at < $1", before)
+	if err != nil {
+		return 0, apperrors.NewDatabaseError("удалении устаревших ключей идемпотентности", err)
+	}
+	return result.RowsAffected(), nil
+}
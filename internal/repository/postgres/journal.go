@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Строки проводки передаются массивами и разворачиваются через unnest одним INSERT.
+// Пустая строка в массивах кошельков и системных счетов означает NULL.
+const insertJournalLinesQuery = `INSERT INTO journal_lines (entry_id, wallet_id, system_account, currency, amount)
+	SELECT $1, NULLIF(l.wallet_id, '')::uuid, NULLIF(l.system_account, ''), l.currency, l.amount
+	FROM unnest($2::text[], $3::text[], $4::text[], $5::bigint[]) AS l(wallet_id, system_account, currency, amount)`
+
+// journalLine - строка проводки: движение суммы по кошельку или системному счёту.
+// Положительная сумма увеличивает остаток счёта, отрицательная уменьшает.
+type journalLine struct {
+	walletID uuid.UUID
+	account  repository.SystemAccount
+	currency string
+	amount   int64
+}
+
+// walletLine возвращает строку проводки по кошельку
+func walletLine(walletID uuid.UUID, currency string, amount int64) journalLine {
+	return journalLine{walletID: walletID, currency: currency, amount: amount}
+}
+
+// systemLine возвращает строку проводки по системному счёту
+func systemLine(account repository.SystemAccount, currency string, amount int64) journalLine {
+	return journalLine{account: account, currency: currency, amount: amount}
+}
+
+// queueJournalEntry добавляет в пакет проводку со строками lines, записи истории кошельков,
+// которые ей соответствуют, и события об изменении их балансов. ID, JournalEntryID и CreatedAt
+// записей истории заполняются при выполнении пакета. Сбалансированность проводки проверяет база
+// при фиксации транзакции.
+func queueJournalEntry(batch *pgx.Batch, entryType repository.JournalEntryType, lines []journalLine, history ...*repository.Transaction) error {
+	entryID := uuid.New()
+	batch.Queue("INSERT INTO journal_entries (id, type) VALUES ($1, $2)", entryID, entryType)
+
+	walletIDs := make([]string, len(lines))
+	accounts := make([]string, len(lines))
+	currencies := make([]string, len(lines))
+	amounts := make([]int64, len(lines))
+	for i, l := range lines {
+		if l.walletID != uuid.Nil {
+			walletIDs[i] = l.walletID.String()
+		}
+		accounts[i] = string(l.account)
+		currencies[i] = l.currency
+		amounts[i] = l.amount
+	}
+	batch.Queue(insertJournalLinesQuery, entryID, walletIDs, accounts, currencies, amounts)
+
+	for _, t := range history {
+		t.ID = uuid.New()
+		t.JournalEntryID = &entryID
+		batch.Queue(insertTransactionQuery, t.ID, t.WalletID, t.Type, t.Amount, t.BalanceAfter, t.CounterpartyWalletID, t.JournalEntryID).
+			QueryRow(func(row pgx.Row) error {
+				return row.Scan(&t.CreatedAt)
+			})
+
+		payload := events.BalanceChangedPayload{
+			TransactionID:        t.ID,
+			Amount:               t.Amount,
+			BalanceAfter:         t.BalanceAfter,
+			Currency:             walletCurrency(lines, t.WalletID),
+			CounterpartyWalletID: t.CounterpartyWalletID,
+			JournalEntryID:       t.JournalEntryID,
+		}
+		if err := queueOutboxEvent(batch, events.TypeForTransaction(t.Type), t.WalletID, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walletCurrency возвращает валюту строки проводки по кошельку walletID
+func walletCurrency(lines []journalLine, walletID uuid.UUID) string {
+	for _, l := range lines {
+		if l.walletID == walletID {
+			return l.currency
+		}
+	}
+	return ""
+}
+
+// postJournalEntry записывает проводку, записи истории кошельков и события за один обмен с сервером
+// в рамках переданной транзакции. Балансы кошельков обновляются вызывающим кодом в той же транзакции.
+func postJournalEntry(ctx context.Context, tx pgx.Tx, entryType repository.JournalEntryType, lines []journalLine, history ...*repository.Transaction) error {
+	batch := &pgx.Batch{}
+	if err := queueJournalEntry(batch, entryType, lines, history...); err != nil {
+		return err
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return apperrors.NewDatabaseError("записи проводки в журнал", err)
+	}
+	return nil
+}
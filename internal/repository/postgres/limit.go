@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// limitStatesQuery читает действующие лимиты и их использование для пар кошелёк - вид операций
+// из массивов $1 и $2. Лимиты кошелька имеют приоритет над лимитами по умолчанию. Использование
+// считается по истории операций, поэтому учитывает все операции, зафиксированные до блокировки
+// строки кошелька; кошельки без лимитов на окна историю не читают.
+const limitStatesQuery = `SELECT k.wallet_id, k.operation,
+		l.max_amount, l.daily_limit, l.weekly_limit, l.monthly_limit, l.wallet_id IS NOT NULL,
+		u.daily, u.weekly, u.monthly
+	FROM unnest($1::uuid[], $2::text[]) AS k (wallet_id, operation)
+	LEFT JOIN LATERAL (
+		SELECT wallet_id, max_amount, daily_limit, weekly_limit, monthly_limit
+		FROM operation_limits
+		WHERE operation = k.operation AND (wallet_id = k.wallet_id OR wallet_id IS NULL)
+		ORDER BY wallet_id NULLS LAST
+		LIMIT 1
+	) l ON true
+	CROSS JOIN LATERAL (
+		SELECT
+			COALESCE(sum(abs(t.amount)) FILTER (WHERE t.created_at > now() - interval '1 day'), 0) AS daily,
+			COALESCE(sum(abs(t.amount)) FILTER (WHERE t.created_at > now() - interval '7 days'), 0) AS weekly,
+			COALESCE(sum(abs(t.amount)), 0) AS monthly
+		FROM wallet_transactions t
+		WHERE t.wallet_id = k.wallet_id
+			AND t.created_at > now() - interval '30 days'
+			AND t.type = ANY(CASE k.operation
+				WHEN 'DEPOSIT' THEN ARRAY['DEPOSIT']
+				WHEN 'WITHDRAW' THEN ARRAY['WITHDRAW', 'HOLD_CAPTURE']
+				ELSE ARRAY['TRANSFER_OUT']
+			END)
+			AND COALESCE(l.daily_limit, l.weekly_limit, l.monthly_limit) IS NOT NULL
+	) u`
+
+// limitKey - пара кошелёк - вид операций
+type limitKey struct {
+	walletID  uuid.UUID
+	operation repository.LimitOperation
+}
+
+// loadLimitStates возвращает действующие лимиты и их использование для пар keys.
+// Вызывается после блокировки строк кошельков, иначе параллельные операции не будут учтены.
+func loadLimitStates(ctx context.Context, q pgxQuerier, keys ...limitKey) (map[limitKey]*repository.WalletLimitStatus, error) {
+	walletIDs := make([]uuid.UUID, len(keys))
+	operations := make([]string, len(keys))
+	for i, k := range keys {
+		walletIDs[i] = k.walletID
+		operations[i] = string(k.operation)
+	}
+
+	rows, err := q.Query(ctx, limitStatesQuery, walletIDs, operations)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("чтении лимитов операций", err)
+	}
+	defer rows.Close()
+
+	states := make(map[limitKey]*repository.WalletLimitStatus, len(keys))
+	for rows.Next() {
+		var (
+			key   limitKey
+			state repository.WalletLimitStatus
+		)
+		if err := rows.Scan(&key.walletID, &key.operation,
+			&state.Limits.MaxAmount, &state.Limits.Daily, &state.Limits.Weekly, &state.Limits.Monthly, &state.Override,
+			&state.Usage.Daily, &state.Usage.Weekly, &state.Usage.Monthly); err != nil {
+			return nil, apperrors.NewDatabaseError("чтении лимитов операций", err)
+		}
+		state.Operation = key.operation
+		states[key] = &state
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("чтении лимитов операций", err)
+	}
+	return states, nil
+}
+
+// pgxQuerier - общее подмножество pgx.Tx и pgxpool.Pool для чтения
+type pgxQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// checkLimit проверяет операцию по лимитам заблокированного кошелька
+func checkLimit(ctx context.Context, tx pgx.Tx, walletID uuid.UUID, operation repository.LimitOperation, amount int64) error {
+	key := limitKey{walletID: walletID, operation: operation}
+	states, err := loadLimitStates(ctx, tx, key)
+	if err != nil {
+		return err
+	}
+	state := states[key]
+	return limitError(state.Limits.Check(operation, amount, state.Usage))
+}
+
+// limitError преобразует нарушение лимита в ошибку приложения
+func limitError(violation *repository.LimitViolation) error {
+	if violation == nil {
+		return nil
+	}
+	return apperrors.NewLimitExceeded(apperrors.LimitExceededDetails{
+		Operation: string(violation.Operation),
+		Period:    string(violation.Period),
+		Limit:     violation.Limit,
+		Used:      violation.Used,
+		Remaining: violation.Remaining,
+	})
+}
+
+// limitColumns - колонки operation_limits в порядке, ожидаемом scanOperationLimit
+const limitColumns = "wallet_id, operation, max_amount, daily_limit, weekly_limit, monthly_limit, updated_at"
+
+func scanOperationLimit(row pgx.Row) (*repository.OperationLimit, error) {
+	var l repository.OperationLimit
+	if err := row.Scan(&l.WalletID, &l.Operation,
+		&l.Limits.MaxAmount, &l.Limits.Daily, &l.Limits.Weekly, &l.Limits.Monthly, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+type limitRepository struct {
+	tx txRunner
+}
+
+// NewLimitRepository создаёт репозиторий; транзакции, прерванные конфликтом
+// с параллельными транзакциями, повторяются по policy
+func NewLimitRepository(pool *pgxpool.Pool, policy RetryPolicy) repository.LimitRepository {
+	return &limitRepository{tx: newTxRunner(pool, policy)}
+}
+
+func (r *limitRepository) ListDefaultLimits(ctx context.Context) ([]repository.OperationLimit, error) {
+	return runTx(ctx, r.tx, "получения лимитов по умолчанию", snapshotReadOnly, func(tx pgx.Tx) ([]repository.OperationLimit, error) {
+		rows, err := tx.Query(ctx,
+			"SELECT "+limitColumns+" FROM operation_limits WHERE wallet_id IS NULL ORDER BY operation")
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("получении лимитов по умолчанию", err)
+		}
+		limits, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.OperationLimit, error) {
+			l, err := scanOperationLimit(row)
+			if err != nil {
+				return repository.OperationLimit{}, err
+			}
+			return *l, nil
+		})
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("чтении лимитов по умолчанию", err)
+		}
+		return limits, nil
+	})
+}
+
+func (r *limitRepository) SetLimit(ctx context.Context, limit repository.OperationLimit) (*repository.OperationLimit, error) {
+	l := limit.Limits
+	return runTx(ctx, r.tx, "сохранения лимитов", readCommitted, func(tx pgx.Tx) (*repository.OperationLimit, error) {
+		saved, err := scanOperationLimit(tx.QueryRow(ctx,
+			`INSERT INTO operation_limits (wallet_id, operation, max_amount, daily_limit, weekly_limit, monthly_limit)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (wallet_id, operation) DO UPDATE
+			SET max_amount = EXCLUDED.max_amount, daily_limit = EXCLUDED.daily_limit,
+				weekly_limit = EXCLUDED.weekly_limit, monthly_limit = EXCLUDED.monthly_limit, updated_at = now()
+			RETURNING `+limitColumns,
+			limit.WalletID, limit.Operation, l.MaxAmount, l.Daily, l.Weekly, l.Monthly))
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if stderrors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+				return nil, apperrors.ErrWalletNotFound
+			}
+			return nil, apperrors.NewDatabaseError("сохранении лимитов", err)
+		}
+		return saved, nil
+	})
+}
+
+func (r *limitRepository) DeleteLimit(ctx context.Context, walletID *uuid.UUID, operation repository.LimitOperation) error {
+	return r.tx.run(ctx, "удаления лимитов", readCommitted, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			"DELETE FROM operation_limits WHERE wallet_id IS NOT DISTINCT FROM $1 AND operation = $2",
+			walletID, operation)
+		if err != nil {
+			return apperrors.NewDatabaseError("удалении лимитов", err)
+		}
+		if result.RowsAffected() == 0 {
+			return apperrors.ErrLimitNotFound
+		}
+		return nil
+	})
+}
+
+// GetWalletLimits читает кошелёк и использование лимитов из одного снимка данных
+func (r *limitRepository) GetWalletLimits(ctx context.Context, walletID uuid.UUID) ([]repository.WalletLimitStatus, error) {
+	return runTx(ctx, r.tx, "получения лимитов кошелька", snapshotReadOnly, func(tx pgx.Tx) ([]repository.WalletLimitStatus, error) {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM wallets WHERE id = $1)", walletID).Scan(&exists); err != nil {
+			return nil, apperrors.NewDatabaseError("получении кошелька", err)
+		}
+		if !exists {
+			return nil, apperrors.ErrWalletNotFound
+		}
+
+		keys := make([]limitKey, len(repository.LimitOperations))
+		for i, op := range repository.LimitOperations {
+			keys[i] = limitKey{walletID: walletID, operation: op}
+		}
+		states, err := loadLimitStates(ctx, tx, keys...)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]repository.WalletLimitStatus, len(keys))
+		for i, k := range keys {
+			result[i] = *states[k]
+		}
+		return result, nil
+	})
+}
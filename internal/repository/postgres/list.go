@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+func (r *walletRepository) ListWallets(ctx context.Context, filter repository.WalletFilter) ([]repository.Wallet, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	addArg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "owner_id = "+addArg(*filter.OwnerID))
+	}
+	if filter.ExternalRef != nil {
+		conditions = append(conditions, "external_ref = "+addArg(*filter.ExternalRef))
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "status = "+addArg(string(*filter.Status)))
+	}
+	if filter.Currency != "" {
+		conditions = append(conditions, "currency = "+addArg(filter.Currency))
+	}
+	if filter.MinBalance != nil {
+		conditions = append(conditions, "balance >= "+addArg(*filter.MinBalance))
+	}
+	if filter.MaxBalance != nil {
+		conditions = append(conditions, "balance <= "+addArg(*filter.MaxBalance))
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= "+addArg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < "+addArg(*filter.CreatedTo))
+	}
+
+	// Имя колонки сортировки подставляется в запрос, поэтому берётся только из фиксированного набора
+	sortColumn := "created_at"
+	var sortValue any
+	if filter.After != nil {
+		sortValue = filter.After.CreatedAt
+	}
+	if filter.Sort == repository.WalletSortBalance {
+		sortColumn = "balance"
+		if filter.After != nil {
+			sortValue = filter.After.Balance
+		}
+	}
+	direction, cmp := "ASC", ">"
+	if filter.Descending {
+		direction, cmp = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		// Keyset-пагинация: id разрешает равенство значений сортировки
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, %s)",
+			sortColumn, cmp, addArg(sortValue), addArg(filter.After.ID)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query := fmt.Sprintf(`SELECT %s FROM wallets_with_buckets %s ORDER BY %s %s, id %s LIMIT %s`,
+		walletColumns, where, sortColumn, direction, direction, addArg(filter.Limit))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("получении списка кошельков", err)
+	}
+	defer rows.Close()
+
+	wallets := make([]repository.Wallet, 0, filter.Limit)
+	for rows.Next() {
+		w, err := scanWallet(rows)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("чтении списка кошельков", err)
+		}
+		wallets = append(wallets, *w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("чтении списка кошельков", err)
+	}
+
+	return wallets, nil
+}
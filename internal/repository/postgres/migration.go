@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -10,9 +11,35 @@ import (
 	goose "github.com/pressly/goose/v3"
 )
 
+// Команды миграций, которые выполняет Migrate
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+	MigrateRedo   = "redo"
+)
+
 func RunMigrations(cfg *config.Config) error {
 	log.Println("Запуск миграций...")
 
+	if err := Migrate(context.Background(), cfg, MigrateUp); err != nil {
+		return fmt.Errorf("ошибка при применении миграций: %w", err)
+	}
+
+	log.Println("Все миграции успешно применены")
+	return nil
+}
+
+// Migrate выполняет команду goose над миграциями из cfg.MigrationsPath: up применяет все новые
+// миграции, down откатывает последнюю, redo откатывает и заново применяет последнюю,
+// status выводит в лог состояние каждой миграции
+func Migrate(ctx context.Context, cfg *config.Config, command string) error {
+	switch command {
+	case MigrateUp, MigrateDown, MigrateStatus, MigrateRedo:
+	default:
+		return fmt.Errorf("неизвестная команда миграций %q", command)
+	}
+
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		cfg.DBHost,
@@ -32,7 +59,7 @@ func RunMigrations(cfg *config.Config) error {
 		}
 	}(db)
 
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		return fmt.Errorf("не удалось подключиться к БД для миграций: %w", err)
 	}
 
@@ -40,10 +67,5 @@ func RunMigrations(cfg *config.Config) error {
 	if err != nil {
 		return err
 	}
-	if err := goose.Up(db, cfg.MigrationsPath); err != nil {
-		return fmt.Errorf("ошибка при применении миграций: %w", err)
-	}
-
-	log.Println("Все миграции успешно применены")
-	return nil
+	return goose.RunContext(ctx, command, db, cfg.MigrationsPath)
 }
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const insertOutboxEventQuery = "INSERT INTO outbox_events (event_id, wallet_id, type, payload) VALUES ($1, $2, $3, $4)"
+
+// outboxRelayLockKey - ключ advisory-блокировки, под которой события доставляет только один процесс:
+// параллельная доставка нарушила бы порядок событий кошелька
+const outboxRelayLockKey int64 = 0x6f7574626f78
+
+// outboxXactQuery назначает транзакции идентификатор до получения sequence первого события.
+// Доставка узнаёт по снимку транзакций, что все транзакции с идентификаторами меньше запомненной
+// границы завершены: значит, завершены и все транзакции, получившие sequence до этого момента.
+const outboxXactQuery = "SELECT pg_current_xact_id()"
+
+// queueOutboxEvent добавляет в пакет запись события в outbox.
+// Время события - время начала транзакции, то же, что у записей истории этой транзакции.
+func queueOutboxEvent(batch *pgx.Batch, eventType events.Type, walletID uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return apperrors.NewDatabaseError("сериализации события", err)
+	}
+	batch.Queue(outboxXactQuery)
+	batch.Queue(insertOutboxEventQuery, uuid.New(), walletID, eventType, data)
+	return nil
+}
+
+// insertOutboxEvent записывает событие в outbox в рамках переданной транзакции
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, eventType events.Type, walletID uuid.UUID, payload any) error {
+	batch := &pgx.Batch{}
+	if err := queueOutboxEvent(batch, eventType, walletID, payload); err != nil {
+		return err
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return apperrors.NewDatabaseError("записи события в outbox", err)
+	}
+	return nil
+}
+
+// outboxCandidate - граница доставки, которая станет действительной после завершения транзакций
+// с идентификаторами меньше xmax: sequence прочитан до снимка, в котором xmax - следующий идентификатор
+type outboxCandidate struct {
+	sequence int64
+	xmax     int64
+}
+
+type outboxRepository struct {
+	tx txRunner
+
+	mu      sync.Mutex
+	horizon int64
+	pending *outboxCandidate
+}
+
+// NewOutboxRepository создаёт репозиторий; транзакции, прерванные конфликтом
+// с параллельными транзакциями, повторяются по policy
+func NewOutboxRepository(pool *pgxpool.Pool, policy RetryPolicy) repository.OutboxRepository {
+	return &outboxRepository{tx: newTxRunner(pool, policy)}
+}
+
+// RelayOutbox держит блокировку доставки на отдельном соединении всё время вызова, а транзакции
+// к базе короткие: выборка событий фиксируется до публикации, отметка о доставке пишется после
+// неё. Публикация - сетевой вызов и не держит открытой транзакцию и блокировки строк.
+func (r *outboxRepository) RelayOutbox(ctx context.Context, limit int, publish func(repository.OutboxEvent) error) (int, error) {
+	conn, err := r.tx.pool.Acquire(ctx)
+	if err != nil {
+		return 0, apperrors.NewDatabaseError("получении соединения для доставки событий", err)
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", outboxRelayLockKey).Scan(&locked); err != nil {
+		return 0, apperrors.NewDatabaseError("блокировке доставки событий", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+	defer func() {
+		// Соединение с неснятой блокировкой не возвращается в пул: закрытие снимает её
+		if _, err := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", outboxRelayLockKey); err != nil {
+			conn.Conn().Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	pending, err := r.claimOutboxEvents(ctx, limit)
+	if err != nil || len(pending) == 0 {
+		return 0, err
+	}
+
+	// События доставляются строго по порядку: после ошибки более поздние события
+	// того же кошелька ждут следующего вызова, события других кошельков доставляются
+	var published []int64
+	failed := make(map[int64]string)
+	blocked := make(map[uuid.UUID]bool)
+	for _, e := range pending {
+		if blocked[e.WalletID] {
+			continue
+		}
+		if err := publish(e); err != nil {
+			blocked[e.WalletID] = true
+			failed[e.Sequence] = err.Error()
+			continue
+		}
+		published = append(published, e.Sequence)
+	}
+
+	// Если отметка не удастся, доставленные события будут доставлены повторно (at-least-once)
+	err = r.tx.run(ctx, "отметки доставленных событий", readCommitted, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for sequence, lastError := range failed {
+			batch.Queue("UPDATE outbox_events SET attempts = attempts + 1, last_error = $2 WHERE sequence = $1",
+				sequence, lastError)
+		}
+		if len(published) > 0 {
+			batch.Queue("UPDATE outbox_events SET published_at = now() WHERE sequence = ANY($1)", published)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return apperrors.NewDatabaseError("отметке доставленных событий", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(published), nil
+}
+
+// claimOutboxEvents выбирает до limit недоставленных событий до границы доставки в порядке sequence.
+// Вызывается под блокировкой доставки, поэтому те же события не выберет другой процесс.
+func (r *outboxRepository) claimOutboxEvents(ctx context.Context, limit int) ([]repository.OutboxEvent, error) {
+	horizon, err := r.outboxHorizon(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return runTx(ctx, r.tx, "выборки событий для доставки", readCommitted, func(tx pgx.Tx) ([]repository.OutboxEvent, error) {
+		rows, err := tx.Query(ctx, `SELECT sequence, event_id, wallet_id, type, payload, attempts, created_at
+			FROM outbox_events
+			WHERE published_at IS NULL AND sequence <= $2
+			ORDER BY sequence
+			LIMIT $1`, limit, horizon)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("выборке событий для доставки", err)
+		}
+		pending, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.OutboxEvent, error) {
+			var e repository.OutboxEvent
+			err := row.Scan(&e.Sequence, &e.EventID, &e.WalletID, &e.Type, &e.Payload, &e.Attempts, &e.CreatedAt)
+			return e, err
+		})
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("чтении событий для доставки", err)
+		}
+		return pending, nil
+	})
+}
+
+// outboxHorizon возвращает наибольший sequence, до которого все события зафиксированы или отменены:
+// событие с меньшим sequence уже не появится после доставленного. Транзакции, записывающие события,
+// не ждут доставку: граница запоминается вместе со снимком транзакций и становится действительной,
+// когда завершатся все транзакции, активные в момент снимка. Обычно это происходит сразу или
+// к следующему вызову.
+func (r *outboxRepository) outboxHorizon(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sequence, xmin, xmax int64
+	err := r.tx.run(ctx, "границы доставки событий", readCommitted, func(tx pgx.Tx) error {
+		// Каждый запрос в READ COMMITTED получает свой снимок: sequence читается раньше снимка
+		err := tx.QueryRow(ctx, "SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM outbox_events_sequence_seq").
+			Scan(&sequence)
+		if err != nil {
+			return apperrors.NewDatabaseError("определении границы доставки событий", err)
+		}
+		err = tx.QueryRow(ctx, `SELECT pg_snapshot_xmin(s)::text::bigint, pg_snapshot_xmax(s)::text::bigint
+			FROM pg_current_snapshot() s`).Scan(&xmin, &xmax)
+		if err != nil {
+			return apperrors.NewDatabaseError("определении границы доставки событий", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if r.pending != nil && xmin >= r.pending.xmax {
+		r.horizon = r.pending.sequence
+		r.pending = nil
+	}
+	if r.pending == nil {
+		if xmin >= xmax {
+			// В момент снимка не было активных транзакций
+			r.horizon = sequence
+		} else {
+			r.pending = &outboxCandidate{sequence: sequence, xmax: xmax}
+		}
+	}
+	return r.horizon, nil
+}
+
+func (r *outboxRepository) DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	return runTx(ctx, r.tx, "удаления доставленных событий", readCommitted, func(tx pgx.Tx) (int64, error) {
+		result, err := tx.Exec(ctx, "DELETE FROM outbox_events WHERE published_at < $1", before)
+		if err != nil {
+			return 0, apperrors.NewDatabaseError("удалении доставленных событий", err)
+		}
+		return result.RowsAffected(), nil
+	})
+}
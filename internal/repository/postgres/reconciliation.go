@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type reconciliationRepository struct {
+	pool *pgxpool.Pool
+	tx   txRunner
+}
+
+// NewReconciliationRepository создаёт репозиторий; транзакции, прерванные конфликтом
+// с параллельными транзакциями, повторяются по policy
+func NewReconciliationRepository(pool *pgxpool.Pool, policy RetryPolicy) repository.ReconciliationRepository {
+	return &reconciliationRepository{pool: pool, tx: newTxRunner(pool, policy)}
+}
+
+func (r *reconciliationRepository) FindBalanceDrifts(ctx context.Context) (int64, []repository.BalanceDrift, error) {
+	// Оба запроса читают один снимок данных; баланс и строки журнала меняются
+	// в одной транзакции, поэтому на снимке они согласованы и блокировки не нужны
+	var (
+		checked int64
+		drifts  []repository.BalanceDrift
+	)
+	err := r.tx.run(ctx, "сверки", snapshotReadOnly, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, "SELECT count(*) FROM wallets").Scan(&checked); err != nil {
+			return apperrors.NewDatabaseError("сверке балансов", err)
+		}
+
+		// Баланс шардированного кошелька включает его корзины
+		rows, err := tx.Query(ctx, `SELECT w.id, w.currency, w.balance, COALESCE(l.total, 0)
+			FROM wallets_with_buckets w
+			LEFT JOIN (
+				SELECT wallet_id, sum(amount) AS total
+				FROM journal_lines
+				WHERE wallet_id IS NOT NULL
+				GROUP BY wallet_id
+			) l ON l.wallet_id = w.id
+			WHERE w.balance <> COALESCE(l.total, 0)
+			ORDER BY w.id`)
+		if err != nil {
+			return apperrors.NewDatabaseError("сверке балансов", err)
+		}
+		defer rows.Close()
+
+		drifts = nil
+		for rows.Next() {
+			var d repository.BalanceDrift
+			if err := rows.Scan(&d.WalletID, &d.Currency, &d.Balance, &d.LedgerBalance); err != nil {
+				return apperrors.NewDatabaseError("чтении результатов сверки", err)
+			}
+			d.Drift = d.Balance - d.LedgerBalance
+			drifts = append(drifts, d)
+		}
+		if err := rows.Err(); err != nil {
+			return apperrors.NewDatabaseError("чтении результатов сверки", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return checked, drifts, nil
+}
+
+func (r *reconciliationRepository) PostAdjustment(ctx context.Context, walletID uuid.UUID) (*repository.BalanceDrift, error) {
+	return runTx(ctx, r.tx, "корректировки", readCommitted, func(tx pgx.Tx) (*repository.BalanceDrift, error) {
+		// Пока кошелёк заблокирован, его баланс и строки журнала не меняются
+		wallet, err := lockWallet(ctx, tx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		var ledgerBalance int64
+		err = tx.QueryRow(ctx, "SELECT COALESCE(sum(amount), 0) FROM journal_lines WHERE wallet_id = $1", walletID).
+			Scan(&ledgerBalance)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("расчёте баланса по журналу", err)
+		}
+
+		drift := &repository.BalanceDrift{
+			WalletID:      walletID,
+			Currency:      wallet.Currency,
+			Balance:       wallet.Balance,
+			LedgerBalance: ledgerBalance,
+			Drift:         wallet.Balance - ledgerBalance,
+		}
+		if drift.Drift == 0 {
+			return nil, nil
+		}
+
+		// Баланс кошелька, который уже видели клиенты, не меняется: журнал выравнивается с ним
+		// проводкой ADJUSTMENT, а расхождение остаётся на счёте RECONCILIATION до разбора.
+		// Запись истории связана с проводкой, поэтому история и выписка сходятся с балансом.
+		lines := []journalLine{
+			walletLine(walletID, wallet.Currency, drift.Drift),
+			systemLine(repository.SystemAccountReconciliation, wallet.Currency, -drift.Drift),
+		}
+		entry := &repository.Transaction{
+			WalletID:     walletID,
+			Type:         repository.TransactionAdjustment,
+			Amount:       drift.Drift,
+			BalanceAfter: wallet.Balance,
+		}
+		if err := postJournalEntry(ctx, tx, repository.JournalAdjustment, lines, entry); err != nil {
+			return nil, err
+		}
+
+		drift.Corrected = true
+		return drift, nil
+	})
+}
+
+func (r *reconciliationRepository) SaveReconciliationRun(ctx context.Context, run *repository.ReconciliationRun) error {
+	drifts, err := json.Marshal(run.Drifts)
+	if err != nil {
+		return apperrors.NewDatabaseError("сериализации результатов сверки", err)
+	}
+	_, err = r.pool.Exec(ctx,
+		`INSERT INTO reconciliation_runs (id, started_at, finished_at, auto_correct, wallets_checked, drifts)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		run.ID, run.StartedAt, run.FinishedAt, run.AutoCorrect, run.WalletsChecked, drifts)
+	if err != nil {
+		return apperrors.NewDatabaseError("сохранении результатов сверки", err)
+	}
+	return nil
+}
+
+func (r *reconciliationRepository) GetLastReconciliationRun(ctx context.Context) (*repository.ReconciliationRun, error) {
+	var (
+		run    repository.ReconciliationRun
+		drifts []byte
+	)
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, started_at, finished_at, auto_correct, wallets_checked, drifts
+		FROM reconciliation_runs
+		ORDER BY finished_at DESC
+		LIMIT 1`).Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.AutoCorrect, &run.WalletsChecked, &drifts)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrReconciliationNotFound
+		}
+		return nil, apperrors.NewDatabaseError("получении результатов сверки", err)
+	}
+	if err := json.Unmarshal(drifts, &run.Drifts); err != nil {
+		return nil, apperrors.NewDatabaseError("чтении результатов сверки", err)
+	}
+	return &run, nil
+}
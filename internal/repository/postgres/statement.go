@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *walletRepository) StreamStatement(ctx context.Context, query repository.StatementQuery, visitor repository.StatementVisitor) error {
+	// Начальный баланс и операции читаются из одного снимка данных, поэтому баланс на конец
+	// выписки всегда равен начальному плюс сумме её операций. Транзакция не повторяется:
+	// часть выписки к моменту ошибки может быть уже отправлена
+	return r.tx.runOnce(ctx, "выписки", snapshotReadOnly, func(tx pgx.Tx) error {
+		statement := repository.Statement{WalletID: query.WalletID, To: query.To}
+		// Начальный баланс - по ближайшему снимку до начала периода и операциям после него
+		err := tx.QueryRow(ctx, `SELECT w.currency, p.from_at,
+				COALESCE(s.balance, 0) + COALESCE((
+					SELECT sum(t.amount)
+					FROM wallet_transactions t
+					WHERE t.wallet_id = w.id
+						AND t.created_at > COALESCE(s.taken_at, '-infinity')
+						AND t.created_at < p.from_at
+				), 0)
+			FROM wallets w
+			CROSS JOIN LATERAL (SELECT COALESCE($2::timestamptz, w.created_at) AS from_at) p
+			LEFT JOIN LATERAL (
+				SELECT balance, taken_at
+				FROM wallet_balance_snapshots
+				WHERE wallet_id = w.id AND taken_at < p.from_at
+				ORDER BY taken_at DESC
+				LIMIT 1
+			) s ON true
+			WHERE w.id = $1`,
+			query.WalletID, query.From).Scan(&statement.Currency, &statement.From, &statement.OpeningBalance)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return apperrors.ErrWalletNotFound
+			}
+			return apperrors.NewDatabaseError("получении начального баланса выписки", err)
+		}
+
+		if err := visitor.Begin(statement); err != nil {
+			return err
+		}
+
+		// pgx читает строки результата из соединения по мере перебора, в памяти остаётся только текущая
+		rows, err := tx.Query(ctx, `SELECT id, type, amount, counterparty_wallet_id, created_at
+			FROM wallet_transactions
+			WHERE wallet_id = $1 AND created_at >= $2 AND created_at < $3
+			ORDER BY created_at, id`,
+			query.WalletID, statement.From, statement.To)
+		if err != nil {
+			return apperrors.NewDatabaseError("получении операций выписки", err)
+		}
+		defer rows.Close()
+
+		// Баланс после операции считается нарастающим итогом, а не берётся из balance_after:
+		// у параллельных операций порядок фиксации может не совпадать с порядком выписки
+		balance := statement.OpeningBalance
+		for rows.Next() {
+			var e repository.StatementEntry
+			if err := rows.Scan(&e.ID, &e.Type, &e.Amount, &e.CounterpartyWalletID, &e.CreatedAt); err != nil {
+				return apperrors.NewDatabaseError("чтении операций выписки", err)
+			}
+			balance += e.Amount
+			e.Balance = balance
+			if err := visitor.Entry(e); err != nil {
+				return err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return apperrors.NewDatabaseError("чтении операций выписки", err)
+		}
+
+		return visitor.End(balance)
+	})
+}
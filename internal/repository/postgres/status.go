@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// allowedStatusTransitions - допустимые переходы между статусами кошелька.
+// Закрытие окончательно: из CLOSED перейти нельзя.
+var allowedStatusTransitions = map[repository.WalletStatus][]repository.WalletStatus{
+	repository.WalletActive: {repository.WalletFrozen, repository.WalletClosed},
+	repository.WalletFrozen: {repository.WalletActive, repository.WalletClosed},
+}
+
+func canChangeStatus(from, to repository.WalletStatus) bool {
+	for _, s := range allowedStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *walletRepository) ChangeWalletStatus(ctx context.Context, change repository.StatusChange) (*repository.Wallet, error) {
+	return runTx(ctx, r.tx, "смены статуса", readCommitted, func(tx pgx.Tx) (*repository.Wallet, error) {
+		ids := []uuid.UUID{change.WalletID}
+		if change.SweepToWalletID != nil {
+			ids = append(ids, *change.SweepToWalletID)
+		}
+		wallets, err := lockWallets(ctx, tx, ids...)
+		if err != nil {
+			return nil, err
+		}
+
+		wallet, ok := wallets[change.WalletID]
+		if !ok {
+			return nil, apperrors.ErrWalletNotFound
+		}
+		if !canChangeStatus(wallet.Status, change.Status) {
+			return nil, apperrors.ErrInvalidStatusTransition
+		}
+
+		if change.Status == repository.WalletClosed {
+			if err := sweepBeforeClose(ctx, tx, wallet, wallets, change.SweepToWalletID); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO wallet_status_changes (id, wallet_id, from_status, to_status, reason, sweep_wallet_id)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			uuid.New(), wallet.ID, wallet.Status, change.Status, change.Reason, change.SweepToWalletID)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("записи смены статуса", err)
+		}
+		err = insertOutboxEvent(ctx, tx, events.WalletStatusChanged, wallet.ID, events.StatusChangedPayload{
+			From:   wallet.Status,
+			To:     change.Status,
+			Reason: change.Reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		wallet, err = scanWallet(tx.QueryRow(ctx,
+			"UPDATE wallets SET status = $1 WHERE id = $2 RETURNING "+walletColumns,
+			change.Status, change.WalletID))
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("смене статуса кошелька", err)
+		}
+
+		return wallet, nil
+	})
+}
+
+// sweepBeforeClose проверяет, что кошелёк можно закрыть, и переводит остаток баланса
+// на кошелёк sweepTo. Кошельки уже заблокированы вызывающей стороной.
+func sweepBeforeClose(ctx context.Context, tx pgx.Tx, wallet *repository.Wallet, locked map[uuid.UUID]*repository.Wallet, sweepTo *uuid.UUID) error {
+	// Заблокированные средства нельзя ни перевести, ни бросить на закрытом кошельке
+	if wallet.Held > 0 {
+		return apperrors.ErrWalletHasActiveHolds
+	}
+	if wallet.Balance == 0 {
+		return nil
+	}
+	// Использованный кредит нельзя ни перевести, ни списать закрытием: его нужно сначала погасить
+	if wallet.Balance < 0 {
+		return apperrors.ErrWalletHasDebt
+	}
+	if sweepTo == nil {
+		return apperrors.ErrWalletNotEmpty
+	}
+
+	target, ok := locked[*sweepTo]
+	if !ok {
+		return apperrors.ErrWalletNotFound
+	}
+	if err := checkWalletActive(target.Status); err != nil {
+		return err
+	}
+	if target.Currency != wallet.Currency {
+		return apperrors.ErrCurrencyMismatch
+	}
+
+	// Остаток переводится так же, как обычный перевод, и виден в истории обоих кошельков
+	var targetBalance int64
+	err := tx.QueryRow(ctx, "UPDATE wallets SET balance = balance + $1 WHERE id = $2 RETURNING balance",
+		wallet.Balance, target.ID).Scan(&targetBalance)
+	if err != nil {
+		return apperrors.NewDatabaseError("переводе остатка при закрытии", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE wallets SET balance = 0 WHERE id = $1", wallet.ID); err != nil {
+		return apperrors.NewDatabaseError("переводе остатка при закрытии", err)
+	}
+
+	debit := &repository.Transaction{
+		WalletID:             wallet.ID,
+		Type:                 repository.TransactionTransferOut,
+		Amount:               -wallet.Balance,
+		BalanceAfter:         0,
+		CounterpartyWalletID: &target.ID,
+	}
+	credit := &repository.Transaction{
+		WalletID:             target.ID,
+		Type:                 repository.TransactionTransferIn,
+		Amount:               wallet.Balance,
+		BalanceAfter:         targetBalance,
+		CounterpartyWalletID: &wallet.ID,
+	}
+	lines := []journalLine{
+		walletLine(wallet.ID, wallet.Currency, -wallet.Balance),
+		walletLine(target.ID, target.Currency, wallet.Balance),
+	}
+	return postJournalEntry(ctx, tx, repository.JournalTransfer, lines, debit, credit)
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+const insertTransactionQuery = `INSERT INTO wallet_transactions (id, wallet_id, type, amount, balance_after, counterparty_wallet_id, journal_entry_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING created_at`
+
+func (r *walletRepository) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]repository.Transaction, error) {
+	conditions := []string{"wallet_id = $1"}
+	args := []any{filter.WalletID}
+	addArg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Type != nil {
+		conditions = append(conditions, "type = "+addArg(string(*filter.Type)))
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= "+addArg(*filter.From))
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < "+addArg(*filter.To))
+	}
+	if filter.After != nil {
+		// Keyset-пагинация: берём записи строго "старше" последней записи предыдущей страницы
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", addArg(filter.After.CreatedAt), addArg(filter.After.ID)))
+	}
+
+	query := fmt.Sprintf(`SELECT id, wallet_id, type, amount, balance_after, counterparty_wallet_id, journal_entry_id, created_at
+		FROM wallet_transactions
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %s`, strings.Join(conditions, " AND "), addArg(filter.Limit))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("получении истории операций", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]repository.Transaction, 0, filter.Limit)
+	for rows.Next() {
+		var t repository.Transaction
+		if err := rows.Scan(&t.ID, &t.WalletID, &t.Type, &t.Amount, &t.BalanceAfter, &t.CounterpartyWalletID, &t.JournalEntryID, &t.CreatedAt); err != nil {
+			return nil, apperrors.NewDatabaseError("чтении истории операций", err)
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("чтении истории операций", err)
+	}
+
+	return transactions, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *walletRepository) Transfer(ctx context.Context, t repository.Transfer) (*repository.TransferResult, error) {
+	return runTx(ctx, r.tx, "перевода", readCommitted, func(tx pgx.Tx) (*repository.TransferResult, error) {
+		// Повтор запроса с тем же ключом идемпотентности не изменяет балансы
+		if t.IdempotencyKey != nil {
+			var stored repository.TransferResult
+			replayed, err := claimIdempotencyKey(ctx, tx, t.IdempotencyKey, &stored)
+			if err != nil {
+				return nil, err
+			}
+			if replayed {
+				return &stored, nil
+			}
+		}
+
+		// Блокируем оба кошелька в порядке возрастания id: встречные переводы
+		// A->B и B->A берут блокировки в одном порядке и не могут взаимно заблокироваться
+		wallets, err := lockWallets(ctx, tx, t.FromWalletID, t.ToWalletID)
+		if err != nil {
+			return nil, err
+		}
+
+		from, ok := wallets[t.FromWalletID]
+		if !ok {
+			return nil, apperrors.ErrWalletNotFound
+		}
+		to, ok := wallets[t.ToWalletID]
+		if !ok {
+			return nil, apperrors.ErrWalletNotFound
+		}
+
+		if err := checkWalletActive(from.Status); err != nil {
+			return nil, err
+		}
+		if err := checkWalletActive(to.Status); err != nil {
+			return nil, err
+		}
+
+		// Перевод возможен только между кошельками в одной валюте
+		if from.Currency != to.Currency || (t.Currency != "" && t.Currency != from.Currency) {
+			return nil, apperrors.ErrCurrencyMismatch
+		}
+
+		// Проверяем достаточность средств у отправителя с учётом активных блокировок и кредитного лимита
+		if from.Available() < t.Amount {
+			return nil, apperrors.ErrInsufficientFunds
+		}
+
+		// Лимиты действуют на исходящие переводы отправителя
+		if err := checkLimit(ctx, tx, t.FromWalletID, repository.LimitTransfer, t.Amount); err != nil {
+			return nil, err
+		}
+
+		var fromBalance, toBalance int64
+		query := "UPDATE wallets SET balance = balance + $1 WHERE id = $2 RETURNING balance"
+		if err := tx.QueryRow(ctx, query, -t.Amount, t.FromWalletID).Scan(&fromBalance); err != nil {
+			return nil, apperrors.NewDatabaseError("списании средств при переводе", err)
+		}
+		if err := tx.QueryRow(ctx, query, t.Amount, t.ToWalletID).Scan(&toBalance); err != nil {
+			return nil, apperrors.NewDatabaseError("зачислении средств при переводе", err)
+		}
+
+		// Перевод - одна проводка между двумя кошельками, обе стороны попадают в историю в той же транзакции
+		debit := &repository.Transaction{
+			WalletID:             t.FromWalletID,
+			Type:                 repository.TransactionTransferOut,
+			Amount:               -t.Amount,
+			BalanceAfter:         fromBalance,
+			CounterpartyWalletID: &t.ToWalletID,
+		}
+		credit := &repository.Transaction{
+			WalletID:             t.ToWalletID,
+			Type:                 repository.TransactionTransferIn,
+			Amount:               t.Amount,
+			BalanceAfter:         toBalance,
+			CounterpartyWalletID: &t.FromWalletID,
+		}
+		lines := []journalLine{
+			walletLine(t.FromWalletID, from.Currency, -t.Amount),
+			walletLine(t.ToWalletID, to.Currency, t.Amount),
+		}
+		if err := postJournalEntry(ctx, tx, repository.JournalTransfer, lines, debit, credit); err != nil {
+			return nil, err
+		}
+
+		result := &repository.TransferResult{Debit: *debit, Credit: *credit}
+		if t.IdempotencyKey != nil {
+			if err := saveIdempotencyResponse(ctx, tx, t.IdempotencyKey, result); err != nil {
+				return nil, err
+			}
+		}
+
+		return result, nil
+	})
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/config"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/metrics"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RetryPolicy задаёт повторы транзакций, прерванных ошибкой сериализации или взаимоблокировкой.
+// MaxAttempts - общее число попыток, включая первую; задержка перед n-й повторной попыткой
+// выбирается случайно от нуля до BaseDelay * 2^(n-1), но не больше MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// NewRetryPolicy возвращает политику повторов транзакций из конфигурации;
+// значения по умолчанию заданы в config.Config
+func NewRetryPolicy(cfg *config.Config) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: cfg.TxRetryMaxAttempts,
+		BaseDelay:   cfg.TxRetryBaseDelay,
+		MaxDelay:    cfg.TxRetryMaxDelay,
+	}
+}
+
+// Delay возвращает наибольшую задержку перед следующей попыткой после attempts неудачных попыток
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// jitter возвращает случайную задержку от нуля до limit: при полном разбросе транзакции,
+// конфликтовавшие друг с другом, повторяются в разное время
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(limit) + 1))
+}
+
+// RetryableCode возвращает код SQLSTATE, если ошибка вызвана конфликтом параллельных транзакций
+// и транзакцию можно безопасно выполнить заново. Ошибка может быть обёрнута в AppError.
+func RetryableCode(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if !stderrors.As(err, &pgErr) {
+		return "", false
+	}
+	switch pgErr.Code {
+	case pgerrcode.SerializationFailure, pgerrcode.DeadlockDetected:
+		return pgErr.Code, true
+	}
+	return "", false
+}
+
+// Уровни изоляции транзакций репозитория
+var (
+	// readCommitted - для операций, которые блокируют изменяемые строки и не зависят
+	// от снимка данных на начало транзакции
+	readCommitted = pgx.TxOptions{IsoLevel: pgx.ReadCommitted}
+	// snapshotReadOnly - для чтений, которым нужен один согласованный снимок данных
+	snapshotReadOnly = pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+)
+
+// txRunner выполняет функцию в транзакции и повторяет её целиком, если транзакция прервана
+// конфликтом с параллельными транзакциями
+type txRunner struct {
+	pool   *pgxpool.Pool
+	policy RetryPolicy
+}
+
+func newTxRunner(pool *pgxpool.Pool, policy RetryPolicy) txRunner {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return txRunner{pool: pool, policy: policy}
+}
+
+// run выполняет fn в транзакции с уровнем изоляции и режимом доступа из opts и фиксирует её.
+// name - название операции в родительном падеже для сообщений об ошибках. fn может вызываться
+// несколько раз и не должна оставлять после неудачной попытки состояние, влияющее на следующую.
+// Повтор не выполняется, если задержка перед ним не укладывается в срок контекста.
+func (r txRunner) run(ctx context.Context, name string, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := r.runOnce(ctx, name, opts, fn)
+		if err == nil {
+			return nil
+		}
+		code, retryable := RetryableCode(err)
+		if !retryable {
+			return err
+		}
+		if attempt >= r.policy.MaxAttempts {
+			metrics.TxRetriesExhausted.Add(1)
+			return err
+		}
+
+		delay := jitter(r.policy.Delay(attempt))
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			metrics.TxRetriesExhausted.Add(1)
+			return err
+		}
+		metrics.TxRetries.Add(code, 1)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+func (r txRunner) runOnce(ctx context.Context, name string, opts pgx.TxOptions, fn func(tx pgx.Tx) error) error {
+	tx, err := r.pool.BeginTx(ctx, opts)
+	if err != nil {
+		return apperrors.NewDatabaseError("создание транзакции для "+name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperrors.NewDatabaseError("фиксация транзакции "+name, err)
+	}
+	return nil
+}
+
+// runTx выполняет fn через runner и возвращает результат успешной попытки
+func runTx[T any](ctx context.Context, r txRunner, name string, opts pgx.TxOptions, fn func(tx pgx.Tx) (T, error)) (T, error) {
+	var result T
+	err := r.run(ctx, name, opts, func(tx pgx.Tx) error {
+		var err error
+		result, err = fn(tx)
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
@@ -2,9 +2,12 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	stderrors "errors"
+	"slices"
 
 	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
 	"github.com/devopesik/wallet-basic-operations/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
@@ -13,105 +16,343 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type walletRepository struct {
-	pool *pgxpool.Pool
-}
+// walletColumns - колонки wallets в порядке, ожидаемом scanWallet
+const walletColumns = "id, balance, held, credit_limit, currency, status, owner_id, external_ref, created_at, balance_buckets, version"
 
-func NewWalletRepository(pool *pgxpool.Pool) repository.WalletRepository {
-	return &walletRepository{pool: pool}
+// scanWallet читает строку с колонками walletColumns
+func scanWallet(row pgx.Row) (*repository.Wallet, error) {
+	var wallet repository.Wallet
+	if err := row.Scan(&wallet.ID, &wallet.Balance, &wallet.Held, &wallet.CreditLimit, &wallet.Currency, &wallet.Status,
+		&wallet.OwnerID, &wallet.ExternalRef, &wallet.CreatedAt, &wallet.BalanceBuckets, &wallet.Version); err != nil {
+		return nil, err
+	}
+	return &wallet, nil
 }
 
-func (r *walletRepository) GetWallet(ctx context.Context, walletID uuid.UUID) (*repository.Wallet, error) {
-	var wallet repository.Wallet
-	err := r.pool.QueryRow(ctx, "SELECT id, balance FROM wallets WHERE id = $1", walletID).Scan(&wallet.ID, &wallet.Balance)
+// lockWallet читает кошелёк с блокировкой строки до конца транзакции.
+// Корзины шардированного баланса переносятся в строку кошелька, поэтому Balance - полный баланс.
+func lockWallet(ctx context.Context, tx pgx.Tx, walletID uuid.UUID) (*repository.Wallet, error) {
+	wallet, err := scanWallet(tx.QueryRow(ctx, "SELECT "+walletColumns+" FROM wallets WHERE id = $1 FOR UPDATE", walletID))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, apperrors.ErrWalletNotFound
 		}
-		return nil, apperrors.NewDatabaseError("получении кошелька", err)
+		return nil, apperrors.NewDatabaseError("блокировке кошелька", err)
 	}
-	return &wallet, nil
+	if err := foldBalanceBuckets(ctx, tx, wallet); err != nil {
+		return nil, err
+	}
+	return wallet, nil
 }
 
-func (r *walletRepository) Deposit(ctx context.Context, walletID uuid.UUID, amount int64) error {
-	// Начинаем транзакцию для атомарности операции
-	tx, err := r.pool.Begin(ctx)
+// lockWallets блокирует несколько кошельков в порядке возрастания id: операции над одной
+// парой кошельков берут блокировки в одном порядке и не могут взаимно заблокироваться.
+// Отсутствующие кошельки в результат не попадают, корзины шардированных балансов переносятся в строки кошельков.
+func lockWallets(ctx context.Context, tx pgx.Tx, walletIDs ...uuid.UUID) (map[uuid.UUID]*repository.Wallet, error) {
+	rows, err := tx.Query(ctx,
+		"SELECT "+walletColumns+" FROM wallets WHERE id = ANY($1) ORDER BY id FOR UPDATE",
+		walletIDs)
 	if err != nil {
-		return apperrors.NewDatabaseError("создание транзакции для пополнения", err)
+		return nil, apperrors.NewDatabaseError("блокировке кошельков", err)
 	}
-	defer tx.Rollback(ctx)
+	defer rows.Close()
 
-	// Обновляем баланс
-	// UPDATE сам блокирует строку, поэтому SELECT FOR UPDATE не обязателен для Deposit
-	query := "UPDATE wallets SET balance = balance + $1 WHERE id = $2"
-	result, err := tx.Exec(ctx, query, amount, walletID)
-	if err != nil {
-		return apperrors.NewDatabaseError("пополнении баланса", err)
+	wallets := make(map[uuid.UUID]*repository.Wallet, len(walletIDs))
+	locked := make([]*repository.Wallet, 0, len(walletIDs))
+	for rows.Next() {
+		w, err := scanWallet(rows)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("блокировке кошельков", err)
+		}
+		wallets[w.ID] = w
+		locked = append(locked, w)
 	}
-
-	if result.RowsAffected() == 0 {
-		return apperrors.ErrWalletNotFound
+	if err := rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseError("блокировке кошельков", err)
 	}
-
-	// Коммитим транзакцию
-	if err := tx.Commit(ctx); err != nil {
-		return apperrors.NewDatabaseError("фиксация транзакции пополнения", err)
+	if err := foldBalanceBuckets(ctx, tx, locked...); err != nil {
+		return nil, err
 	}
-
-	return nil
+	return wallets, nil
 }
 
-func (r *walletRepository) Withdraw(ctx context.Context, walletID uuid.UUID, amount int64) error {
-	// Начинаем транзакцию для предотвращения race conditions
-	tx, err := r.pool.Begin(ctx)
-	if err != nil {
-		return apperrors.NewDatabaseError("создание транзакции для списания", err)
+// checkWalletActive возвращает ошибку, если статус кошелька запрещает операции с балансом
+func checkWalletActive(status repository.WalletStatus) error {
+	switch status {
+	case repository.WalletFrozen:
+		return apperrors.ErrWalletFrozen
+	case repository.WalletClosed:
+		return apperrors.ErrWalletClosed
+	default:
+		return nil
 	}
-	defer tx.Rollback(ctx)
+}
 
-	var balance int64
-	err = tx.QueryRow(ctx, "SELECT balance FROM wallets WHERE id = $1 FOR UPDATE", walletID).Scan(&balance)
+// balanceDeltaQuery меняет баланс кошелька $1 на $2 одним условным UPDATE: кошелёк должен быть
+// активен и не шардирован, его валюта совпадать с $3 (пустая строка - без проверки), версия входить
+// в $4 (NULL - без проверки), а при списании доступных средств должно хватать с учётом блокировок
+// и кредитного лимита. Строка кошелька из снимка запроса возвращается и тогда, когда условие
+// не выполнено, и позволяет назвать причину отказа без дополнительного запроса; отсутствие строк
+// означает, что кошелька нет. Последняя колонка сообщает, заданы ли для кошелька лимиты операции $5:
+// без них проверка лимитов не требует отдельного запроса под блокировкой строки.
+const balanceDeltaQuery = `WITH updated AS (
+		UPDATE wallets SET balance = balance + $2::bigint
+		WHERE id = $1 AND status = 'ACTIVE' AND ($3::text = '' OR currency = $3) AND balance_buckets = 0
+			AND ($4::bigint[] IS NULL OR version = ANY($4))
+			AND ($2 >= 0 OR balance - held + credit_limit + $2 >= 0)
+		RETURNING balance
+	)
+	SELECT u.balance, w.currency, w.status, w.balance_buckets, w.version, w.balance - w.held + w.credit_limit,
+		EXISTS (SELECT 1 FROM operation_limits l WHERE l.operation = $5 AND (l.wallet_id = $1 OR l.wallet_id IS NULL))
+	FROM wallets w LEFT JOIN updated u ON true
+	WHERE w.id = $1`
+
+// balanceDelta - результат applyBalanceDelta
+type balanceDelta struct {
+	balance  int64
+	currency string
+	// limited - для кошелька заданы лимиты операции, и их нужно проверить
+	limited bool
+}
+
+// applyBalanceDelta меняет баланс кошелька на delta и возвращает баланс после изменения и валюту
+// кошелька. Строка кошелька остаётся заблокированной до конца транзакции. Для шардированного
+// кошелька баланс не меняется: возвращается errBalanceSharded вместе с валютой кошелька.
+// Непустой versions ограничивает допустимые версии кошелька.
+func applyBalanceDelta(ctx context.Context, tx pgx.Tx, walletID uuid.UUID, delta int64, currency string, versions []int64,
+	operation repository.LimitOperation) (balanceDelta, error) {
+	var (
+		balance   *int64
+		result    balanceDelta
+		status    repository.WalletStatus
+		buckets   int
+		version   int64
+		available int64
+	)
+	err := tx.QueryRow(ctx, balanceDeltaQuery, walletID, delta, currency, versions, operation).
+		Scan(&balance, &result.currency, &status, &buckets, &version, &available, &result.limited)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return apperrors.ErrWalletNotFound
+			return balanceDelta{}, apperrors.ErrWalletNotFound
 		}
-		return apperrors.NewDatabaseError("получение баланса для списания", err)
+		return balanceDelta{}, apperrors.NewDatabaseError("изменении баланса", err)
+	}
+	if balance != nil {
+		result.balance = *balance
+		return result, nil
 	}
 
-	// Проверяем достаточность средств
-	if balance < amount {
-		return apperrors.ErrInsufficientFunds
+	// UPDATE не выполнен: причину определяем по строке кошелька из снимка запроса
+	if err := checkWalletActive(status); err != nil {
+		return balanceDelta{}, err
+	}
+	if currency != "" && currency != result.currency {
+		return balanceDelta{}, apperrors.ErrCurrencyMismatch
+	}
+	if buckets > 0 {
+		return balanceDelta{currency: result.currency}, errBalanceSharded
+	}
+	// UPDATE проверяет условие по последней версии строки, а снимок может её не видеть: если по снимку
+	// операция допустима, строку изменила параллельная транзакция и версия уже другая
+	if versions != nil && (!slices.Contains(versions, version) || available+delta >= 0) {
+		return balanceDelta{}, apperrors.ErrVersionMismatch
 	}
+	return balanceDelta{}, apperrors.ErrInsufficientFunds
+}
 
-	// Обновляем баланс
-	query := "UPDATE wallets SET balance = balance - $1 WHERE id = $2"
-	result, err := tx.Exec(ctx, query, amount, walletID)
+// changeBalance меняет баланс кошелька op.WalletID на delta и проверяет лимиты операции operation.
+// Возвращает баланс после изменения и валюту кошелька.
+func changeBalance(ctx context.Context, tx pgx.Tx, op repository.Operation, delta int64, operation repository.LimitOperation) (int64, string, error) {
+	walletID, currency := op.WalletID, op.Currency
+	amount := max(delta, -delta)
+	result, err := applyBalanceDelta(ctx, tx, walletID, delta, currency, op.ExpectedVersions, operation)
+	if stderrors.Is(err, errBalanceSharded) {
+		balance, err := changeShardedBalance(ctx, tx, op, delta, operation)
+		return balance, result.currency, err
+	}
 	if err != nil {
-		return apperrors.NewDatabaseError("списание баланса", err)
+		return 0, "", err
 	}
 
-	if result.RowsAffected() == 0 {
-		return apperrors.ErrWalletNotFound
+	// Строка кошелька заблокирована UPDATE, поэтому лимиты учитывают все параллельные операции;
+	// нарушение лимита откатывает изменение баланса. Без лимитов операции запрос не нужен
+	if result.limited {
+		if err := checkLimit(ctx, tx, walletID, operation, amount); err != nil {
+			return 0, "", err
+		}
 	}
+	return result.balance, result.currency, nil
+}
 
-	// Коммитим транзакцию
-	if err := tx.Commit(ctx); err != nil {
-		return apperrors.NewDatabaseError("фиксация транзакции списания", err)
+type walletRepository struct {
+	pool *pgxpool.Pool
+	tx   txRunner
+}
+
+// NewWalletRepository создаёт репозиторий; транзакции, прерванные конфликтом
+// с параллельными транзакциями, повторяются по policy
+func NewWalletRepository(pool *pgxpool.Pool, policy RetryPolicy) repository.WalletRepository {
+	return &walletRepository{pool: pool, tx: newTxRunner(pool, policy)}
+}
+
+func (r *walletRepository) GetWallet(ctx context.Context, walletID uuid.UUID) (*repository.Wallet, error) {
+	wallet, err := scanWallet(r.pool.QueryRow(ctx, "SELECT "+walletColumns+" FROM wallets_with_buckets WHERE id = $1", walletID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrWalletNotFound
+		}
+		return nil, apperrors.NewDatabaseError("получении кошелька", err)
 	}
+	return wallet, nil
+}
+
+func (r *walletRepository) Deposit(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	return runTx(ctx, r.tx, "пополнения", readCommitted, func(tx pgx.Tx) (*repository.Transaction, error) {
+		// Повтор запроса с тем же ключом идемпотентности не изменяет баланс
+		if op.IdempotencyKey != nil {
+			var stored repository.Transaction
+			replayed, err := claimIdempotencyKey(ctx, tx, op.IdempotencyKey, &stored)
+			if err != nil {
+				return nil, err
+			}
+			if replayed {
+				return &stored, nil
+			}
+		}
 
-	return nil
+		// Баланс меняется одним условным UPDATE, который сразу блокирует строку кошелька,
+		// у шардированного кошелька - строку одной из корзин
+		balance, currencyCode, err := changeBalance(ctx, tx, op, op.Amount, repository.LimitDeposit)
+		if err != nil {
+			return nil, err
+		}
+
+		// Деньги поступают в кошелёк со счёта внешних поступлений, проводка и запись
+		// истории пишутся в той же транзакции, что и баланс
+		entry := &repository.Transaction{
+			WalletID:     op.WalletID,
+			Type:         repository.TransactionDeposit,
+			Amount:       op.Amount,
+			BalanceAfter: balance,
+		}
+		lines := []journalLine{
+			walletLine(op.WalletID, currencyCode, op.Amount),
+			systemLine(repository.SystemAccountExternalFunding, currencyCode, -op.Amount),
+		}
+		if err := postJournalEntry(ctx, tx, repository.JournalDeposit, lines, entry); err != nil {
+			return nil, err
+		}
+
+		if op.IdempotencyKey != nil {
+			if err := saveIdempotencyResponse(ctx, tx, op.IdempotencyKey, entry); err != nil {
+				return nil, err
+			}
+		}
+
+		return entry, nil
+	})
 }
 
-func (r *walletRepository) CreateWallet(ctx context.Context) (*repository.Wallet, error) {
-	var wallet repository.Wallet
-	walletID := uuid.New()
-	err := r.pool.QueryRow(ctx, "INSERT INTO wallets (id, balance) VALUES ($1, 0) RETURNING id, balance", walletID).Scan(&wallet.ID, &wallet.Balance)
-	var pgErr *pgconn.PgError
+func (r *walletRepository) Withdraw(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	return runTx(ctx, r.tx, "списания", readCommitted, func(tx pgx.Tx) (*repository.Transaction, error) {
+		// Повтор запроса с тем же ключом идемпотентности не изменяет баланс
+		if op.IdempotencyKey != nil {
+			var stored repository.Transaction
+			replayed, err := claimIdempotencyKey(ctx, tx, op.IdempotencyKey, &stored)
+			if err != nil {
+				return nil, err
+			}
+			if replayed {
+				return &stored, nil
+			}
+		}
+
+		// Статус, валюта и достаточность средств с учётом блокировок и кредитного лимита проверяются
+		// в условии UPDATE, поэтому строка кошелька блокируется без предварительного SELECT FOR UPDATE
+		balance, currencyCode, err := changeBalance(ctx, tx, op, -op.Amount, repository.LimitWithdraw)
+		if err != nil {
+			return nil, err
+		}
+
+		// Списанные деньги уходят на счёт выплат, проводка и запись истории пишутся в той же транзакции
+		entry := &repository.Transaction{
+			WalletID:     op.WalletID,
+			Type:         repository.TransactionWithdraw,
+			Amount:       -op.Amount,
+			BalanceAfter: balance,
+		}
+		lines := []journalLine{
+			walletLine(op.WalletID, currencyCode, -op.Amount),
+			systemLine(repository.SystemAccountPayouts, currencyCode, op.Amount),
+		}
+		if err := postJournalEntry(ctx, tx, repository.JournalWithdraw, lines, entry); err != nil {
+			return nil, err
+		}
+
+		if op.IdempotencyKey != nil {
+			if err := saveIdempotencyResponse(ctx, tx, op.IdempotencyKey, entry); err != nil {
+				return nil, err
+			}
+		}
+
+		return entry, nil
+	})
+}
+
+func (r *walletRepository) CreateWallet(ctx context.Context, params repository.NewWallet) (*repository.Wallet, error) {
+	walletID := params.ID
+	if walletID == uuid.Nil {
+		walletID = uuid.New()
+	}
+	var externalRef *string
+	if params.ExternalRef != "" {
+		externalRef = &params.ExternalRef
+	}
+	event, err := json.Marshal(events.WalletCreatedPayload{
+		Currency:    params.Currency,
+		OwnerID:     params.OwnerID,
+		ExternalRef: externalRef,
+	})
 	if err != nil {
-		if stderrors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			return nil, apperrors.ErrWalletAlreadyExists
+		return nil, apperrors.NewDatabaseError("сериализации события", err)
+	}
+
+	// Кошелёк и событие о его создании записываются в одной транзакции;
+	// идентификатор транзакции назначается до получения sequence события
+	return runTx(ctx, r.tx, "создания кошелька", readCommitted, func(tx pgx.Tx) (*repository.Wallet, error) {
+		if _, err := tx.Exec(ctx, outboxXactQuery); err != nil {
+			return nil, apperrors.NewDatabaseError("создании кошелька", err)
+		}
+		wallet, err := scanWallet(tx.QueryRow(ctx,
+			`WITH created AS (
+				INSERT INTO wallets (id, balance, currency, owner_id, external_ref) VALUES ($1, 0, $2, $3, $4)
+				RETURNING `+walletColumns+`
+			), event AS (
+				INSERT INTO outbox_events (event_id, wallet_id, type, payload)
+				SELECT $5, id, $6, $7 FROM created
+			)
+			SELECT `+walletColumns+` FROM created`,
+			walletID, params.Currency, params.OwnerID, externalRef, uuid.New(), events.WalletCreated, event))
+		var pgErr *pgconn.PgError
+		if err != nil {
+			if stderrors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				return nil, apperrors.ErrWalletAlreadyExists
+			}
+			return nil, apperrors.NewDatabaseError("создании кошелька", err)
 		}
-		return nil, apperrors.NewDatabaseError("создании кошелька", err)
+		return wallet, nil
+	})
+}
+
+func (r *walletRepository) FindWalletByExternalRef(ctx context.Context, ownerID, externalRef string) (*repository.Wallet, error) {
+	wallet, err := scanWallet(r.pool.QueryRow(ctx,
+		"SELECT "+walletColumns+" FROM wallets_with_buckets WHERE owner_id = $1 AND external_ref = $2",
+		ownerID, externalRef))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrWalletNotFound
+		}
+		return nil, apperrors.NewDatabaseError("поиске кошелька по внешнему идентификатору", err)
 	}
-	return &wallet, nil
+	return wallet, nil
 }
@@ -0,0 +1,297 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// webhookSubscriptionColumns - колонки webhook_subscriptions в порядке, ожидаемом scanWebhookSubscription
+const webhookSubscriptionColumns = "id, url, secret, event_types, wallet_id, active, created_at, updated_at"
+
+func scanWebhookSubscription(row pgx.Row) (*repository.WebhookSubscription, error) {
+	var s repository.WebhookSubscription
+	if err := row.Scan(&s.ID, &s.URL, &s.Secret, &s.EventTypes, &s.WalletID, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// webhookDeliveryColumns - колонки webhook_deliveries в порядке полей webhookDeliveryFields
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at`
+
+// webhookDeliveryFields возвращает адреса полей доставки для Scan
+func webhookDeliveryFields(d *repository.WebhookDelivery) []any {
+	return []any{&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
+
+type webhookRepository struct {
+	pool *pgxpool.Pool
+	tx   txRunner
+}
+
+// NewWebhookRepository создаёт репозиторий; транзакции, прерванные конфликтом
+// с параллельными транзакциями, повторяются по policy
+func NewWebhookRepository(pool *pgxpool.Pool, policy RetryPolicy) repository.WebhookRepository {
+	return &webhookRepository{pool: pool, tx: newTxRunner(pool, policy)}
+}
+
+// webhookWriteError преобразует ошибку записи подписки: ссылка на несуществующий кошелёк - ErrWalletNotFound
+func webhookWriteError(operation string, err error) error {
+	var pgErr *pgconn.PgError
+	if stderrors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+		return apperrors.ErrWalletNotFound
+	}
+	return apperrors.NewDatabaseError(operation, err)
+}
+
+// eventTypesArg возвращает пустой массив вместо nil: колонка event_types не допускает NULL
+func eventTypesArg(eventTypes []string) []string {
+	if eventTypes == nil {
+		return []string{}
+	}
+	return eventTypes
+}
+
+func (r *webhookRepository) CreateWebhookSubscription(ctx context.Context, params repository.WebhookSubscriptionParams, secret string) (*repository.WebhookSubscription, error) {
+	id := uuid.New()
+	return runTx(ctx, r.tx, "создания подписки на события", readCommitted, func(tx pgx.Tx) (*repository.WebhookSubscription, error) {
+		sub, err := scanWebhookSubscription(tx.QueryRow(ctx,
+			`INSERT INTO webhook_subscriptions (id, url, secret, event_types, wallet_id, active)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+webhookSubscriptionColumns,
+			id, params.URL, secret, eventTypesArg(params.EventTypes), params.WalletID, params.Active))
+		if err != nil {
+			return nil, webhookWriteError("создании подписки на события", err)
+		}
+		return sub, nil
+	})
+}
+
+func (r *webhookRepository) GetWebhookSubscription(ctx context.Context, id uuid.UUID) (*repository.WebhookSubscription, error) {
+	return getWebhookSubscription(ctx, r.pool, id)
+}
+
+// getWebhookSubscription читает подписку через q - пул или транзакцию
+func getWebhookSubscription(ctx context.Context, q pgxQuerier, id uuid.UUID) (*repository.WebhookSubscription, error) {
+	sub, err := scanWebhookSubscription(q.QueryRow(ctx,
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions WHERE id = $1", id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, apperrors.ErrWebhookNotFound
+		}
+		return nil, apperrors.NewDatabaseError("получении подписки на события", err)
+	}
+	return sub, nil
+}
+
+func (r *webhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]repository.WebhookSubscription, error) {
+	rows, err := r.pool.Query(ctx,
+		"SELECT "+webhookSubscriptionColumns+" FROM webhook_subscriptions ORDER BY created_at, id")
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("получении подписок на события", err)
+	}
+	subs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.WebhookSubscription, error) {
+		sub, err := scanWebhookSubscription(row)
+		if err != nil {
+			return repository.WebhookSubscription{}, err
+		}
+		return *sub, nil
+	})
+	if err != nil {
+		return nil, apperrors.NewDatabaseError("чтении подписок на события", err)
+	}
+	return subs, nil
+}
+
+func (r *webhookRepository) UpdateWebhookSubscription(ctx context.Context, id uuid.UUID, params repository.WebhookSubscriptionParams) (*repository.WebhookSubscription, error) {
+	return runTx(ctx, r.tx, "изменения подписки на события", readCommitted, func(tx pgx.Tx) (*repository.WebhookSubscription, error) {
+		sub, err := scanWebhookSubscription(tx.QueryRow(ctx,
+			`UPDATE webhook_subscriptions
+			SET url = $2, event_types = $3, wallet_id = $4, active = $5, updated_at = now()
+			WHERE id = $1
+			RETURNING `+webhookSubscriptionColumns,
+			id, params.URL, eventTypesArg(params.EventTypes), params.WalletID, params.Active))
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil, apperrors.ErrWebhookNotFound
+			}
+			return nil, webhookWriteError("изменении подписки на события", err)
+		}
+		return sub, nil
+	})
+}
+
+func (r *webhookRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	return r.tx.run(ctx, "удаления подписки на события", readCommitted, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+		if err != nil {
+			return apperrors.NewDatabaseError("удалении подписки на события", err)
+		}
+		if result.RowsAffected() == 0 {
+			return apperrors.ErrWebhookNotFound
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepository) ListWebhookDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]repository.WebhookDelivery, error) {
+	conditions := []string{"d.subscription_id = $1"}
+	args := []any{filter.SubscriptionID}
+	addArg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Status != nil {
+		conditions = append(conditions, "d.status = "+addArg(string(*filter.Status)))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(d.created_at, d.id) < (%s, %s)", addArg(filter.After.CreatedAt), addArg(filter.After.ID)))
+	}
+
+	query := fmt.Sprintf(`SELECT %s
+		FROM webhook_deliveries d
+		WHERE %s
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT %s`, webhookDeliveryColumns, strings.Join(conditions, " AND "), addArg(filter.Limit))
+
+	return runTx(ctx, r.tx, "получения доставок событий", snapshotReadOnly, func(tx pgx.Tx) ([]repository.WebhookDelivery, error) {
+		// Пустой список доставок и отсутствующая подписка должны различаться для клиента
+		if _, err := getWebhookSubscription(ctx, tx, filter.SubscriptionID); err != nil {
+			return nil, err
+		}
+
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("получении доставок событий", err)
+		}
+		deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.WebhookDelivery, error) {
+			var d repository.WebhookDelivery
+			err := row.Scan(webhookDeliveryFields(&d)...)
+			return d, err
+		})
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("чтении доставок событий", err)
+		}
+		return deliveries, nil
+	})
+}
+
+func (r *webhookRepository) ReplayWebhookDeliveries(ctx context.Context, subscriptionID uuid.UUID, deliveryIDs []uuid.UUID) (int64, error) {
+	condition := "status = 'DEAD'"
+	args := []any{subscriptionID}
+	if len(deliveryIDs) > 0 {
+		condition = "id = ANY($2)"
+		args = append(args, deliveryIDs)
+	}
+	return runTx(ctx, r.tx, "повторной постановки доставок в очередь", readCommitted, func(tx pgx.Tx) (int64, error) {
+		if _, err := getWebhookSubscription(ctx, tx, subscriptionID); err != nil {
+			return 0, err
+		}
+		result, err := tx.Exec(ctx,
+			`UPDATE webhook_deliveries
+			SET status = 'PENDING', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+			WHERE subscription_id = $1 AND `+condition, args...)
+		if err != nil {
+			return 0, apperrors.NewDatabaseError("повторной постановке доставок в очередь", err)
+		}
+		return result.RowsAffected(), nil
+	})
+}
+
+func (r *webhookRepository) EnqueueWebhookDeliveries(ctx context.Context, event repository.WebhookEvent) (int64, error) {
+	return runTx(ctx, r.tx, "создания доставок события", readCommitted, func(tx pgx.Tx) (int64, error) {
+		result, err := tx.Exec(ctx,
+			`INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload)
+			SELECT gen_random_uuid(), s.id, $1, $3, $4
+			FROM webhook_subscriptions s
+			WHERE s.active
+				AND (cardinality(s.event_types) = 0 OR $3 = ANY(s.event_types))
+				AND (s.wallet_id IS NULL OR s.wallet_id = $2)
+			ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+			event.ID, event.WalletID, event.Type, event.Payload)
+		if err != nil {
+			return 0, apperrors.NewDatabaseError("создании доставок события", err)
+		}
+		return result.RowsAffected(), nil
+	})
+}
+
+func (r *webhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]repository.WebhookDispatch, error) {
+	return runTx(ctx, r.tx, "выборки доставок событий", readCommitted, func(tx pgx.Tx) ([]repository.WebhookDispatch, error) {
+		// SKIP LOCKED позволяет нескольким процессам разбирать очередь, не ожидая друг друга
+		rows, err := tx.Query(ctx,
+			`WITH due AS (
+				SELECT d.id
+				FROM webhook_deliveries d
+				JOIN webhook_subscriptions s ON s.id = d.subscription_id
+				WHERE d.status = 'PENDING' AND d.next_attempt_at <= now() AND s.active
+				ORDER BY d.next_attempt_at
+				LIMIT $1
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			UPDATE webhook_deliveries d
+			SET next_attempt_at = $2
+			FROM due, webhook_subscriptions s
+			WHERE d.id = due.id AND s.id = d.subscription_id
+			RETURNING `+webhookDeliveryColumns+`, s.url, s.secret`,
+			limit, leaseUntil)
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("выборке доставок событий", err)
+		}
+		dispatches, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repository.WebhookDispatch, error) {
+			var d repository.WebhookDispatch
+			err := row.Scan(append(webhookDeliveryFields(&d.WebhookDelivery), &d.URL, &d.Secret)...)
+			return d, err
+		})
+		if err != nil {
+			return nil, apperrors.NewDatabaseError("чтении доставок событий", err)
+		}
+		return dispatches, nil
+	})
+}
+
+func (r *webhookRepository) RecordWebhookAttempt(ctx context.Context, attempt repository.WebhookAttempt) error {
+	var (
+		statusCode *int
+		lastError  *string
+	)
+	if attempt.StatusCode != 0 {
+		statusCode = &attempt.StatusCode
+	}
+	if attempt.Error != "" {
+		lastError = &attempt.Error
+	}
+
+	// Повторная постановка в очередь меняет next_attempt_at: результат устаревшей попытки не сохраняется,
+	// и доставка будет выполнена заново
+	return r.tx.run(ctx, "сохранения результата доставки события", readCommitted, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			`UPDATE webhook_deliveries
+			SET attempts = attempts + 1,
+				status = $2,
+				next_attempt_at = CASE WHEN $2 = 'PENDING' THEN $3 ELSE next_attempt_at END,
+				last_status_code = $4,
+				last_error = $5,
+				delivered_at = CASE WHEN $2 = 'DELIVERED' THEN now() END
+			WHERE id = $1 AND status = 'PENDING' AND next_attempt_at = $6`,
+			attempt.DeliveryID, attempt.Status, attempt.NextAttemptAt, statusCode, lastError, attempt.ClaimedUntil)
+		if err != nil {
+			return apperrors.NewDatabaseError("сохранении результата доставки события", err)
+		}
+		return nil
+	})
+}
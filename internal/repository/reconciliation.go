@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BalanceDrift - расхождение баланса кошелька с суммой его строк в журнале.
+// Drift = Balance - LedgerBalance; Corrected означает, что расхождение закрыто корректирующей проводкой.
+// Error - причина, по которой корректировка кошелька не удалась.
+type BalanceDrift struct {
+	WalletID      uuid.UUID `json:"walletId"`
+	Currency      string    `json:"currency"`
+	Balance       int64     `json:"balance"`
+	LedgerBalance int64     `json:"ledgerBalance"`
+	Drift         int64     `json:"drift"`
+	Corrected     bool      `json:"corrected"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// ReconciliationRun - результат запуска сверки балансов с журналом
+type ReconciliationRun struct {
+	ID             uuid.UUID      `json:"id"`
+	StartedAt      time.Time      `json:"startedAt"`
+	FinishedAt     time.Time      `json:"finishedAt"`
+	AutoCorrect    bool           `json:"autoCorrect"`
+	WalletsChecked int64          `json:"walletsChecked"`
+	Drifts         []BalanceDrift `json:"drifts"`
+}
+
+type ReconciliationRepository interface {
+	// FindBalanceDrifts сравнивает балансы всех кошельков с журналом на одном снимке данных
+	// и возвращает число проверенных кошельков и найденные расхождения
+	FindBalanceDrifts(ctx context.Context) (int64, []BalanceDrift, error)
+	// PostAdjustment перепроверяет кошелёк под блокировкой и, если расхождение осталось,
+	// выравнивает журнал с балансом проводкой ADJUSTMENT на счёт RECONCILIATION и записью в истории.
+	// Возвращает закрытое расхождение или nil, если его уже нет.
+	PostAdjustment(ctx context.Context, walletID uuid.UUID) (*BalanceDrift, error)
+	SaveReconciliationRun(ctx context.Context, run *ReconciliationRun) error
+	// GetLastReconciliationRun возвращает ErrReconciliationNotFound, если сверка ещё не выполнялась
+	GetLastReconciliationRun(ctx context.Context) (*ReconciliationRun, error)
+}
@@ -2,19 +2,263 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// Wallet представляет структуру кошелька
+// WalletStatus представляет состояние жизненного цикла кошелька
+type WalletStatus string
+
+const (
+	// WalletActive - кошелёк доступен для всех операций
+	WalletActive WalletStatus = "ACTIVE"
+	// WalletFrozen - операции с балансом запрещены до разморозки
+	WalletFrozen WalletStatus = "FROZEN"
+	// WalletClosed - кошелёк закрыт окончательно
+	WalletClosed WalletStatus = "CLOSED"
+)
+
+// Wallet представляет структуру кошелька.
+// Balance хранится в минимальных единицах валюты Currency (код ISO 4217).
+// Held - сумма активных блокировок, недоступная для списания.
+// CreditLimit - кредитный лимит: баланс за вычетом блокировок может опускаться до -CreditLimit.
+// ExternalRef - идентификатор кошелька во внешней системе, уникален в пределах OwnerID.
+// BalanceBuckets - число корзин шардированного баланса; 0 - баланс хранится в одной строке.
+// Version растёт при каждом изменении баланса, блокировок, кредитного лимита или статуса.
 type Wallet struct {
-	ID      uuid.UUID
-	Balance int64
+	ID             uuid.UUID
+	Balance        int64
+	Held           int64
+	CreditLimit    int64
+	Currency       string
+	Status         WalletStatus
+	OwnerID        string
+	ExternalRef    *string
+	CreatedAt      time.Time
+	BalanceBuckets int
+	Version        int64
+}
+
+// MaxBalanceBuckets - наибольшее число корзин шардированного баланса
+const MaxBalanceBuckets = 64
+
+// Available возвращает сумму, доступную для списания, с учётом кредитного лимита
+func (w *Wallet) Available() int64 {
+	return w.Balance - w.Held + w.CreditLimit
+}
+
+// CreditUsed возвращает использованную часть кредитного лимита
+func (w *Wallet) CreditUsed() int64 {
+	return max(w.Held-w.Balance, 0)
+}
+
+// NewWallet описывает параметры создания кошелька.
+// Нулевой ID означает, что идентификатор генерируется сервером; пустой ExternalRef - что он не задан.
+type NewWallet struct {
+	ID          uuid.UUID
+	Currency    string
+	OwnerID     string
+	ExternalRef string
+}
+
+// StatusChange описывает смену статуса кошелька.
+// SweepToWalletID используется только при закрытии: остаток баланса переводится на этот кошелёк.
+type StatusChange struct {
+	WalletID        uuid.UUID
+	Status          WalletStatus
+	Reason          string
+	SweepToWalletID *uuid.UUID
+}
+
+// IdempotencyKey связывает повторные запросы клиента с результатом первого выполнения.
+// Fingerprint - отпечаток тела запроса: повтор с тем же ключом, но другим телом отклоняется.
+type IdempotencyKey struct {
+	Key         string
+	Fingerprint string
+}
+
+// Operation описывает операцию изменения баланса кошелька.
+// Currency необязательна: если задана, она должна совпадать с валютой кошелька.
+// ExpectedVersions, если задан, - допустимые версии кошелька: при другой версии операция
+// отклоняется с ErrVersionMismatch.
+type Operation struct {
+	WalletID         uuid.UUID
+	Amount           int64
+	Currency         string
+	IdempotencyKey   *IdempotencyKey
+	ExpectedVersions []int64
+}
+
+// Transfer описывает перевод между двумя кошельками.
+// Кошельки должны быть в одной валюте; Currency, если задана, должна с ней совпадать.
+type Transfer struct {
+	FromWalletID   uuid.UUID
+	ToWalletID     uuid.UUID
+	Amount         int64
+	Currency       string
+	IdempotencyKey *IdempotencyKey
+}
+
+// TransferResult содержит записи истории обеих сторон перевода
+type TransferResult struct {
+	Debit  Transaction
+	Credit Transaction
+}
+
+// TransactionType представляет тип записи в истории операций
+type TransactionType string
+
+const (
+	TransactionDeposit     TransactionType = "DEPOSIT"
+	TransactionWithdraw    TransactionType = "WITHDRAW"
+	TransactionTransferIn  TransactionType = "TRANSFER_IN"
+	TransactionTransferOut TransactionType = "TRANSFER_OUT"
+	TransactionHoldCapture TransactionType = "HOLD_CAPTURE"
+	// TransactionAdjustment - исправление баланса сверкой по журналу, проводки не имеет
+	TransactionAdjustment TransactionType = "ADJUSTMENT"
+)
+
+// Transaction представляет запись в истории операций кошелька.
+// Amount положительный для зачислений и отрицательный для списаний.
+// CounterpartyWalletID заполнен для переводов и указывает на второй кошелёк.
+// JournalEntryID - проводка журнала, которой создана запись; пуст для записей, сделанных до ведения журнала.
+type Transaction struct {
+	ID                   uuid.UUID
+	WalletID             uuid.UUID
+	Type                 TransactionType
+	Amount               int64
+	BalanceAfter         int64
+	CounterpartyWalletID *uuid.UUID
+	JournalEntryID       *uuid.UUID
+	CreatedAt            time.Time
+}
+
+// HistoricalBalance - баланс кошелька на момент At
+type HistoricalBalance struct {
+	WalletID uuid.UUID
+	Currency string
+	At       time.Time
+	Balance  int64
+}
+
+// StatementQuery описывает выписку кошелька за период [From, To).
+// Без From выписка начинается с момента создания кошелька.
+type StatementQuery struct {
+	WalletID uuid.UUID
+	From     *time.Time
+	To       time.Time
+}
+
+// Statement - начало выписки: период и баланс кошелька на его начало
+type Statement struct {
+	WalletID       uuid.UUID
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+}
+
+// StatementEntry - операция выписки; Balance - баланс кошелька после неё
+type StatementEntry struct {
+	ID                   uuid.UUID
+	Type                 TransactionType
+	Amount               int64
+	Balance              int64
+	CounterpartyWalletID *uuid.UUID
+	CreatedAt            time.Time
+}
+
+// StatementVisitor получает выписку по мере чтения из БД: Begin - до первой операции,
+// Entry - для каждой операции периода в хронологическом порядке, End - с балансом на конец периода.
+// Ошибка любого из методов прерывает чтение и возвращается из StreamStatement.
+type StatementVisitor interface {
+	Begin(statement Statement) error
+	Entry(entry StatementEntry) error
+	End(closingBalance int64) error
+}
+
+// TransactionCursor указывает на последнюю запись предыдущей страницы истории
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// TransactionFilter описывает выборку истории операций кошелька.
+// Записи возвращаются от новых к старым, From включительно, To исключительно.
+type TransactionFilter struct {
+	WalletID uuid.UUID
+	Type     *TransactionType
+	From     *time.Time
+	To       *time.Time
+	After    *TransactionCursor
+	Limit    int
+}
+
+// WalletSort задаёт поле сортировки списка кошельков
+type WalletSort string
+
+const (
+	WalletSortCreatedAt WalletSort = "created_at"
+	WalletSortBalance   WalletSort = "balance"
+)
+
+// WalletCursor указывает на последний кошелёк предыдущей страницы списка.
+// Из CreatedAt и Balance используется поле, по которому отсортирован список.
+type WalletCursor struct {
+	CreatedAt time.Time
+	Balance   int64
+	ID        uuid.UUID
+}
+
+// WalletFilter описывает выборку кошельков.
+// Границы баланса включительны, CreatedFrom включительно, CreatedTo исключительно.
+type WalletFilter struct {
+	OwnerID     *string
+	ExternalRef *string
+	Status      *WalletStatus
+	Currency    string
+	MinBalance  *int64
+	MaxBalance  *int64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        WalletSort
+	Descending  bool
+	After       *WalletCursor
+	Limit       int
 }
 
 type WalletRepository interface {
 	GetWallet(ctx context.Context, walletID uuid.UUID) (*Wallet, error)
-	Deposit(ctx context.Context, walletID uuid.UUID, amount int64) error
-	Withdraw(ctx context.Context, walletID uuid.UUID, amount int64) error
-	CreateWallet(ctx context.Context) (*Wallet, error)
+	// Deposit и Withdraw возвращают запись истории с балансом после операции
+	Deposit(ctx context.Context, op Operation) (*Transaction, error)
+	Withdraw(ctx context.Context, op Operation) (*Transaction, error)
+	// ApplyBatch выполняет операции пакета в одной транзакции и возвращает результаты в порядке ops.
+	// В атомарном режиме первая неуспешная операция отменяет весь пакет и возвращается как *BatchItemError,
+	// иначе неуспешные операции пропускаются и отмечаются ошибкой в результате.
+	ApplyBatch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchItemResult, error)
+	// Transfer атомарно списывает сумму с одного кошелька и зачисляет на другой
+	Transfer(ctx context.Context, t Transfer) (*TransferResult, error)
+	// CreateWallet возвращает ErrWalletAlreadyExists, если занят ID или пара OwnerID и ExternalRef
+	CreateWallet(ctx context.Context, params NewWallet) (*Wallet, error)
+	FindWalletByExternalRef(ctx context.Context, ownerID, externalRef string) (*Wallet, error)
+	ListWallets(ctx context.Context, filter WalletFilter) ([]Wallet, error)
+	// ChangeWalletStatus меняет статус кошелька и записывает причину в журнал смены статусов
+	ChangeWalletStatus(ctx context.Context, change StatusChange) (*Wallet, error)
+	// SetCreditLimit меняет кредитный лимит кошелька; лимит не может быть меньше использованного кредита
+	SetCreditLimit(ctx context.Context, walletID uuid.UUID, creditLimit int64) (*Wallet, error)
+	// SetBalanceBuckets включает шардированный баланс из buckets корзин; 0 возвращает баланс в одну строку
+	SetBalanceBuckets(ctx context.Context, walletID uuid.UUID, buckets int) (*Wallet, error)
+	ListTransactions(ctx context.Context, filter TransactionFilter) ([]Transaction, error)
+	// GetBalanceAt возвращает баланс на момент at по ближайшему снимку и истории операций после него.
+	// Если кошелёк создан позже at, возвращается ErrWalletNotFound.
+	GetBalanceAt(ctx context.Context, walletID uuid.UUID, at time.Time) (*HistoricalBalance, error)
+	// StreamStatement читает выписку за период и передаёт её visitor, не загружая историю в память.
+	// Если кошелёк не найден, возвращается ErrWalletNotFound до вызова Begin.
+	StreamStatement(ctx context.Context, query StatementQuery, visitor StatementVisitor) error
+	// SnapshotBalances снимает балансы на момент upTo для кошельков с операциями после предыдущего снимка
+	// и возвращает число снимков. upTo должен отставать от текущего времени на длительность самой долгой транзакции.
+	SnapshotBalances(ctx context.Context, upTo time.Time) (int64, error)
+	// DeleteExpiredIdempotencyKeys удаляет ключи идемпотентности, созданные раньше before
+	DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscription - подписка на доставку событий на URL получателя.
+// Пустой EventTypes означает все типы событий, пустой WalletID - события всех кошельков.
+// Secret - ключ подписи HMAC-SHA256 тела запроса.
+type WebhookSubscription struct {
+	ID         uuid.UUID
+	URL        string
+	Secret     string
+	EventTypes []string
+	WalletID   *uuid.UUID
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WebhookSubscriptionParams описывает настройки подписки при создании и изменении
+type WebhookSubscriptionParams struct {
+	URL        string
+	EventTypes []string
+	WalletID   *uuid.UUID
+	Active     bool
+}
+
+// WebhookDeliveryStatus представляет состояние доставки события подписчику
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryDead - попытки доставки исчерпаны, доставка повторяется только вручную
+	WebhookDeliveryDead WebhookDeliveryStatus = "DEAD"
+)
+
+// WebhookDelivery - доставка одного события одному подписчику.
+// Payload - тело запроса, одинаковое во всех попытках.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookDispatch - доставка, выбранная для очередной попытки, вместе с адресом и ключом подписки
+type WebhookDispatch struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookEvent - событие, которое нужно доставить подходящим подписчикам
+type WebhookEvent struct {
+	ID       uuid.UUID
+	WalletID uuid.UUID
+	Type     string
+	Payload  []byte
+}
+
+// WebhookAttempt - результат попытки доставки. ClaimedUntil - NextAttemptAt доставки,
+// возвращённой ClaimWebhookDeliveries. Status - новый статус доставки;
+// NextAttemptAt учитывается только для статуса PENDING. StatusCode равен нулю,
+// если получатель не ответил.
+type WebhookAttempt struct {
+	DeliveryID    uuid.UUID
+	ClaimedUntil  time.Time
+	Status        WebhookDeliveryStatus
+	StatusCode    int
+	Error         string
+	NextAttemptAt time.Time
+}
+
+// WebhookDeliveryCursor - позиция в списке доставок: следующая страница начинается
+// с доставок, созданных раньше CreatedAt (при равенстве - с меньшим ID)
+type WebhookDeliveryCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// WebhookDeliveryFilter описывает выборку доставок подписки от новых к старым
+type WebhookDeliveryFilter struct {
+	SubscriptionID uuid.UUID
+	Status         *WebhookDeliveryStatus
+	After          *WebhookDeliveryCursor
+	Limit          int
+}
+
+type WebhookRepository interface {
+	CreateWebhookSubscription(ctx context.Context, params WebhookSubscriptionParams, secret string) (*WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id uuid.UUID) (*WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	UpdateWebhookSubscription(ctx context.Context, id uuid.UUID, params WebhookSubscriptionParams) (*WebhookSubscription, error)
+	// DeleteWebhookSubscription удаляет подписку вместе с её доставками
+	DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error
+	// ListWebhookDeliveries возвращает ErrWebhookNotFound, если подписки нет
+	ListWebhookDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]WebhookDelivery, error)
+	// ReplayWebhookDeliveries ставит доставки подписки в очередь заново с полным числом попыток:
+	// перечисленные в deliveryIDs в любом статусе, без deliveryIDs - все доставки в статусе DEAD.
+	// Возвращает число поставленных в очередь доставок.
+	ReplayWebhookDeliveries(ctx context.Context, subscriptionID uuid.UUID, deliveryIDs []uuid.UUID) (int64, error)
+
+	// EnqueueWebhookDeliveries создаёт доставки события для всех подходящих активных подписок.
+	// Повторный вызов для того же события доставки не дублирует.
+	EnqueueWebhookDeliveries(ctx context.Context, event WebhookEvent) (int64, error)
+	// ClaimWebhookDeliveries выбирает до limit доставок, время попытки которых наступило,
+	// и откладывает их следующую попытку до leaseUntil, чтобы их не взял другой процесс.
+	// Если процесс не сообщит результат попытки, доставка будет повторена после leaseUntil.
+	ClaimWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]WebhookDispatch, error)
+	// RecordWebhookAttempt сохраняет результат попытки доставки и увеличивает число попыток.
+	// Результат не сохраняется, если доставка с тех пор поставлена в очередь заново.
+	RecordWebhookAttempt(ctx context.Context, attempt WebhookAttempt) error
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+type batchService struct {
+	repo    repository.WalletRepository
+	maxSize int
+}
+
+func NewBatchService(repo repository.WalletRepository, maxSize int) BatchService {
+	return &batchService{repo: repo, maxSize: maxSize}
+}
+
+func (s *batchService) ProcessBatch(ctx context.Context, mode BatchMode, ops []repository.BatchOperation) ([]repository.BatchItemResult, error) {
+	if mode != BatchAtomic && mode != BatchBestEffort {
+		return nil, apperrors.ErrInvalidBatchMode
+	}
+	if len(ops) == 0 || len(ops) > s.maxSize {
+		return nil, apperrors.ErrInvalidBatchSize
+	}
+
+	// Некорректные операции отсекаются до обращения к БД: в атомарном режиме
+	// они отменяют пакет, в режиме best effort сразу попадают в результат с ошибкой
+	results := make([]repository.BatchItemResult, len(ops))
+	valid := make([]repository.BatchOperation, 0, len(ops))
+	indexes := make([]int, 0, len(ops))
+	for i, op := range ops {
+		if err := validateBatchOperation(&op); err != nil {
+			if mode == BatchAtomic {
+				return nil, &repository.BatchItemError{Index: i, Err: err}
+			}
+			results[i].Err = err
+			continue
+		}
+		valid = append(valid, op)
+		indexes = append(indexes, i)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	applied, err := s.repo.ApplyBatch(ctx, valid, mode == BatchAtomic)
+	if err != nil {
+		var itemErr *repository.BatchItemError
+		if stderrors.As(err, &itemErr) {
+			return nil, &repository.BatchItemError{Index: indexes[itemErr.Index], Err: itemErr.Err}
+		}
+		return nil, err
+	}
+	for i, result := range applied {
+		results[indexes[i]] = result
+	}
+	return results, nil
+}
+
+func validateBatchOperation(op *repository.BatchOperation) error {
+	if op.Type != repository.TransactionDeposit && op.Type != repository.TransactionWithdraw {
+		return apperrors.ErrInvalidOperationType
+	}
+	if op.Amount <= 0 {
+		return apperrors.ErrInvalidAmount
+	}
+	return normalizeCurrency(&op.Currency)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+// transactionCursor - сериализуемое представление курсора истории операций
+type transactionCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// encodeCursor кодирует курсор в непрозрачную для клиента строку
+func encodeCursor(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Курсоры состоят только из сериализуемых полей, ошибка здесь невозможна
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor разбирает строку курсора, полученную от клиента
+func decodeCursor(s string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return apperrors.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return apperrors.ErrInvalidCursor
+	}
+	return nil
+}
+
+func encodeTransactionCursor(t repository.Transaction) string {
+	return encodeCursor(transactionCursor{CreatedAt: t.CreatedAt, ID: t.ID})
+}
+
+func decodeTransactionCursor(s string) (*repository.TransactionCursor, error) {
+	var c transactionCursor
+	if err := decodeCursor(s, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	return &repository.TransactionCursor{CreatedAt: c.CreatedAt, ID: c.ID}, nil
+}
+
+// walletCursor - сериализуемое представление курсора списка кошельков.
+// Сортировка сохраняется в курсоре, чтобы курсор нельзя было применить к списку с другой сортировкой.
+type walletCursor struct {
+	Sort       repository.WalletSort `json:"s"`
+	Descending bool                  `json:"d,omitempty"`
+	CreatedAt  time.Time             `json:"t"`
+	Balance    int64                 `json:"b"`
+	ID         uuid.UUID             `json:"id"`
+}
+
+func encodeWalletCursor(w repository.Wallet, sort repository.WalletSort, descending bool) string {
+	return encodeCursor(walletCursor{
+		Sort:       sort,
+		Descending: descending,
+		CreatedAt:  w.CreatedAt,
+		Balance:    w.Balance,
+		ID:         w.ID,
+	})
+}
+
+func decodeWalletCursor(s string, sort repository.WalletSort, descending bool) (*repository.WalletCursor, error) {
+	var c walletCursor
+	if err := decodeCursor(s, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil || c.Sort != sort || c.Descending != descending {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	return &repository.WalletCursor{CreatedAt: c.CreatedAt, Balance: c.Balance, ID: c.ID}, nil
+}
+
+// webhookDeliveryCursor - сериализуемое представление курсора списка доставок
+type webhookDeliveryCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func encodeWebhookDeliveryCursor(d repository.WebhookDelivery) string {
+	return encodeCursor(webhookDeliveryCursor{CreatedAt: d.CreatedAt, ID: d.ID})
+}
+
+func decodeWebhookDeliveryCursor(s string) (*repository.WebhookDeliveryCursor, error) {
+	var c webhookDeliveryCursor
+	if err := decodeCursor(s, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil || c.CreatedAt.IsZero() {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	return &repository.WebhookDeliveryCursor{CreatedAt: c.CreatedAt, ID: c.ID}, nil
+}
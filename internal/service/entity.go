@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/devopesik/wallet-basic-operations/internal/repository"
 	"github.com/google/uuid"
@@ -15,9 +16,168 @@ const (
 	OperationWithdraw OperationType = "WITHDRAW"
 )
 
+// DefaultCurrency - валюта кошелька, если она не указана при создании
+const DefaultCurrency = "RUB"
+
+// maxExternalRefLength - максимальная длина externalRef и ownerId
+const maxExternalRefLength = 255
+
+// Параметры пагинации истории операций
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// TransactionQuery описывает запрос истории операций кошелька
+type TransactionQuery struct {
+	Type   *repository.TransactionType
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Cursor string
+}
+
+// TransactionPage представляет страницу истории операций.
+// NextCursor пустой, если следующей страницы нет.
+type TransactionPage struct {
+	Items      []repository.Transaction
+	NextCursor string
+}
+
+// StatementQuery описывает запрос выписки за период [From, To).
+// Без From выписка начинается с момента создания кошелька, без To - заканчивается текущим моментом.
+type StatementQuery struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// WalletQuery описывает запрос списка кошельков.
+// Sort - поле сортировки (по умолчанию время создания), Descending - обратный порядок.
+// ExternalRef без OwnerID ищется среди кошельков без владельца.
+type WalletQuery struct {
+	OwnerID     *string
+	ExternalRef *string
+	Status      *repository.WalletStatus
+	Currency    string
+	MinBalance  *int64
+	MaxBalance  *int64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        repository.WalletSort
+	Descending  bool
+	Limit       int
+	Cursor      string
+}
+
+// WalletPage представляет страницу списка кошельков.
+// NextCursor пустой, если следующей страницы нет.
+type WalletPage struct {
+	Items      []repository.Wallet
+	NextCursor string
+}
+
 type WalletService interface {
-	Deposit(ctx context.Context, walletID uuid.UUID, amount int64) error
-	Withdraw(ctx context.Context, walletID uuid.UUID, amount int64) error
+	Deposit(ctx context.Context, op repository.Operation) (*repository.Transaction, error)
+	Withdraw(ctx context.Context, op repository.Operation) (*repository.Transaction, error)
+	Transfer(ctx context.Context, t repository.Transfer) (*repository.TransferResult, error)
 	GetWallet(ctx context.Context, walletID uuid.UUID) (*repository.Wallet, error)
-	CreateWallet(ctx context.Context) (*repository.Wallet, error)
+	CreateWallet(ctx context.Context, params repository.NewWallet) (*repository.Wallet, error)
+	// GetOrCreateWallet создаёт кошелёк или возвращает существующий с тем же ID или externalRef.
+	// created равен false, если возвращён существующий кошелёк.
+	GetOrCreateWallet(ctx context.Context, params repository.NewWallet) (wallet *repository.Wallet, created bool, err error)
+	ListWallets(ctx context.Context, query WalletQuery) (*WalletPage, error)
+	ChangeWalletStatus(ctx context.Context, change repository.StatusChange) (*repository.Wallet, error)
+	// SetCreditLimit меняет кредитный лимит кошелька; лимит не может быть меньше использованного кредита
+	SetCreditLimit(ctx context.Context, walletID uuid.UUID, creditLimit int64) (*repository.Wallet, error)
+	// SetBalanceBuckets включает шардированный баланс из buckets корзин; 0 выключает шардирование
+	SetBalanceBuckets(ctx context.Context, walletID uuid.UUID, buckets int) (*repository.Wallet, error)
+	ListTransactions(ctx context.Context, walletID uuid.UUID, query TransactionQuery) (*TransactionPage, error)
+	// GetBalanceAt возвращает баланс кошелька на момент at; at не может быть в будущем
+	GetBalanceAt(ctx context.Context, walletID uuid.UUID, at time.Time) (*repository.HistoricalBalance, error)
+	// StreamStatement передаёт visitor выписку кошелька: баланс на начало периода, операции
+	// с балансом после каждой и баланс на конец периода. Конец периода в будущем заменяется текущим моментом
+	StreamStatement(ctx context.Context, walletID uuid.UUID, query StatementQuery, visitor repository.StatementVisitor) error
+}
+
+// HoldRequest описывает запрос на блокировку средств.
+// Нулевой TTL означает срок действия блокировки по умолчанию.
+type HoldRequest struct {
+	WalletID uuid.UUID
+	Amount   int64
+	Currency string
+	TTL      time.Duration
+}
+
+type HoldService interface {
+	CreateHold(ctx context.Context, req HoldRequest) (*repository.Hold, error)
+	GetHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error)
+	// CaptureHold списывает amount из блокировки; нулевой amount означает всю заблокированную сумму
+	CaptureHold(ctx context.Context, walletID, holdID uuid.UUID, amount int64) (*repository.Hold, error)
+	VoidHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error)
+}
+
+// BatchMode задаёт режим выполнения пакета операций
+type BatchMode string
+
+const (
+	// BatchAtomic - все операции пакета выполняются или отменяются вместе
+	BatchAtomic BatchMode = "ATOMIC"
+	// BatchBestEffort - неуспешные операции пропускаются, остальные выполняются
+	BatchBestEffort BatchMode = "BEST_EFFORT"
+)
+
+type BatchService interface {
+	// ProcessBatch возвращает результаты в порядке ops. В атомарном режиме ошибка операции
+	// возвращается как *repository.BatchItemError с её индексом.
+	ProcessBatch(ctx context.Context, mode BatchMode, ops []repository.BatchOperation) ([]repository.BatchItemResult, error)
+}
+
+type ReconciliationService interface {
+	// Reconcile сверяет балансы всех кошельков с журналом и сохраняет результат запуска.
+	// С autoCorrect расхождения закрываются корректирующими проводками.
+	Reconcile(ctx context.Context, autoCorrect bool) (*repository.ReconciliationRun, error)
+	// LastRun возвращает результат последнего запуска сверки
+	LastRun(ctx context.Context) (*repository.ReconciliationRun, error)
+}
+
+// WebhookDeliveryQuery описывает запрос списка доставок подписки
+type WebhookDeliveryQuery struct {
+	Status *repository.WebhookDeliveryStatus
+	Limit  int
+	Cursor string
+}
+
+// WebhookDeliveryPage представляет страницу списка доставок.
+// NextCursor пустой, если следующей страницы нет.
+type WebhookDeliveryPage struct {
+	Items      []repository.WebhookDelivery
+	NextCursor string
+}
+
+type WebhookService interface {
+	// CreateSubscription создаёт подписку со сгенерированным ключом подписи
+	CreateSubscription(ctx context.Context, params repository.WebhookSubscriptionParams) (*repository.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uuid.UUID) (*repository.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]repository.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id uuid.UUID, params repository.WebhookSubscriptionParams) (*repository.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error)
+	// ReplayDeliveries ставит в очередь заново перечисленные доставки или, без deliveryIDs,
+	// все доставки в статусе DEAD. Возвращает число поставленных в очередь доставок.
+	ReplayDeliveries(ctx context.Context, subscriptionID uuid.UUID, deliveryIDs []uuid.UUID) (int64, error)
+}
+
+// WalletLimit - действующие лимиты вида операций кошелька с их использованием.
+// Remaining - сумма, которую кошелёк может провести прямо сейчас; nil, если лимиты не заданы.
+type WalletLimit struct {
+	repository.WalletLimitStatus
+	Remaining *int64
+}
+
+type LimitService interface {
+	ListDefaultLimits(ctx context.Context) ([]repository.OperationLimit, error)
+	// SetLimit задаёт лимиты по умолчанию (WalletID nil) или лимиты кошелька, заменяющие их целиком
+	SetLimit(ctx context.Context, limit repository.OperationLimit) (*repository.OperationLimit, error)
+	DeleteLimit(ctx context.Context, walletID *uuid.UUID, operation repository.LimitOperation) error
+	GetWalletLimits(ctx context.Context, walletID uuid.UUID) ([]WalletLimit, error)
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+type holdService struct {
+	repo       repository.HoldRepository
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+}
+
+func NewHoldService(repo repository.HoldRepository, defaultTTL, maxTTL time.Duration) HoldService {
+	return &holdService{repo: repo, defaultTTL: defaultTTL, maxTTL: maxTTL}
+}
+
+func (s *holdService) CreateHold(ctx context.Context, req HoldRequest) (*repository.Hold, error) {
+	if req.Amount <= 0 {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if err := normalizeCurrency(&req.Currency); err != nil {
+		return nil, err
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = s.defaultTTL
+	}
+	if ttl < 0 || ttl > s.maxTTL {
+		return nil, apperrors.ErrInvalidHoldTTL
+	}
+
+	return s.repo.CreateHold(ctx, repository.NewHold{
+		WalletID:  req.WalletID,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+}
+
+func (s *holdService) GetHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	return s.repo.GetHold(ctx, walletID, holdID)
+}
+
+func (s *holdService) CaptureHold(ctx context.Context, walletID, holdID uuid.UUID, amount int64) (*repository.Hold, error) {
+	if amount < 0 {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	return s.repo.CaptureHold(ctx, walletID, holdID, amount)
+}
+
+func (s *holdService) VoidHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	return s.repo.VoidHold(ctx, walletID, holdID)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+type limitService struct {
+	repo repository.LimitRepository
+}
+
+func NewLimitService(repo repository.LimitRepository) LimitService {
+	return &limitService{repo: repo}
+}
+
+func (s *limitService) ListDefaultLimits(ctx context.Context) ([]repository.OperationLimit, error) {
+	return s.repo.ListDefaultLimits(ctx)
+}
+
+func (s *limitService) SetLimit(ctx context.Context, limit repository.OperationLimit) (*repository.OperationLimit, error) {
+	if err := validateLimitOperation(limit.Operation); err != nil {
+		return nil, err
+	}
+	for _, value := range []*int64{limit.Limits.MaxAmount, limit.Limits.Daily, limit.Limits.Weekly, limit.Limits.Monthly} {
+		if value != nil && *value <= 0 {
+			return nil, apperrors.ErrInvalidOperationLimits
+		}
+	}
+	return s.repo.SetLimit(ctx, limit)
+}
+
+func (s *limitService) DeleteLimit(ctx context.Context, walletID *uuid.UUID, operation repository.LimitOperation) error {
+	if err := validateLimitOperation(operation); err != nil {
+		return err
+	}
+	return s.repo.DeleteLimit(ctx, walletID, operation)
+}
+
+func (s *limitService) GetWalletLimits(ctx context.Context, walletID uuid.UUID) ([]WalletLimit, error) {
+	statuses, err := s.repo.GetWalletLimits(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]WalletLimit, len(statuses))
+	for i, status := range statuses {
+		limits[i] = WalletLimit{WalletLimitStatus: status}
+		if remaining, _, limited := status.Limits.Allowance(status.Usage); limited {
+			limits[i].Remaining = &remaining
+		}
+	}
+	return limits, nil
+}
+
+// validateLimitOperation проверяет вид операций для лимитов
+func validateLimitOperation(operation repository.LimitOperation) error {
+	switch operation {
+	case repository.LimitDeposit, repository.LimitWithdraw, repository.LimitTransfer:
+		return nil
+	default:
+		return apperrors.ErrInvalidLimitOperation
+	}
+}
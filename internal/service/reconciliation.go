@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+type reconciliationService struct {
+	repo repository.ReconciliationRepository
+}
+
+func NewReconciliationService(repo repository.ReconciliationRepository) ReconciliationService {
+	return &reconciliationService{repo: repo}
+}
+
+func (s *reconciliationService) Reconcile(ctx context.Context, autoCorrect bool) (*repository.ReconciliationRun, error) {
+	run := &repository.ReconciliationRun{
+		ID:          uuid.New(),
+		StartedAt:   time.Now(),
+		AutoCorrect: autoCorrect,
+	}
+
+	checked, drifts, err := s.repo.FindBalanceDrifts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	run.WalletsChecked = checked
+	run.Drifts = make([]repository.BalanceDrift, 0, len(drifts))
+
+	for _, d := range drifts {
+		if autoCorrect {
+			// Корректировка перепроверяет кошелёк под блокировкой: расхождение, найденное
+			// на снимке данных, корректируется на актуальную сумму или не корректируется вовсе.
+			// Ошибка одного кошелька сохраняется в его расхождении и не прерывает сверку остальных
+			corrected, err := s.repo.PostAdjustment(ctx, d.WalletID)
+			switch {
+			case err != nil:
+				d.Error = err.Error()
+			case corrected != nil:
+				d = *corrected
+			}
+		}
+		run.Drifts = append(run.Drifts, d)
+	}
+
+	run.FinishedAt = time.Now()
+	if err := s.repo.SaveReconciliationRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *reconciliationService) LastRun(ctx context.Context) (*repository.ReconciliationRun, error) {
+	return s.repo.GetLastReconciliationRun(ctx)
+}
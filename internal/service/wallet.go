@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
+	"strings"
+	"time"
 
+	"github.com/devopesik/wallet-basic-operations/internal/currency"
 	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
 	"github.com/devopesik/wallet-basic-operations/internal/repository"
 	"github.com/google/uuid"
@@ -16,24 +20,322 @@ func NewWalletService(repo repository.WalletRepository) WalletService {
 	return &walletService{repo: repo}
 }
 
-func (s *walletService) Deposit(ctx context.Context, walletID uuid.UUID, amount int64) error {
-	if amount <= 0 {
-		return apperrors.ErrInvalidAmount
+func (s *walletService) Deposit(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	if op.Amount <= 0 {
+		return nil, apperrors.ErrInvalidAmount
 	}
-	return s.repo.Deposit(ctx, walletID, amount)
+	if err := normalizeCurrency(&op.Currency); err != nil {
+		return nil, err
+	}
+	return s.repo.Deposit(ctx, op)
+}
+
+func (s *walletService) Withdraw(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	if op.Amount <= 0 {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if err := normalizeCurrency(&op.Currency); err != nil {
+		return nil, err
+	}
+	return s.repo.Withdraw(ctx, op)
 }
 
-func (s *walletService) Withdraw(ctx context.Context, walletID uuid.UUID, amount int64) error {
-	if amount <= 0 {
-		return apperrors.ErrInvalidAmount
+func (s *walletService) Transfer(ctx context.Context, t repository.Transfer) (*repository.TransferResult, error) {
+	if t.Amount <= 0 {
+		return nil, apperrors.ErrInvalidAmount
+	}
+	if t.FromWalletID == t.ToWalletID {
+		return nil, apperrors.ErrSameWallet
 	}
-	return s.repo.Withdraw(ctx, walletID, amount)
+	if err := normalizeCurrency(&t.Currency); err != nil {
+		return nil, err
+	}
+	return s.repo.Transfer(ctx, t)
 }
 
 func (s *walletService) GetWallet(ctx context.Context, walletID uuid.UUID) (*repository.Wallet, error) {
 	return s.repo.GetWallet(ctx, walletID)
 }
 
-func (s *walletService) CreateWallet(ctx context.Context) (*repository.Wallet, error) {
-	return s.repo.CreateWallet(ctx)
+func (s *walletService) CreateWallet(ctx context.Context, params repository.NewWallet) (*repository.Wallet, error) {
+	if err := validateNewWallet(&params); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateWallet(ctx, params)
+}
+
+func (s *walletService) GetOrCreateWallet(ctx context.Context, params repository.NewWallet) (*repository.Wallet, bool, error) {
+	if err := validateNewWallet(&params); err != nil {
+		return nil, false, err
+	}
+
+	wallet, err := s.repo.CreateWallet(ctx, params)
+	if err == nil {
+		return wallet, true, nil
+	}
+	if !stderrors.Is(err, apperrors.ErrWalletAlreadyExists) {
+		return nil, false, err
+	}
+
+	// Кошелёк уже создан: возвращаем его, только если он совпадает с запрошенным,
+	// иначе повтор запроса мог бы вернуть чужой кошелёк или кошелёк в другой валюте
+	existing, err := s.findExistingWallet(ctx, params)
+	if err != nil {
+		return nil, false, err
+	}
+	if !matchesNewWallet(existing, params) {
+		return nil, false, apperrors.ErrWalletAlreadyExists
+	}
+	return existing, false, nil
+}
+
+// findExistingWallet находит кошелёк, с которым конфликтует создание params
+func (s *walletService) findExistingWallet(ctx context.Context, params repository.NewWallet) (*repository.Wallet, error) {
+	if params.ID != uuid.Nil {
+		wallet, err := s.repo.GetWallet(ctx, params.ID)
+		if err == nil {
+			return wallet, nil
+		}
+		if !stderrors.Is(err, apperrors.ErrWalletNotFound) {
+			return nil, err
+		}
+	}
+	if params.ExternalRef != "" {
+		wallet, err := s.repo.FindWalletByExternalRef(ctx, params.OwnerID, params.ExternalRef)
+		if err == nil {
+			return wallet, nil
+		}
+		if !stderrors.Is(err, apperrors.ErrWalletNotFound) {
+			return nil, err
+		}
+	}
+	return nil, apperrors.ErrWalletAlreadyExists
+}
+
+func (s *walletService) ListWallets(ctx context.Context, query WalletQuery) (*WalletPage, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return nil, apperrors.ErrInvalidLimit
+	}
+
+	sort := query.Sort
+	switch sort {
+	case "":
+		sort = repository.WalletSortCreatedAt
+	case repository.WalletSortCreatedAt, repository.WalletSortBalance:
+	default:
+		return nil, apperrors.ErrInvalidSort
+	}
+
+	if query.Status != nil {
+		switch *query.Status {
+		case repository.WalletActive, repository.WalletFrozen, repository.WalletClosed:
+		default:
+			return nil, apperrors.ErrInvalidWalletStatus
+		}
+	}
+	if err := normalizeCurrency(&query.Currency); err != nil {
+		return nil, err
+	}
+	if query.MinBalance != nil && query.MaxBalance != nil && *query.MinBalance > *query.MaxBalance {
+		return nil, apperrors.ErrInvalidBalanceRange
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && !query.CreatedFrom.Before(*query.CreatedTo) {
+		return nil, apperrors.ErrInvalidTimeRange
+	}
+
+	filter := repository.WalletFilter{
+		OwnerID:     query.OwnerID,
+		ExternalRef: query.ExternalRef,
+		Status:      query.Status,
+		Currency:    query.Currency,
+		MinBalance:  query.MinBalance,
+		MaxBalance:  query.MaxBalance,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+		Sort:        sort,
+		Descending:  query.Descending,
+		// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+		Limit: limit + 1,
+	}
+	if query.ExternalRef != nil {
+		if *query.ExternalRef == "" || len(*query.ExternalRef) > maxExternalRefLength {
+			return nil, apperrors.ErrInvalidExternalRef
+		}
+		if filter.OwnerID == nil {
+			noOwner := ""
+			filter.OwnerID = &noOwner
+		}
+	}
+	if query.Cursor != "" {
+		after, err := decodeWalletCursor(query.Cursor, sort, query.Descending)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	items, err := s.repo.ListWallets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &WalletPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = encodeWalletCursor(page.Items[limit-1], sort, query.Descending)
+	}
+	return page, nil
+}
+
+// validateNewWallet проверяет параметры создания кошелька и подставляет валюту по умолчанию
+func validateNewWallet(params *repository.NewWallet) error {
+	if params.Currency == "" {
+		params.Currency = DefaultCurrency
+	}
+	if err := normalizeCurrency(&params.Currency); err != nil {
+		return err
+	}
+	if len(params.ExternalRef) > maxExternalRefLength || len(params.OwnerID) > maxExternalRefLength {
+		return apperrors.ErrInvalidExternalRef
+	}
+	return nil
+}
+
+// matchesNewWallet проверяет, что существующий кошелёк создан с теми же параметрами
+func matchesNewWallet(wallet *repository.Wallet, params repository.NewWallet) bool {
+	if params.ID != uuid.Nil && wallet.ID != params.ID {
+		return false
+	}
+	if params.ExternalRef != "" {
+		if wallet.ExternalRef == nil || *wallet.ExternalRef != params.ExternalRef || wallet.OwnerID != params.OwnerID {
+			return false
+		}
+	}
+	return wallet.Currency == params.Currency
+}
+
+func (s *walletService) ChangeWalletStatus(ctx context.Context, change repository.StatusChange) (*repository.Wallet, error) {
+	switch change.Status {
+	case repository.WalletActive, repository.WalletFrozen, repository.WalletClosed:
+	default:
+		return nil, apperrors.ErrInvalidWalletStatus
+	}
+
+	change.Reason = strings.TrimSpace(change.Reason)
+	if change.Reason == "" {
+		return nil, apperrors.ErrStatusReasonRequired
+	}
+
+	// Кошелёк для перевода остатка имеет смысл только при закрытии
+	if change.SweepToWalletID != nil {
+		if change.Status != repository.WalletClosed {
+			return nil, apperrors.ErrInvalidWalletStatus
+		}
+		if *change.SweepToWalletID == change.WalletID {
+			return nil, apperrors.ErrSameWallet
+		}
+	}
+
+	return s.repo.ChangeWalletStatus(ctx, change)
+}
+
+func (s *walletService) SetCreditLimit(ctx context.Context, walletID uuid.UUID, creditLimit int64) (*repository.Wallet, error) {
+	if creditLimit < 0 {
+		return nil, apperrors.ErrInvalidCreditLimit
+	}
+	return s.repo.SetCreditLimit(ctx, walletID, creditLimit)
+}
+
+func (s *walletService) SetBalanceBuckets(ctx context.Context, walletID uuid.UUID, buckets int) (*repository.Wallet, error) {
+	if buckets < 0 || buckets > repository.MaxBalanceBuckets {
+		return nil, apperrors.ErrInvalidBalanceBuckets
+	}
+	return s.repo.SetBalanceBuckets(ctx, walletID, buckets)
+}
+
+// normalizeCurrency проверяет код валюты по реестру ISO 4217 и приводит его к верхнему регистру.
+// Пустой код допустим и означает, что валюта не указана.
+func normalizeCurrency(code *string) error {
+	if *code == "" {
+		return nil
+	}
+	c, ok := currency.Lookup(*code)
+	if !ok {
+		return apperrors.ErrInvalidCurrency
+	}
+	*code = c.Code
+	return nil
+}
+
+func (s *walletService) ListTransactions(ctx context.Context, walletID uuid.UUID, query TransactionQuery) (*TransactionPage, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return nil, apperrors.ErrInvalidLimit
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, apperrors.ErrInvalidTimeRange
+	}
+
+	filter := repository.TransactionFilter{
+		WalletID: walletID,
+		Type:     query.Type,
+		From:     query.From,
+		To:       query.To,
+		// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+		Limit: limit + 1,
+	}
+	if query.Cursor != "" {
+		after, err := decodeTransactionCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	// Пустая история и отсутствующий кошелёк должны различаться для клиента
+	if _, err := s.repo.GetWallet(ctx, walletID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.ListTransactions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &TransactionPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = encodeTransactionCursor(page.Items[limit-1])
+	}
+	return page, nil
+}
+
+func (s *walletService) GetBalanceAt(ctx context.Context, walletID uuid.UUID, at time.Time) (*repository.HistoricalBalance, error) {
+	// Баланс в будущем неизвестен, а ответ для него изменился бы задним числом
+	if at.After(time.Now()) {
+		return nil, apperrors.ErrInvalidBalanceTime
+	}
+	return s.repo.GetBalanceAt(ctx, walletID, at)
+}
+
+func (s *walletService) StreamStatement(ctx context.Context, walletID uuid.UUID, query StatementQuery, visitor repository.StatementVisitor) error {
+	// Выписка за ещё не закончившийся период заканчивается текущим моментом,
+	// фактический конец периода возвращается в Statement.To
+	now := time.Now()
+	to := now
+	if query.To != nil && query.To.Before(now) {
+		to = *query.To
+	}
+	if query.From != nil && !query.From.Before(to) {
+		return apperrors.ErrInvalidTimeRange
+	}
+
+	return s.repo.StreamStatement(ctx, repository.StatementQuery{WalletID: walletID, From: query.From, To: to}, visitor)
 }
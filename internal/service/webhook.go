@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+)
+
+// webhookSecretPrefix - префикс ключа подписи, по которому его легко узнать в конфигурации получателя
+const webhookSecretPrefix = "whsec_"
+
+// maxWebhookURLLength - максимальная длина адреса получателя
+const maxWebhookURLLength = 2048
+
+type webhookService struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) WebhookService {
+	return &webhookService{repo: repo}
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, params repository.WebhookSubscriptionParams) (*repository.WebhookSubscription, error) {
+	if err := validateWebhookParams(&params); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateWebhookSubscription(ctx, params, newWebhookSecret())
+}
+
+func (s *webhookService) GetSubscription(ctx context.Context, id uuid.UUID) (*repository.WebhookSubscription, error) {
+	return s.repo.GetWebhookSubscription(ctx, id)
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]repository.WebhookSubscription, error) {
+	return s.repo.ListWebhookSubscriptions(ctx)
+}
+
+func (s *webhookService) UpdateSubscription(ctx context.Context, id uuid.UUID, params repository.WebhookSubscriptionParams) (*repository.WebhookSubscription, error) {
+	if err := validateWebhookParams(&params); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateWebhookSubscription(ctx, id, params)
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteWebhookSubscription(ctx, id)
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, query WebhookDeliveryQuery) (*WebhookDeliveryPage, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return nil, apperrors.ErrInvalidLimit
+	}
+	if query.Status != nil {
+		switch *query.Status {
+		case repository.WebhookDeliveryPending, repository.WebhookDeliveryDelivered, repository.WebhookDeliveryDead:
+		default:
+			return nil, apperrors.ErrInvalidDeliveryStatus
+		}
+	}
+
+	filter := repository.WebhookDeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Status:         query.Status,
+		// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+		Limit: limit + 1,
+	}
+	if query.Cursor != "" {
+		after, err := decodeWebhookDeliveryCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	items, err := s.repo.ListWebhookDeliveries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &WebhookDeliveryPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = encodeWebhookDeliveryCursor(page.Items[limit-1])
+	}
+	return page, nil
+}
+
+func (s *webhookService) ReplayDeliveries(ctx context.Context, subscriptionID uuid.UUID, deliveryIDs []uuid.UUID) (int64, error) {
+	return s.repo.ReplayWebhookDeliveries(ctx, subscriptionID, deliveryIDs)
+}
+
+// validateWebhookParams проверяет адрес получателя и типы событий подписки.
+// Повторяющиеся типы событий удаляются.
+func validateWebhookParams(params *repository.WebhookSubscriptionParams) error {
+	if len(params.URL) > maxWebhookURLLength {
+		return apperrors.ErrInvalidWebhookURL
+	}
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.ErrInvalidWebhookURL
+	}
+
+	eventTypes := make([]string, 0, len(params.EventTypes))
+	for _, t := range params.EventTypes {
+		switch events.Type(t) {
+		case events.WalletCreated, events.WalletStatusChanged, events.Deposited, events.Withdrawn,
+			events.TransferSent, events.TransferReceived, events.HoldCaptured, events.BalanceAdjusted:
+		default:
+			return apperrors.ErrInvalidEventType
+		}
+		if !slices.Contains(eventTypes, t) {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+	params.EventTypes = eventTypes
+	return nil
+}
+
+// newWebhookSecret генерирует ключ подписи подписки
+func newWebhookSecret() string {
+	buf := make([]byte, 32)
+	// crypto/rand.Read не возвращает ошибок: при сбое источника случайности программа завершается
+	_, _ = rand.Read(buf)
+	return webhookSecretPrefix + hex.EncodeToString(buf)
+}
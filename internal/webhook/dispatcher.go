@@ -0,0 +1,163 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+// leaseMargin - запас времени сверх таймаута запроса, на который доставка закрепляется за процессом
+const leaseMargin = time.Minute
+
+// maxErrorLength - максимальная длина сохраняемого текста ошибки доставки
+const maxErrorLength = 500
+
+// RetryPolicy задаёт повторы неудачных доставок: задержка перед n-й повторной попыткой
+// равна BaseDelay * 2^(n-1), но не больше MaxDelay. После MaxAttempts неудачных попыток
+// доставка переходит в статус DEAD.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Delay возвращает задержку перед следующей попыткой после attempts неудачных попыток
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// Dispatcher отправляет доставки, время попытки которых наступило, подписанными POST-запросами.
+// Доставленной считается доставка, на которую получен ответ 2xx.
+type Dispatcher struct {
+	repo      repository.WebhookRepository
+	client    *http.Client
+	policy    RetryPolicy
+	timeout   time.Duration
+	batchSize int
+}
+
+func NewDispatcher(repo repository.WebhookRepository, policy RetryPolicy, timeout time.Duration, batchSize int) *Dispatcher {
+	return &Dispatcher{
+		repo:      repo,
+		client:    &http.Client{Timeout: timeout},
+		policy:    policy,
+		timeout:   timeout,
+		batchSize: batchSize,
+	}
+}
+
+// DispatchDue отправляет накопившиеся доставки пакетами по batchSize, пока очередь не опустеет.
+// Запросы пакета отправляются параллельно. Возвращает число успешных доставок.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		claimed, err := d.repo.ClaimWebhookDeliveries(ctx, d.batchSize, time.Now().Add(d.timeout+leaseMargin))
+		if err != nil {
+			return total, err
+		}
+
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			firstErr error
+		)
+		for _, dispatch := range claimed {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				delivered, err := d.attempt(ctx, dispatch)
+				mu.Lock()
+				defer mu.Unlock()
+				if delivered {
+					total++
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}()
+		}
+		wg.Wait()
+
+		if firstErr != nil || len(claimed) < d.batchSize {
+			return total, firstErr
+		}
+	}
+}
+
+// attempt выполняет одну попытку доставки и сохраняет её результат.
+// Ошибка возвращается только при сбое сохранения результата.
+func (d *Dispatcher) attempt(ctx context.Context, dispatch repository.WebhookDispatch) (bool, error) {
+	statusCode, sendErr := d.send(ctx, dispatch)
+
+	result := repository.WebhookAttempt{
+		DeliveryID:   dispatch.ID,
+		ClaimedUntil: dispatch.NextAttemptAt,
+		Status:       repository.WebhookDeliveryDelivered,
+		StatusCode:   statusCode,
+	}
+	if sendErr != nil {
+		result.Error = truncate(sendErr.Error(), maxErrorLength)
+		if attempts := dispatch.Attempts + 1; attempts >= d.policy.MaxAttempts {
+			result.Status = repository.WebhookDeliveryDead
+			log.Printf("Доставка %s события %s исчерпала %d попыток: %v", dispatch.ID, dispatch.EventID, attempts, sendErr)
+		} else {
+			result.Status = repository.WebhookDeliveryPending
+			result.NextAttemptAt = time.Now().Add(d.policy.Delay(attempts))
+		}
+	}
+
+	if err := d.repo.RecordWebhookAttempt(ctx, result); err != nil {
+		return false, err
+	}
+	return sendErr == nil, nil
+}
+
+// send отправляет подписанный запрос и возвращает код ответа (0, если ответа нет)
+func (d *Dispatcher) send(ctx context.Context, dispatch repository.WebhookDispatch) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(dispatch.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryID, dispatch.ID.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(dispatch.Secret, timestamp, dispatch.Payload))
+	req.Header.Set("X-Event-Id", dispatch.EventID.String())
+	req.Header.Set("X-Event-Type", dispatch.EventType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("получатель ответил статусом %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// truncate обрезает строку до n байт, не разрывая символы UTF-8
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+)
+
+// Publisher принимает события из outbox и создаёт по доставке для каждой подходящей подписки.
+// Сами запросы подписчикам отправляет Dispatcher, поэтому медленный подписчик не задерживает outbox.
+type Publisher struct {
+	repo repository.WebhookRepository
+}
+
+func NewPublisher(repo repository.WebhookRepository) *Publisher {
+	return &Publisher{repo: repo}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event events.Event) error {
+	// Тело запроса фиксируется при создании доставки и не меняется между попытками
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("сериализация события %s: %w", event.ID, err)
+	}
+	_, err = p.repo.EnqueueWebhookDeliveries(ctx, repository.WebhookEvent{
+		ID:       event.ID,
+		WalletID: event.WalletID,
+		Type:     string(event.Type),
+		Payload:  body,
+	})
+	return err
+}
@@ -0,0 +1,61 @@
+// Package webhook доставляет события подписчикам: создаёт доставки для подходящих подписок,
+// отправляет их подписанными POST-запросами и повторяет неудачные попытки с экспоненциальной задержкой.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки запроса доставки
+const (
+	// HeaderDeliveryID - идентификатор доставки, общий для всех её попыток
+	HeaderDeliveryID = "X-Webhook-Id"
+	// HeaderTimestamp - время отправки попытки, Unix-время в секундах
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderSignature - подпись "sha256=<hex>" строки "<timestamp>.<тело запроса>"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// signaturePrefix - префикс алгоритма в заголовке подписи
+const signaturePrefix = "sha256="
+
+var (
+	ErrInvalidSignature = errors.New("подпись запроса не совпадает")
+	ErrTimestampExpired = errors.New("время запроса вне допустимого окна")
+)
+
+// Sign возвращает значение заголовка подписи для тела body, отправленного в момент timestamp.
+// Время входит в подпись, чтобы перехваченный запрос нельзя было повторить позже.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись запроса на стороне получателя по значениям заголовков
+// HeaderTimestamp и HeaderSignature. Запросы старше tolerance отклоняются.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	timestamp := time.Unix(unix, 0)
+	if diff := now.Sub(timestamp); diff > tolerance || diff < -tolerance {
+		return ErrTimestampExpired
+	}
+	if !strings.HasPrefix(signatureHeader, signaturePrefix) {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signatureHeader)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job - периодическая фоновая задача
+type Job func(ctx context.Context) error
+
+// RunPeriodic выполняет job с заданным интервалом, пока не будет отменён ctx.
+// Ошибки задачи логируются и не прерывают последующие запуски.
+func RunPeriodic(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("Фоновая задача %q запущена с интервалом %v", name, interval)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Фоновая задача %q остановлена", name)
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("ошибка фоновой задачи %q: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE wallet_transactions (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    type TEXT NOT NULL CHECK (type IN ('DEPOSIT', 'WITHDRAW')),
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    balance_after BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Индекс под курсорную пагинацию истории кошелька (от новых к старым)
+CREATE INDEX idx_wallet_transactions_wallet_created
+    ON wallet_transactions (wallet_id, created_at DESC, id DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallet_transactions;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE idempotency_keys (
+    key TEXT PRIMARY KEY,
+    request_hash TEXT NOT NULL,
+    -- Результат первого выполнения; NULL, пока операция не завершена
+    response JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Индекс для удаления устаревших ключей
+CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE wallet_transactions DROP CONSTRAINT wallet_transactions_type_check;
+ALTER TABLE wallet_transactions ADD CONSTRAINT wallet_transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER_IN', 'TRANSFER_OUT'));
+
+-- Второй кошелёк перевода: получатель для TRANSFER_OUT, отправитель для TRANSFER_IN
+ALTER TABLE wallet_transactions ADD COLUMN counterparty_wallet_id UUID REFERENCES wallets (id);
+
+-- +goose Down
+-- История операций не удаляется при откате: переводы в ней не дают откатить миграцию
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM wallet_transactions WHERE type IN ('TRANSFER_IN', 'TRANSFER_OUT')) THEN
+        RAISE EXCEPTION 'в истории операций есть переводы, откат миграции удалил бы их';
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+ALTER TABLE wallet_transactions DROP COLUMN counterparty_wallet_id;
+ALTER TABLE wallet_transactions DROP CONSTRAINT wallet_transactions_type_check;
+ALTER TABLE wallet_transactions ADD CONSTRAINT wallet_transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW'));
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Существующие кошельки велись в рублях
+ALTER TABLE wallets ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE wallets ALTER COLUMN currency DROP DEFAULT;
+
+-- Валюта задаётся при создании кошелька и не меняется
+-- +goose StatementBegin
+CREATE FUNCTION wallets_forbid_currency_change() RETURNS trigger AS $$
+BEGIN
+    IF NEW.currency <> OLD.currency THEN
+        RAISE EXCEPTION 'валюту кошелька % нельзя изменить', OLD.id;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER wallets_currency_immutable
+    BEFORE UPDATE OF currency ON wallets
+    FOR EACH ROW EXECUTE FUNCTION wallets_forbid_currency_change();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS wallets_currency_immutable ON wallets;
+DROP FUNCTION IF EXISTS wallets_forbid_currency_change();
+ALTER TABLE wallets DROP COLUMN currency;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- Сумма активных блокировок: доступный баланс = balance - held
+ALTER TABLE wallets ADD COLUMN held BIGINT NOT NULL DEFAULT 0 CHECK (held >= 0);
+ALTER TABLE wallets ADD CONSTRAINT wallets_available_check CHECK (balance >= held);
+
+CREATE TABLE wallet_holds (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    captured_amount BIGINT NOT NULL DEFAULT 0 CHECK (captured_amount >= 0 AND captured_amount <= amount),
+    status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'CAPTURED', 'VOIDED', 'EXPIRED')),
+    capture_transaction_id UUID REFERENCES wallet_transactions (id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_wallet_holds_wallet ON wallet_holds (wallet_id, created_at DESC);
+-- Индекс для фоновой задачи истечения блокировок
+CREATE INDEX idx_wallet_holds_active_expires ON wallet_holds (expires_at) WHERE status = 'ACTIVE';
+
+ALTER TABLE wallet_transactions DROP CONSTRAINT wallet_transactions_type_check;
+ALTER TABLE wallet_transactions ADD CONSTRAINT wallet_transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER_IN', 'TRANSFER_OUT', 'HOLD_CAPTURE'));
+
+-- +goose Down
+-- История операций не удаляется при откате: списания блокировок в ней не дают откатить миграцию
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM wallet_transactions WHERE type = 'HOLD_CAPTURE') THEN
+        RAISE EXCEPTION 'в истории операций есть списания блокировок, откат миграции удалил бы их';
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+ALTER TABLE wallet_transactions DROP CONSTRAINT wallet_transactions_type_check;
+ALTER TABLE wallet_transactions ADD CONSTRAINT wallet_transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER_IN', 'TRANSFER_OUT'));
+DROP TABLE IF EXISTS wallet_holds;
+ALTER TABLE wallets DROP CONSTRAINT wallets_available_check;
+ALTER TABLE wallets DROP COLUMN held;
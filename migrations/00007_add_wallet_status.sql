@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE'
+    CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED'));
+
+-- Журнал смены статусов: причина обязательна для каждого изменения
+CREATE TABLE wallet_status_changes (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    sweep_wallet_id UUID REFERENCES wallets (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_wallet_status_changes_wallet ON wallet_status_changes (wallet_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS wallet_status_changes;
+ALTER TABLE wallets DROP COLUMN status;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Владелец и внешний идентификатор кошелька во внешней системе (например, id пользователя)
+ALTER TABLE wallets ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE wallets ADD COLUMN external_ref TEXT;
+
+CREATE UNIQUE INDEX idx_wallets_owner_external_ref ON wallets (owner_id, external_ref)
+    WHERE external_ref IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallets_owner_external_ref;
+ALTER TABLE wallets DROP COLUMN external_ref;
+ALTER TABLE wallets DROP COLUMN owner_id;
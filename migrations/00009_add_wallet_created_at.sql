@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE wallets ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Для существующих кошельков время создания восстанавливаем по первой операции
+UPDATE wallets w SET created_at = t.first_at
+FROM (SELECT wallet_id, min(created_at) AS first_at FROM wallet_transactions GROUP BY wallet_id) t
+WHERE t.wallet_id = w.id AND t.first_at < w.created_at;
+
+-- Индексы для keyset-пагинации списка кошельков по времени создания и по балансу
+CREATE INDEX idx_wallets_created_at ON wallets (created_at, id);
+CREATE INDEX idx_wallets_balance ON wallets (balance, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallets_balance;
+DROP INDEX IF EXISTS idx_wallets_created_at;
+ALTER TABLE wallets DROP COLUMN created_at;
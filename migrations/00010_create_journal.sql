@@ -0,0 +1,121 @@
+-- +goose Up
+-- Системные счета - вторая сторона операций, которые вводят деньги в систему или выводят их из неё
+CREATE TABLE system_accounts (
+    code TEXT PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+INSERT INTO system_accounts (code, description) VALUES
+    ('EXTERNAL_FUNDING', 'Внешние поступления: источник пополнений кошельков'),
+    ('PAYOUTS', 'Выплаты: получатель списаний с кошельков'),
+    ('FEES', 'Комиссии сервиса');
+
+-- Проводка журнала двойной записи: набор строк, сумма которых по каждой валюте равна нулю
+CREATE TABLE journal_entries (
+    id UUID PRIMARY KEY,
+    type TEXT NOT NULL CHECK (type IN ('OPENING_BALANCE', 'DEPOSIT', 'WITHDRAW', 'TRANSFER', 'HOLD_CAPTURE')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Строка проводки относится либо к кошельку, либо к системному счёту.
+-- Положительная сумма увеличивает остаток счёта, отрицательная уменьшает.
+CREATE TABLE journal_lines (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    entry_id UUID NOT NULL REFERENCES journal_entries (id),
+    wallet_id UUID REFERENCES wallets (id),
+    system_account TEXT REFERENCES system_accounts (code),
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    CONSTRAINT journal_lines_account_check CHECK ((wallet_id IS NULL) <> (system_account IS NULL))
+);
+
+CREATE INDEX idx_journal_lines_entry ON journal_lines (entry_id);
+CREATE INDEX idx_journal_lines_wallet ON journal_lines (wallet_id) WHERE wallet_id IS NOT NULL;
+CREATE INDEX idx_journal_lines_system_account ON journal_lines (system_account, currency) WHERE system_account IS NOT NULL;
+
+-- Баланс проводки проверяется при фиксации транзакции, когда все её строки уже записаны
+-- +goose StatementBegin
+CREATE FUNCTION journal_check_entry_balanced() RETURNS trigger AS $$
+DECLARE
+    v_entry_id UUID;
+    v_lines INT;
+BEGIN
+    IF TG_TABLE_NAME = 'journal_entries' THEN
+        v_entry_id := NEW.id;
+    ELSE
+        v_entry_id := NEW.entry_id;
+    END IF;
+
+    SELECT count(*) INTO v_lines FROM journal_lines WHERE entry_id = v_entry_id;
+    IF v_lines < 2 THEN
+        RAISE EXCEPTION 'проводка % должна содержать не меньше двух строк', v_entry_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM journal_lines
+        WHERE entry_id = v_entry_id
+        GROUP BY currency
+        HAVING sum(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'проводка % не сбалансирована', v_entry_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE CONSTRAINT TRIGGER journal_entries_balanced
+    AFTER INSERT ON journal_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION journal_check_entry_balanced();
+
+CREATE CONSTRAINT TRIGGER journal_lines_balanced
+    AFTER INSERT ON journal_lines
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION journal_check_entry_balanced();
+
+-- Журнал только дополняется: ошибки исправляются новыми проводками
+-- +goose StatementBegin
+CREATE FUNCTION journal_forbid_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'записи журнала (%) нельзя изменять или удалять', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER journal_entries_immutable
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION journal_forbid_changes();
+
+CREATE TRIGGER journal_lines_immutable
+    BEFORE UPDATE OR DELETE ON journal_lines
+    FOR EACH ROW EXECUTE FUNCTION journal_forbid_changes();
+
+-- Запись истории кошелька ссылается на проводку, которой она создана
+ALTER TABLE wallet_transactions ADD COLUMN journal_entry_id UUID REFERENCES journal_entries (id);
+CREATE INDEX idx_wallet_transactions_journal_entry ON wallet_transactions (journal_entry_id);
+
+-- Текущие балансы переносятся в журнал входящими остатками за счёт внешних поступлений
+WITH opening AS (
+    SELECT gen_random_uuid() AS entry_id, id AS wallet_id, currency, balance
+    FROM wallets
+    WHERE balance <> 0
+), entries AS (
+    INSERT INTO journal_entries (id, type)
+    SELECT entry_id, 'OPENING_BALANCE' FROM opening
+)
+INSERT INTO journal_lines (entry_id, wallet_id, system_account, currency, amount)
+SELECT entry_id, wallet_id, NULL, currency, balance FROM opening
+UNION ALL
+SELECT entry_id, NULL, 'EXTERNAL_FUNDING', currency, -balance FROM opening;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_transactions_journal_entry;
+ALTER TABLE wallet_transactions DROP COLUMN journal_entry_id;
+DROP TABLE IF EXISTS journal_lines;
+DROP TABLE IF EXISTS journal_entries;
+DROP FUNCTION IF EXISTS journal_forbid_changes();
+DROP FUNCTION IF EXISTS journal_check_entry_balanced();
+DROP TABLE IF EXISTS system_accounts;
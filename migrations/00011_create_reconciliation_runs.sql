@@ -0,0 +1,28 @@
+-- +goose Up
+-- Счёт расхождений: вторая сторона корректировок, которыми сверка выравнивает журнал с балансами
+INSERT INTO system_accounts (code, description) VALUES
+    ('RECONCILIATION', 'Расхождения, выявленные сверкой балансов, до их разбора');
+
+ALTER TABLE journal_entries DROP CONSTRAINT journal_entries_type_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_type_check
+    CHECK (type IN ('OPENING_BALANCE', 'DEPOSIT', 'WITHDRAW', 'TRANSFER', 'HOLD_CAPTURE', 'ADJUSTMENT'));
+
+-- Результаты запусков сверки балансов с журналом; drifts - расхождения по кошелькам в JSON
+CREATE TABLE reconciliation_runs (
+    id UUID PRIMARY KEY,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    auto_correct BOOLEAN NOT NULL,
+    wallets_checked BIGINT NOT NULL,
+    drifts JSONB NOT NULL
+);
+
+CREATE INDEX idx_reconciliation_runs_finished ON reconciliation_runs (finished_at DESC);
+
+-- +goose Down
+-- Журнал только дополняется: после первой корректировки откат этой миграции невозможен
+DROP TABLE IF EXISTS reconciliation_runs;
+ALTER TABLE journal_entries DROP CONSTRAINT journal_entries_type_check;
+ALTER TABLE journal_entries ADD CONSTRAINT journal_entries_type_check
+    CHECK (type IN ('OPENING_BALANCE', 'DEPOSIT', 'WITHDRAW', 'TRANSFER', 'HOLD_CAPTURE'));
+DELETE FROM system_accounts WHERE code = 'RECONCILIATION';
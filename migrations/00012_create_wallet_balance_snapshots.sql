@@ -0,0 +1,26 @@
+-- +goose Up
+-- Снимки баланса: баланс кошелька с учётом всех записей истории с created_at <= taken_at.
+-- Баланс на момент T = последний снимок не позже T + сумма записей истории после снимка до T.
+CREATE TABLE wallet_balance_snapshots (
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    taken_at TIMESTAMPTZ NOT NULL,
+    balance BIGINT NOT NULL,
+    PRIMARY KEY (wallet_id, taken_at)
+);
+
+-- Индекс для поиска момента последнего снимка фоновой задачей
+CREATE INDEX idx_wallet_balance_snapshots_taken_at ON wallet_balance_snapshots (taken_at);
+-- Индекс для выборки записей истории, появившихся после последнего снимка
+CREATE INDEX idx_wallet_transactions_created ON wallet_transactions (created_at);
+
+-- Балансы, накопленные до появления истории операций, фиксируются начальными снимками
+INSERT INTO wallet_balance_snapshots (wallet_id, taken_at, balance)
+SELECT w.id, '-infinity', w.balance - COALESCE(sum(t.amount), 0)
+FROM wallets w
+LEFT JOIN wallet_transactions t ON t.wallet_id = w.id
+GROUP BY w.id, w.balance
+HAVING w.balance <> COALESCE(sum(t.amount), 0);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_transactions_created;
+DROP TABLE IF EXISTS wallet_balance_snapshots;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Outbox доменных событий: событие записывается в той же транзакции, что и изменение кошелька,
+-- и доставляется фоновой задачей в порядке sequence
+CREATE TABLE outbox_events (
+    sequence BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+
+-- Индекс для выборки неопубликованных событий в порядке записи
+CREATE INDEX idx_outbox_events_pending ON outbox_events (sequence) WHERE published_at IS NULL;
+-- Индекс для очистки доставленных событий
+CREATE INDEX idx_outbox_events_published ON outbox_events (published_at) WHERE published_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox_events;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Лимиты операций: строка без wallet_id - глобальные лимиты по умолчанию, строка с wallet_id
+-- заменяет их для кошелька целиком. NULL в колонке лимита означает отсутствие ограничения.
+-- Суточный, недельный и месячный лимиты - суммы операций за скользящие 24 часа, 7 и 30 дней.
+CREATE TABLE operation_limits (
+    wallet_id UUID REFERENCES wallets (id),
+    operation TEXT NOT NULL CHECK (operation IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER')),
+    max_amount BIGINT CHECK (max_amount > 0),
+    daily_limit BIGINT CHECK (daily_limit > 0),
+    weekly_limit BIGINT CHECK (weekly_limit > 0),
+    monthly_limit BIGINT CHECK (monthly_limit > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE NULLS NOT DISTINCT (wallet_id, operation)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS operation_limits;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Кредитный лимит: баланс за вычетом блокировок может опускаться до -credit_limit.
+-- Ограничение заменяет фиксированные balance >= 0 и balance >= held.
+ALTER TABLE wallets ADD COLUMN credit_limit BIGINT NOT NULL DEFAULT 0 CHECK (credit_limit >= 0);
+ALTER TABLE wallets DROP CONSTRAINT wallets_balance_check;
+ALTER TABLE wallets DROP CONSTRAINT wallets_available_check;
+ALTER TABLE wallets ADD CONSTRAINT wallets_available_check CHECK (balance - held >= -credit_limit);
+
+-- +goose Down
+-- Откат невозможен, пока у кошельков есть использованный кредит
+ALTER TABLE wallets DROP CONSTRAINT wallets_available_check;
+ALTER TABLE wallets ADD CONSTRAINT wallets_available_check CHECK (balance >= held);
+ALTER TABLE wallets ADD CONSTRAINT wallets_balance_check CHECK (balance >= 0);
+ALTER TABLE wallets DROP COLUMN credit_limit;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Шардированный баланс: у кошелька с balance_buckets > 0 пополнения и списания распределяются
+-- по корзинам wallet_balance_buckets, чтобы параллельные операции не конкурировали за строку wallets.
+-- Баланс кошелька - wallets.balance плюс сумма его корзин. Операции, блокирующие строку кошелька,
+-- сначала переносят корзины в wallets.balance, поэтому ограничение wallets_available_check
+-- по-прежнему действует, а корзины не бывают отрицательными.
+ALTER TABLE wallets ADD COLUMN balance_buckets INT NOT NULL DEFAULT 0
+    CHECK (balance_buckets >= 0 AND balance_buckets <= 64);
+
+CREATE TABLE wallet_balance_buckets (
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    bucket INT NOT NULL,
+    balance BIGINT NOT NULL CHECK (balance >= 0),
+    PRIMARY KEY (wallet_id, bucket)
+);
+
+-- Кошельки с балансом, включающим корзины; колонки совпадают с wallets
+CREATE VIEW wallets_with_buckets AS
+SELECT w.id,
+    w.balance + COALESCE((SELECT sum(b.balance) FROM wallet_balance_buckets b WHERE b.wallet_id = w.id), 0)::BIGINT AS balance,
+    w.held, w.credit_limit, w.currency, w.status, w.owner_id, w.external_ref, w.created_at, w.balance_buckets
+FROM wallets w;
+
+-- +goose Down
+-- Остатки корзин переносятся в баланс кошелька
+UPDATE wallets w SET balance = w.balance + b.total
+FROM (SELECT wallet_id, sum(balance) AS total FROM wallet_balance_buckets GROUP BY wallet_id) b
+WHERE w.id = b.wallet_id;
+DROP VIEW IF EXISTS wallets_with_buckets;
+DROP TABLE IF EXISTS wallet_balance_buckets;
+ALTER TABLE wallets DROP COLUMN balance_buckets;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- Версия кошелька для оптимистичных блокировок: растёт при каждом изменении баланса, блокировок,
+-- кредитного лимита или статуса. Версия шардированного кошелька - версия строки плюс сумма версий
+-- его корзин; перенос корзин в строку кошелька добавляет к версии строки версии удалённых корзин,
+-- поэтому полная версия при переносе не меняется.
+ALTER TABLE wallets ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE wallet_balance_buckets ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- Версия увеличивается, если запрос не задал её сам
+-- +goose StatementBegin
+CREATE FUNCTION bump_version() RETURNS trigger AS $$
+BEGIN
+    IF NEW.version = OLD.version THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER wallets_bump_version
+    BEFORE UPDATE ON wallets
+    FOR EACH ROW
+    WHEN ((OLD.balance, OLD.held, OLD.credit_limit, OLD.status) IS DISTINCT FROM (NEW.balance, NEW.held, NEW.credit_limit, NEW.status))
+    EXECUTE FUNCTION bump_version();
+
+CREATE TRIGGER wallet_balance_buckets_bump_version
+    BEFORE UPDATE ON wallet_balance_buckets
+    FOR EACH ROW
+    WHEN (OLD.balance IS DISTINCT FROM NEW.balance)
+    EXECUTE FUNCTION bump_version();
+
+CREATE OR REPLACE VIEW wallets_with_buckets AS
+SELECT w.id,
+    w.balance + COALESCE((SELECT sum(b.balance) FROM wallet_balance_buckets b WHERE b.wallet_id = w.id), 0)::BIGINT AS balance,
+    w.held, w.credit_limit, w.currency, w.status, w.owner_id, w.external_ref, w.created_at, w.balance_buckets,
+    w.version + COALESCE((SELECT sum(b.version) FROM wallet_balance_buckets b WHERE b.wallet_id = w.id), 0)::BIGINT AS version
+FROM wallets w;
+
+-- +goose Down
+DROP VIEW IF EXISTS wallets_with_buckets;
+CREATE VIEW wallets_with_buckets AS
+SELECT w.id,
+    w.balance + COALESCE((SELECT sum(b.balance) FROM wallet_balance_buckets b WHERE b.wallet_id = w.id), 0)::BIGINT AS balance,
+    w.held, w.credit_limit, w.currency, w.status, w.owner_id, w.external_ref, w.created_at, w.balance_buckets
+FROM wallets w;
+DROP TRIGGER IF EXISTS wallet_balance_buckets_bump_version ON wallet_balance_buckets;
+DROP TRIGGER IF EXISTS wallets_bump_version ON wallets;
+DROP FUNCTION IF EXISTS bump_version();
+ALTER TABLE wallet_balance_buckets DROP COLUMN version;
+ALTER TABLE wallets DROP COLUMN version;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Корректировка баланса сверкой по журналу отражается в истории кошелька записью ADJUSTMENT
+ALTER TABLE wallet_transactions DROP CONSTRAINT wallet_transactions_type_check;
+ALTER TABLE wallet_transactions ADD CONSTRAINT wallet_transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER_IN', 'TRANSFER_OUT', 'HOLD_CAPTURE', 'ADJUSTMENT'));
+
+-- +goose Down
+-- История операций не удаляется при откате: корректировки в ней не дают откатить миграцию
+-- +goose StatementBegin
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM wallet_transactions WHERE type = 'ADJUSTMENT') THEN
+        RAISE EXCEPTION 'в истории операций есть корректировки, откат миграции удалил бы их';
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+ALTER TABLE wallet_transactions DROP CONSTRAINT wallet_transactions_type_check;
+ALTER TABLE wallet_transactions ADD CONSTRAINT wallet_transactions_type_check
+    CHECK (type IN ('DEPOSIT', 'WITHDRAW', 'TRANSFER_IN', 'TRANSFER_OUT', 'HOLD_CAPTURE'));
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+)
+
+func TestWalletBalanceAtIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+	repo := postgres.NewWalletRepository(testPool(t), postgres.NewRetryPolicy(testConfig()))
+	ctx := context.Background()
+
+	// deposit выполняет пополнение и возвращает время операции по часам базы
+	deposit := func(walletID string, amount int64) time.Time {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": "DEPOSIT", "amount": amount})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при пополнении: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var op struct {
+			CreatedAt time.Time `json:"createdAt"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&op); err != nil {
+			t.Fatalf("ошибка декодирования ответа пополнения: %v", err)
+		}
+		return op.CreatedAt
+	}
+	balanceAt := func(walletID string, at time.Time) (int, int64) {
+		t.Helper()
+		resp, err := http.Get(baseURL + "/api/v1/wallets/" + walletID + "/balance?at=" + url.QueryEscape(at.Format(time.RFC3339Nano)))
+		if err != nil {
+			t.Fatalf("ошибка получения баланса на момент времени: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var balance struct {
+			Balance int64 `json:"balance"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&balance)
+		return resp.StatusCode, balance.Balance
+	}
+
+	walletID := createFundedWallet(t, baseURL, 0)
+	first := deposit(walletID, 100)
+	if _, err := repo.SnapshotBalances(ctx, first); err != nil {
+		t.Fatalf("ошибка снятия снимков: %v", err)
+	}
+	second := deposit(walletID, 50)
+
+	// 1. Баланс по снимку и по снимку с историей после него
+	if status, balance := balanceAt(walletID, first); status != http.StatusOK || balance != 100 {
+		t.Errorf("на момент первого пополнения ожидался баланс 100, получены %d и %d", status, balance)
+	}
+	if status, balance := balanceAt(walletID, second); status != http.StatusOK || balance != 150 {
+		t.Errorf("на момент второго пополнения ожидался баланс 150, получены %d и %d", status, balance)
+	}
+
+	// 2. Новый снимок не меняет результат
+	if _, err := repo.SnapshotBalances(ctx, second); err != nil {
+		t.Fatalf("ошибка снятия снимков: %v", err)
+	}
+	if status, balance := balanceAt(walletID, second); status != http.StatusOK || balance != 150 {
+		t.Errorf("после снимка ожидался баланс 150, получены %d и %d", status, balance)
+	}
+	if status, balance := balanceAt(walletID, first.Add(-time.Nanosecond)); status != http.StatusOK || balance != 0 {
+		t.Errorf("до первого пополнения ожидался баланс 0, получены %d и %d", status, balance)
+	}
+
+	// 3. До создания кошелька баланса нет, будущее недоступно
+	if status, _ := balanceAt(walletID, first.Add(-time.Hour)); status != http.StatusNotFound {
+		t.Errorf("до создания кошелька ожидался статус 404, получен %d", status)
+	}
+	if status, _ := balanceAt(walletID, time.Now().Add(time.Hour)); status != http.StatusBadRequest {
+		t.Errorf("для момента в будущем ожидался статус 400, получен %d", status)
+	}
+}
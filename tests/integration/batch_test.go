@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestWalletBatchIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	first := createFundedWallet(t, baseURL, 100)
+	second := createFundedWallet(t, baseURL, 0)
+
+	type batchResponse struct {
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+		Results   []struct {
+			Index     int    `json:"index"`
+			Status    string `json:"status"`
+			Operation struct {
+				Balance int64 `json:"balance"`
+			} `json:"operation"`
+		} `json:"results"`
+	}
+	sendBatch := func(mode string, items []map[string]any) (int, batchResponse) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"mode": mode, "items": items})
+		resp, err := http.Post(baseURL+"/api/v1/wallet/batch", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при отправке пакета: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var batch batchResponse
+		if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+			t.Fatalf("ошибка декодирования ответа пакета: %v", err)
+		}
+		return resp.StatusCode, batch
+	}
+	op := func(walletID, opType string, amount int64) map[string]any {
+		return map[string]any{"walletId": walletID, "operationType": opType, "amount": amount}
+	}
+
+	// 1. Атомарный пакет отменяется целиком из-за одной операции
+	status, batch := sendBatch("ATOMIC", []map[string]any{
+		op(second, "DEPOSIT", 500),
+		op(first, "WITHDRAW", 1000),
+	})
+	if status != http.StatusConflict || batch.Failed != 1 || len(batch.Results) != 1 || batch.Results[0].Index != 1 {
+		t.Errorf("ожидалась отмена пакета из-за операции 1 со статусом 409, получены %d и %+v", status, batch)
+	}
+	if balance := getBalance(t, baseURL, second); balance != 0 {
+		t.Errorf("пополнение из отменённого пакета не должно применяться, баланс %d", balance)
+	}
+
+	// 2. Операции применяются по порядку: списание использует пополнение из того же пакета
+	status, batch = sendBatch("ATOMIC", []map[string]any{
+		op(second, "DEPOSIT", 500),
+		op(second, "WITHDRAW", 200),
+		op(first, "DEPOSIT", 50),
+	})
+	if status != http.StatusOK || batch.Succeeded != 3 {
+		t.Fatalf("ожидалось успешное выполнение 3 операций, получены %d и %+v", status, batch)
+	}
+	if batch.Results[1].Operation.Balance != 300 {
+		t.Errorf("ожидался баланс 300 после второй операции, получен %d", batch.Results[1].Operation.Balance)
+	}
+
+	// 3. Best effort пропускает неуспешные операции
+	status, batch = sendBatch("BEST_EFFORT", []map[string]any{
+		op(first, "WITHDRAW", 10000),
+		op(first, "WITHDRAW", 50),
+		op("00000000-0000-0000-0000-000000000001", "DEPOSIT", 1),
+	})
+	if status != http.StatusOK || batch.Succeeded != 1 || batch.Failed != 2 {
+		t.Fatalf("ожидались 1 успешная и 2 неуспешные операции, получены %d и %+v", status, batch)
+	}
+	if batch.Results[0].Status != "FAILED" || batch.Results[1].Status != "SUCCEEDED" || batch.Results[2].Status != "FAILED" {
+		t.Errorf("неожиданные статусы операций: %+v", batch.Results)
+	}
+	if balance := getBalance(t, baseURL, first); balance != 100 {
+		t.Errorf("ожидался баланс 100, получен %d", balance)
+	}
+}
@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+)
+
+func TestWalletBalanceBucketsIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+	pool := testPool(t)
+
+	walletID := createFundedWallet(t, baseURL, 1000)
+
+	setBuckets := func(buckets int) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"buckets": buckets})
+		req, _ := http.NewRequest(http.MethodPut, baseURL+"/api/v1/admin/wallets/"+walletID+"/balance-buckets", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка при смене числа корзин: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	operation := func(opType string, amount int64) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": opType, "amount": amount})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Errorf("ошибка при операции: %v", err)
+			return 0
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	bucketsTotal := func() (rows int, total int64) {
+		t.Helper()
+		err := pool.QueryRow(context.Background(),
+			"SELECT count(*), COALESCE(sum(balance), 0) FROM wallet_balance_buckets WHERE wallet_id = $1", walletID).
+			Scan(&rows, &total)
+		if err != nil {
+			t.Fatalf("ошибка чтения корзин: %v", err)
+		}
+		return rows, total
+	}
+
+	// 1. Число корзин проверяется
+	if status := setBuckets(65); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для 65 корзин, получен %d", status)
+	}
+	if status := setBuckets(8); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при включении корзин, получен %d", status)
+	}
+
+	// 2. Параллельные пополнения распределяются по корзинам, баланс в API - полный
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status := operation("DEPOSIT", 10); status != http.StatusOK {
+				t.Errorf("ожидался статус 200 при пополнении, получен %d", status)
+			}
+		}()
+	}
+	wg.Wait()
+	if rows, total := bucketsTotal(); rows == 0 || total != 500 {
+		t.Errorf("ожидалось 500 в корзинах, получено %d в %d корзинах", total, rows)
+	}
+	if balance := getBalance(t, baseURL, walletID); balance != 1500 {
+		t.Errorf("ожидался баланс 1500, получен %d", balance)
+	}
+
+	// 3. Списание больше любой корзины переносит корзины в баланс кошелька
+	if status := operation("WITHDRAW", 1200); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при списании, получен %d", status)
+	}
+	if rows, _ := bucketsTotal(); rows != 0 {
+		t.Errorf("ожидалось, что корзины перенесены в баланс, осталось %d", rows)
+	}
+	if status := operation("WITHDRAW", 301); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при нехватке средств, получен %d", status)
+	}
+
+	// 4. Небольшое списание идёт из корзины
+	if status := operation("DEPOSIT", 100); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при пополнении, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 40); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при списании из корзины, получен %d", status)
+	}
+	if _, total := bucketsTotal(); total != 60 {
+		t.Errorf("ожидалось 60 в корзинах, получено %d", total)
+	}
+	if balance := getBalance(t, baseURL, walletID); balance != 360 {
+		t.Errorf("ожидался баланс 360, получен %d", balance)
+	}
+
+	// 5. Сверка учитывает корзины, выключение шардирования переносит их в баланс
+	reconciliation := service.NewReconciliationService(postgres.NewReconciliationRepository(pool, postgres.NewRetryPolicy(testConfig())))
+	run, err := reconciliation.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatalf("ошибка сверки: %v", err)
+	}
+	for _, d := range run.Drifts {
+		if d.WalletID.String() == walletID {
+			t.Errorf("сверка нашла расхождение шардированного кошелька: %+v", d)
+		}
+	}
+	if status := setBuckets(0); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при выключении корзин, получен %d", status)
+	}
+	if rows, _ := bucketsTotal(); rows != 0 {
+		t.Errorf("ожидалось, что корзины перенесены в баланс, осталось %d", rows)
+	}
+	if balance := getBalance(t, baseURL, walletID); balance != 360 {
+		t.Errorf("ожидался баланс 360, получен %d", balance)
+	}
+}
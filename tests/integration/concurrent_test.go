@@ -49,8 +49,8 @@ func TestWalletConcurrentLoad(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ошибка при начальном депозите: %v", err)
 	}
-	if resp.StatusCode != http.StatusNoContent {
-		t.Fatalf("ожидался статус 204, получен %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ожидался статус 200, получен %d", resp.StatusCode)
 	}
 	_ = resp.Body.Close()
 
@@ -252,8 +252,8 @@ func TestWalletConcurrentLoad_SustainedHighRPS(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ошибка при начальном депозите: %v", err)
 	}
-	if resp.StatusCode != http.StatusNoContent {
-		t.Fatalf("ожидался статус 204, получен %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ожидался статус 200, получен %d", resp.StatusCode)
 	}
 	_ = resp.Body.Close()
 
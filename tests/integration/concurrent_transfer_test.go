@@ -0,0 +1,198 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func createFundedWallet(t *testing.T, baseURL string, amount int64) string {
+	t.Helper()
+
+	resp, err := http.Post(baseURL+"/api/v1/wallets", "application/json", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatalf("ошибка при создании кошелька: %v", err)
+	}
+	var createResp struct {
+		WalletId string `json:"walletId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа создания кошелька: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if amount > 0 {
+		body, _ := json.Marshal(map[string]interface{}{
+			"walletId":      createResp.WalletId,
+			"operationType": "DEPOSIT",
+			"amount":        amount,
+		})
+		resp, err = http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при начальном депозите: %v", err)
+		}
+		if resp.StatusCode >= 300 {
+			t.Fatalf("начальный депозит завершился со статусом %d", resp.StatusCode)
+		}
+		_ = resp.Body.Close()
+	}
+
+	return createResp.WalletId
+}
+
+func getBalance(t *testing.T, baseURL, walletID string) int64 {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/api/v1/wallets/" + walletID)
+	if err != nil {
+		t.Fatalf("ошибка при получении баланса: %v", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ошибка при получении баланса, статус: %d", resp.StatusCode)
+	}
+	var balanceResp struct {
+		Balance int64 `json:"balance"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&balanceResp); err != nil {
+		t.Fatalf("ошибка декодирования баланса: %v", err)
+	}
+	return balanceResp.Balance
+}
+
+func TestTransferIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	from := createFundedWallet(t, baseURL, 1000)
+	to := createFundedWallet(t, baseURL, 0)
+
+	transfer := func(from, to string, amount int64) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"fromWalletId": from,
+			"toWalletId":   to,
+			"amount":       amount,
+		})
+		resp, err := http.Post(baseURL+"/api/v1/transfers", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при переводе: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := transfer(from, to, 400); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200, получен %d", status)
+	}
+	if status := transfer(from, to, 601); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при недостатке средств, получен %d", status)
+	}
+	if status := transfer(from, from, 1); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 при переводе самому себе, получен %d", status)
+	}
+	if status := transfer(from, "00000000-0000-0000-0000-000000000001", 1); status != http.StatusNotFound {
+		t.Errorf("ожидался статус 404 для несуществующего получателя, получен %d", status)
+	}
+
+	if balance := getBalance(t, baseURL, from); balance != 600 {
+		t.Errorf("ожидался баланс отправителя 600, получен %d", balance)
+	}
+	if balance := getBalance(t, baseURL, to); balance != 400 {
+		t.Errorf("ожидался баланс получателя 400, получен %d", balance)
+	}
+}
+
+func TestTransferConcurrent_OppositeDirections(t *testing.T) {
+	if testing.Short() {
+		t.Skip("пропуск нагрузочного теста в режиме -short")
+	}
+
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	const (
+		initialBalance = 100000
+		transfersEach  = 500
+		workerCount    = 50
+		amount         = 7
+	)
+
+	walletA := createFundedWallet(t, baseURL, initialBalance)
+	walletB := createFundedWallet(t, baseURL, initialBalance)
+
+	type job struct{ from, to string }
+	jobs := make(chan job, 2*transfersEach)
+	for i := 0; i < transfersEach; i++ {
+		jobs <- job{walletA, walletB}
+		jobs <- job{walletB, walletA}
+	}
+	close(jobs)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded = map[string]int64{}
+		server5xx int
+	)
+
+	startTime := time.Now()
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := &http.Client{Timeout: 10 * time.Second}
+			for j := range jobs {
+				body, _ := json.Marshal(map[string]interface{}{
+					"fromWalletId": j.from,
+					"toWalletId":   j.to,
+					"amount":       amount,
+				})
+				resp, err := client.Post(baseURL+"/api/v1/transfers", "application/json", bytes.NewReader(body))
+				if err != nil {
+					t.Errorf("ошибка при переводе: %v", err)
+					continue
+				}
+				respBody, _ := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+
+				mu.Lock()
+				switch {
+				case resp.StatusCode >= 500:
+					server5xx++
+					t.Errorf("5xx ошибка при переводе: статус %d, тело: %s", resp.StatusCode, string(respBody))
+				case resp.StatusCode < 300:
+					succeeded[j.from]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	t.Logf("Выполнено %d встречных переводов за %v", 2*transfersEach, time.Since(startTime))
+
+	if server5xx > 0 {
+		t.Fatalf("обнаружены ошибки сервера (5xx): %d", server5xx)
+	}
+	if succeeded[walletA] != transfersEach || succeeded[walletB] != transfersEach {
+		t.Errorf("ожидалось %d успешных переводов в каждую сторону, получено A->B: %d, B->A: %d",
+			transfersEach, succeeded[walletA], succeeded[walletB])
+	}
+
+	// Встречные переводы на одинаковую сумму должны вернуть балансы к исходным
+	balanceA := getBalance(t, baseURL, walletA)
+	balanceB := getBalance(t, baseURL, walletB)
+	if balanceA+balanceB != 2*initialBalance {
+		t.Errorf("сумма балансов изменилась: %d + %d != %d", balanceA, balanceB, 2*initialBalance)
+	}
+	if balanceA != initialBalance || balanceB != initialBalance {
+		t.Errorf("ожидались исходные балансы %d, получено A: %d, B: %d", initialBalance, balanceA, balanceB)
+	}
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestWalletCreditLimitIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	walletID := createFundedWallet(t, baseURL, 100)
+
+	type walletBalance struct {
+		Balance          int64 `json:"balance"`
+		AvailableBalance int64 `json:"availableBalance"`
+		CreditLimit      int64 `json:"creditLimit"`
+		CreditUsed       int64 `json:"creditUsed"`
+	}
+	setCreditLimit := func(limit int64) (int, walletBalance) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"creditLimit": limit})
+		req, _ := http.NewRequest(http.MethodPut, baseURL+"/api/v1/admin/wallets/"+walletID+"/credit-limit", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка при смене кредитного лимита: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var wallet walletBalance
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&wallet); err != nil {
+				t.Fatalf("ошибка декодирования ответа: %v", err)
+			}
+		}
+		return resp.StatusCode, wallet
+	}
+	operation := func(opType string, amount int64) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": opType, "amount": amount})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при операции: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	getWallet := func() walletBalance {
+		t.Helper()
+		resp, err := http.Get(baseURL + "/api/v1/wallets/" + walletID + "/balance")
+		if err != nil {
+			t.Fatalf("ошибка при получении баланса: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var wallet walletBalance
+		if err := json.NewDecoder(resp.Body).Decode(&wallet); err != nil {
+			t.Fatalf("ошибка декодирования баланса: %v", err)
+		}
+		return wallet
+	}
+
+	// 1. Без кредитного лимита баланс не может стать отрицательным
+	if status := operation("WITHDRAW", 200); status != http.StatusConflict {
+		t.Fatalf("ожидался статус 409 без кредитного лимита, получен %d", status)
+	}
+
+	// 2. Кредитный лимит позволяет уйти в минус до -creditLimit
+	if status, wallet := setCreditLimit(500); status != http.StatusOK || wallet.CreditLimit != 500 || wallet.AvailableBalance != 600 {
+		t.Fatalf("неожиданный результат смены лимита: %d, %+v", status, wallet)
+	}
+	if status := operation("WITHDRAW", 400); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при списании в кредит, получен %d", status)
+	}
+	expected := walletBalance{Balance: -300, AvailableBalance: 200, CreditLimit: 500, CreditUsed: 300}
+	if wallet := getWallet(); wallet != expected {
+		t.Errorf("ожидалось %+v, получено %+v", expected, wallet)
+	}
+	if status := operation("WITHDRAW", 201); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при превышении кредитного лимита, получен %d", status)
+	}
+
+	// 3. Лимит нельзя снизить ниже использованного кредита, а кошелёк с долгом - закрыть
+	if status, _ := setCreditLimit(299); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при снижении лимита ниже долга, получен %d", status)
+	}
+	if status, _ := setCreditLimit(-1); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для отрицательного лимита, получен %d", status)
+	}
+	body, _ := json.Marshal(map[string]any{"status": "CLOSED", "reason": "закрытие договора"})
+	resp, err := http.Post(baseURL+"/api/v1/admin/wallets/"+walletID+"/status", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("ошибка при смене статуса: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при закрытии кошелька с долгом, получен %d", resp.StatusCode)
+	}
+
+	// 4. После погашения долга лимит можно снять
+	if status := operation("DEPOSIT", 300); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при пополнении, получен %d", status)
+	}
+	if status, wallet := setCreditLimit(0); status != http.StatusOK || wallet.CreditUsed != 0 || wallet.AvailableBalance != 0 {
+		t.Errorf("неожиданный результат снятия лимита: %d, %+v", status, wallet)
+	}
+}
@@ -0,0 +1,106 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestWalletCurrencyIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	createWallet := func(body string) (int, string, string) {
+		t.Helper()
+		resp, err := http.Post(baseURL+"/api/v1/wallets", "application/json", bytes.NewReader([]byte(body)))
+		if err != nil {
+			t.Fatalf("ошибка при создании кошелька: %v", err)
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(resp.Body)
+		var createResp struct {
+			WalletId string `json:"walletId"`
+			Currency string `json:"currency"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&createResp)
+		return resp.StatusCode, createResp.WalletId, createResp.Currency
+	}
+
+	status, kztWallet, code := createWallet(`{"currency": "KZT"}`)
+	if status != http.StatusCreated {
+		t.Fatalf("ожидался статус 201, получен %d", status)
+	}
+	if code != "KZT" {
+		t.Errorf("ожидалась валюта KZT, получена %q", code)
+	}
+
+	// Без тела запроса создаётся рублёвый кошелёк
+	status, rubWallet, code := createWallet(``)
+	if status != http.StatusCreated || code != "RUB" {
+		t.Errorf("ожидался рублёвый кошелёк со статусом 201, получены %d и %q", status, code)
+	}
+
+	if status, _, _ := createWallet(`{"currency": "ZZZ"}`); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для неизвестной валюты, получен %d", status)
+	}
+
+	operation := func(walletID, currency string) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"walletId":      walletID,
+			"operationType": "DEPOSIT",
+			"amount":        1000,
+			"currency":      currency,
+		})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при пополнении: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := operation(kztWallet, "USD"); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при несовпадении валюты, получен %d", status)
+	}
+	if status := operation(kztWallet, "KZT"); status != http.StatusOK {
+		t.Errorf("ожидался статус 200 для совпадающей валюты, получен %d", status)
+	}
+
+	// Перевод между кошельками в разных валютах запрещён
+	body, _ := json.Marshal(map[string]interface{}{
+		"fromWalletId": kztWallet,
+		"toWalletId":   rubWallet,
+		"amount":       100,
+	})
+	resp, err := http.Post(baseURL+"/api/v1/transfers", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("ошибка при переводе: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("ожидался статус 409 для перевода между валютами, получен %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(baseURL + "/api/v1/wallets/" + kztWallet)
+	if err != nil {
+		t.Fatalf("ошибка при получении баланса: %v", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	var balanceResp struct {
+		Balance    int64  `json:"balance"`
+		Currency   string `json:"currency"`
+		MinorUnits int    `json:"minorUnits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&balanceResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа: %v", err)
+	}
+	if balanceResp.Balance != 1000 || balanceResp.Currency != "KZT" || balanceResp.MinorUnits != 2 {
+		t.Errorf("ожидался баланс 1000 KZT с 2 знаками, получено %+v", balanceResp)
+	}
+}
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWalletExternalRefIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	externalRef := "user-" + uuid.NewString()
+	create := func(payload map[string]any) (int, string) {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		resp, err := http.Post(baseURL+"/api/v1/wallets", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при создании кошелька: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var createResp struct {
+			WalletId string `json:"walletId"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&createResp)
+		return resp.StatusCode, createResp.WalletId
+	}
+
+	// 1. Кошелёк с клиентским id и externalRef
+	walletID := uuid.NewString()
+	status, createdID := create(map[string]any{"walletId": walletID, "ownerId": "users", "externalRef": externalRef})
+	if status != http.StatusCreated || createdID != walletID {
+		t.Fatalf("ожидался статус 201 и id %s, получены %d и %s", walletID, status, createdID)
+	}
+
+	// 2. Повтор без флага - конфликт, с флагом - существующий кошелёк
+	if status, _ := create(map[string]any{"ownerId": "users", "externalRef": externalRef}); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 для занятого externalRef, получен %d", status)
+	}
+	status, existingID := create(map[string]any{"ownerId": "users", "externalRef": externalRef, "returnExisting": true})
+	if status != http.StatusOK || existingID != walletID {
+		t.Errorf("ожидался статус 200 и существующий кошелёк %s, получены %d и %s", walletID, status, existingID)
+	}
+
+	// 3. Тот же externalRef у другого владельца - отдельный кошелёк
+	if status, _ := create(map[string]any{"ownerId": "partners", "externalRef": externalRef}); status != http.StatusCreated {
+		t.Errorf("ожидался статус 201 для externalRef другого владельца, получен %d", status)
+	}
+
+	// 4. Поиск по externalRef
+	resp, err := http.Get(baseURL + "/api/v1/wallets?ownerId=users&externalRef=" + url.QueryEscape(externalRef))
+	if err != nil {
+		t.Fatalf("ошибка при поиске кошелька: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var listResp struct {
+		Items []struct {
+			WalletId    string `json:"walletId"`
+			ExternalRef string `json:"externalRef"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа поиска: %v", err)
+	}
+	if len(listResp.Items) != 1 || listResp.Items[0].WalletId != walletID {
+		t.Errorf("ожидался один кошелёк %s, получено %+v", walletID, listResp.Items)
+	}
+}
@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/groupcommit"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+)
+
+func TestDepositCoalescerIntegration(t *testing.T) {
+	cfg := testConfig()
+	if err := postgres.RunMigrations(cfg); err != nil {
+		t.Fatalf("не удалось применить миграции: %v", err)
+	}
+	pool := testPool(t)
+	repo := postgres.NewWalletRepository(pool, postgres.NewRetryPolicy(cfg))
+	ctx := context.Background()
+
+	const deposits = 10
+	// Группа заполняется последним пополнением, окно его не ограничивает
+	coalescer, err := groupcommit.NewDepositCoalescer(repo, time.Minute, deposits, time.Minute)
+	if err != nil {
+		t.Fatalf("ошибка создания группировщика: %v", err)
+	}
+	depositAll := func(walletID repository.Wallet) []*repository.Transaction {
+		t.Helper()
+		results := make([]*repository.Transaction, deposits)
+		var wg sync.WaitGroup
+		for i := 0; i < deposits; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tx, err := coalescer.Deposit(ctx, repository.Operation{WalletID: walletID.ID, Amount: int64(i + 1)})
+				if err != nil {
+					t.Errorf("ошибка пополнения: %v", err)
+					return
+				}
+				results[i] = tx
+			}()
+		}
+		wg.Wait()
+		return results
+	}
+	// transactions возвращает число транзакций базы, записавших историю кошелька
+	transactions := func(walletID repository.Wallet) int {
+		t.Helper()
+		var count int
+		err := pool.QueryRow(ctx,
+			"SELECT count(DISTINCT xmin::text) FROM wallet_transactions WHERE wallet_id = $1", walletID.ID).Scan(&count)
+		if err != nil {
+			t.Fatalf("ошибка чтения истории: %v", err)
+		}
+		return count
+	}
+
+	// 1. Пополнения группы фиксируются одной транзакцией, но каждое со своими записями
+	wallet, err := repo.CreateWallet(ctx, repository.NewWallet{Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("ошибка создания кошелька: %v", err)
+	}
+	results := depositAll(*wallet)
+	if count := transactions(*wallet); count != 1 {
+		t.Fatalf("ожидалась одна транзакция группы, история записана %d транзакциями", count)
+	}
+
+	// balance_after каждой записи - баланс после предыдущих пополнений группы плюс своя сумма
+	rows, err := pool.Query(ctx,
+		`SELECT amount, balance_after, journal_entry_id::text FROM wallet_transactions
+		WHERE wallet_id = $1 ORDER BY balance_after`, wallet.ID)
+	if err != nil {
+		t.Fatalf("ошибка чтения истории: %v", err)
+	}
+	var (
+		balance  int64
+		entries  = make(map[string]bool)
+		recorded = make(map[int64]bool)
+	)
+	for rows.Next() {
+		var (
+			amount, balanceAfter int64
+			entryID              string
+		)
+		if err := rows.Scan(&amount, &balanceAfter, &entryID); err != nil {
+			t.Fatalf("ошибка чтения записи истории: %v", err)
+		}
+		balance += amount
+		if balanceAfter != balance {
+			t.Errorf("ожидался balance_after %d, получен %d", balance, balanceAfter)
+		}
+		entries[entryID] = true
+		recorded[balanceAfter] = true
+	}
+	rows.Close()
+	if expected := int64(deposits * (deposits + 1) / 2); balance != expected || len(entries) != deposits {
+		t.Fatalf("ожидалось %d записей с отдельными проводками и баланс %d, получено %d проводок и баланс %d",
+			deposits, expected, len(entries), balance)
+	}
+	for _, tx := range results {
+		if tx != nil && !recorded[tx.BalanceAfter] {
+			t.Errorf("баланс ответа %d не совпадает ни с одной записью истории", tx.BalanceAfter)
+		}
+	}
+
+	var ledgerBalance int64
+	if err := pool.QueryRow(ctx, "SELECT sum(amount) FROM journal_lines WHERE wallet_id = $1", wallet.ID).Scan(&ledgerBalance); err != nil {
+		t.Fatalf("ошибка чтения журнала: %v", err)
+	}
+	if ledgerBalance != balance {
+		t.Errorf("ожидался баланс по журналу %d, получен %d", balance, ledgerBalance)
+	}
+
+	eventRows, err := pool.Query(ctx,
+		"SELECT payload FROM outbox_events WHERE wallet_id = $1 AND type = $2", wallet.ID, events.Deposited)
+	if err != nil {
+		t.Fatalf("ошибка чтения outbox: %v", err)
+	}
+	balances := make(map[int64]bool)
+	for eventRows.Next() {
+		var (
+			data    []byte
+			payload events.BalanceChangedPayload
+		)
+		if err := eventRows.Scan(&data); err != nil {
+			t.Fatalf("ошибка чтения события: %v", err)
+		}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("ошибка декодирования события: %v", err)
+		}
+		if !recorded[payload.BalanceAfter] {
+			t.Errorf("баланс события %d не совпадает ни с одной записью истории", payload.BalanceAfter)
+		}
+		balances[payload.BalanceAfter] = true
+	}
+	eventRows.Close()
+	if len(balances) != deposits {
+		t.Errorf("ожидалось %d событий с разными балансами, получено %d", deposits, len(balances))
+	}
+
+	// 2. Пополнения шардированного кошелька не объединяются и идут через корзины
+	sharded, err := repo.CreateWallet(ctx, repository.NewWallet{Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("ошибка создания кошелька: %v", err)
+	}
+	if _, err := repo.SetBalanceBuckets(ctx, sharded.ID, 4); err != nil {
+		t.Fatalf("ошибка включения корзин: %v", err)
+	}
+	depositAll(*sharded)
+	if count := transactions(*sharded); count != deposits {
+		t.Errorf("ожидалось %d отдельных транзакций, история записана %d транзакциями", deposits, count)
+	}
+	var buckets int64
+	if err := pool.QueryRow(ctx,
+		"SELECT COALESCE(sum(balance), 0) FROM wallet_balance_buckets WHERE wallet_id = $1", sharded.ID).Scan(&buckets); err != nil {
+		t.Fatalf("ошибка чтения корзин: %v", err)
+	}
+	if expected := int64(deposits * (deposits + 1) / 2); buckets != expected {
+		t.Errorf("ожидалось %d в корзинах, получено %d", expected, buckets)
+	}
+}
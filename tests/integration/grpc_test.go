@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated/walletpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCWalletOperationsIntegration(t *testing.T) {
+	_, cleanup := testServer(t)
+	defer cleanup()
+
+	conn, err := grpc.NewClient("localhost:"+testConfig().GRPCPort, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("не удалось подключиться к gRPC серверу: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	client := walletpb.NewWalletServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// 1. Создание кошелька и пополнение
+	created, err := client.CreateWallet(ctx, &walletpb.CreateWalletRequest{})
+	if err != nil {
+		t.Fatalf("ошибка при создании кошелька: %v", err)
+	}
+	walletID := created.GetWallet().GetWalletId()
+	if !created.GetCreated() || created.GetWallet().GetStatus() != walletpb.WalletStatus_WALLET_STATUS_ACTIVE {
+		t.Fatalf("ожидался новый активный кошелёк, получен %v", created)
+	}
+
+	deposit := &walletpb.ProcessWalletOperationRequest{
+		WalletId:       walletID,
+		OperationType:  walletpb.OperationType_OPERATION_TYPE_DEPOSIT,
+		Amount:         1000,
+		IdempotencyKey: "grpc-deposit-" + walletID,
+	}
+	first, err := client.ProcessWalletOperation(ctx, deposit)
+	if err != nil {
+		t.Fatalf("ошибка при пополнении: %v", err)
+	}
+	if first.GetBalance() != 1000 {
+		t.Errorf("ожидался баланс 1000 после пополнения, получен %d", first.GetBalance())
+	}
+
+	// 2. Повтор с тем же ключом возвращает первый результат, с другим телом - отклоняется
+	repeated, err := client.ProcessWalletOperation(ctx, deposit)
+	if err != nil || repeated.GetOperationId() != first.GetOperationId() {
+		t.Errorf("повтор с тем же ключом должен вернуть первую операцию, получены %v и %v", repeated, err)
+	}
+	deposit.Amount = 500
+	if _, err := client.ProcessWalletOperation(ctx, deposit); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ожидался статус FAILED_PRECONDITION для повтора ключа с другим телом, получена ошибка %v", err)
+	}
+
+	// 3. Ошибки сервиса переводятся в статусы gRPC
+	withdraw := &walletpb.ProcessWalletOperationRequest{
+		WalletId:      walletID,
+		OperationType: walletpb.OperationType_OPERATION_TYPE_WITHDRAW,
+		Amount:        5000,
+	}
+	if _, err := client.ProcessWalletOperation(ctx, withdraw); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("ожидался статус FAILED_PRECONDITION для недостатка средств, получена ошибка %v", err)
+	}
+	if _, err := client.GetWalletBalance(ctx, &walletpb.GetWalletBalanceRequest{WalletId: "00000000-0000-0000-0000-000000000001"}); status.Code(err) != codes.NotFound {
+		t.Errorf("ожидался статус NOT_FOUND для несуществующего кошелька, получена ошибка %v", err)
+	}
+
+	// 4. WatchBalance отправляет текущее состояние и каждое изменение
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	stream, err := client.WatchBalance(watchCtx, &walletpb.WatchBalanceRequest{WalletId: walletID})
+	if err != nil {
+		t.Fatalf("ошибка при подписке на баланс: %v", err)
+	}
+	current, err := stream.Recv()
+	if err != nil || current.GetBalance() != 1000 {
+		t.Fatalf("ожидалось текущее состояние с балансом 1000, получены %v и %v", current, err)
+	}
+
+	// 5. Списание с устаревшей версией отклоняется, с текущей - выполняется
+	stale := current.GetVersion() - 1
+	withdraw.Amount = 300
+	withdraw.ExpectedVersion = &stale
+	if _, err := client.ProcessWalletOperation(ctx, withdraw); status.Code(err) != codes.Aborted {
+		t.Errorf("ожидался статус ABORTED для устаревшей версии, получена ошибка %v", err)
+	}
+	version := current.GetVersion()
+	withdraw.ExpectedVersion = &version
+	if _, err := client.ProcessWalletOperation(ctx, withdraw); err != nil {
+		t.Fatalf("ошибка при списании с текущей версией: %v", err)
+	}
+
+	updated, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("ошибка при получении изменения баланса: %v", err)
+	}
+	if updated.GetBalance() != 700 || updated.GetVersion() <= current.GetVersion() {
+		t.Errorf("ожидался баланс 700 с новой версией, получены баланс %d и версия %d", updated.GetBalance(), updated.GetVersion())
+	}
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+type holdResponse struct {
+	HoldId             string `json:"holdId"`
+	Amount             int64  `json:"amount"`
+	CapturedAmount     int64  `json:"capturedAmount"`
+	Status             string `json:"status"`
+	CaptureOperationId string `json:"captureOperationId"`
+}
+
+func getAvailableBalance(t *testing.T, baseURL, walletID string) (balance, available int64) {
+	t.Helper()
+
+	resp, err := http.Get(baseURL + "/api/v1/wallets/" + walletID)
+	if err != nil {
+		t.Fatalf("ошибка при получении баланса: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	var balanceResp struct {
+		Balance          int64 `json:"balance"`
+		AvailableBalance int64 `json:"availableBalance"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&balanceResp); err != nil {
+		t.Fatalf("ошибка декодирования баланса: %v", err)
+	}
+	return balanceResp.Balance, balanceResp.AvailableBalance
+}
+
+func TestHoldIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	walletID := createFundedWallet(t, baseURL, 1000)
+	holdsURL := baseURL + "/api/v1/wallets/" + walletID + "/holds"
+
+	post := func(url string, payload any) (int, holdResponse) {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		resp, err := http.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка запроса %s: %v", url, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var hold holdResponse
+		if resp.StatusCode < 300 {
+			if err := json.NewDecoder(resp.Body).Decode(&hold); err != nil {
+				t.Fatalf("ошибка декодирования блокировки: %v", err)
+			}
+		}
+		return resp.StatusCode, hold
+	}
+
+	// 1. Блокировка уменьшает доступный баланс, но не баланс
+	status, hold := post(holdsURL, map[string]any{"amount": 600, "ttlSeconds": 60})
+	if status != http.StatusCreated {
+		t.Fatalf("ожидался статус 201, получен %d", status)
+	}
+	if hold.Status != "ACTIVE" {
+		t.Errorf("ожидался статус блокировки ACTIVE, получен %s", hold.Status)
+	}
+	if balance, available := getAvailableBalance(t, baseURL, walletID); balance != 1000 || available != 400 {
+		t.Errorf("ожидались баланс 1000 и доступно 400, получено %d и %d", balance, available)
+	}
+
+	// 2. Списание и вторая блокировка не могут использовать заблокированные средства
+	withdrawBody, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": "WITHDRAW", "amount": 500})
+	resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(withdrawBody))
+	if err != nil {
+		t.Fatalf("ошибка при списании: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при списании заблокированных средств, получен %d", resp.StatusCode)
+	}
+	if status, _ := post(holdsURL, map[string]any{"amount": 401}); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при блокировке сверх доступного, получен %d", status)
+	}
+
+	// 3. Частичное списание блокировки освобождает остаток
+	status, captured := post(holdsURL+"/"+hold.HoldId+"/capture", map[string]any{"amount": 250})
+	if status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при списании блокировки, получен %d", status)
+	}
+	if captured.Status != "CAPTURED" || captured.CapturedAmount != 250 || captured.CaptureOperationId == "" {
+		t.Errorf("неожиданный результат списания блокировки: %+v", captured)
+	}
+	if balance, available := getAvailableBalance(t, baseURL, walletID); balance != 750 || available != 750 {
+		t.Errorf("ожидались баланс 750 и доступно 750, получено %d и %d", balance, available)
+	}
+
+	// 4. Повторное списание и отмена завершённой блокировки запрещены
+	if status, _ := post(holdsURL+"/"+hold.HoldId+"/capture", map[string]any{}); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при повторном списании, получен %d", status)
+	}
+	if status, _ := post(holdsURL+"/"+hold.HoldId+"/void", nil); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при отмене списанной блокировки, получен %d", status)
+	}
+
+	// 5. Отмена блокировки возвращает средства в доступный баланс
+	_, hold = post(holdsURL, map[string]any{"amount": 700})
+	status, voided := post(holdsURL+"/"+hold.HoldId+"/void", nil)
+	if status != http.StatusOK || voided.Status != "VOIDED" {
+		t.Errorf("ожидалась отменённая блокировка, статус %d, блокировка %+v", status, voided)
+	}
+	if balance, available := getAvailableBalance(t, baseURL, walletID); balance != 750 || available != 750 {
+		t.Errorf("ожидались баланс 750 и доступно 750, получено %d и %d", balance, available)
+	}
+
+	// 6. Списание сверх заблокированной суммы отклоняется
+	_, hold = post(holdsURL, map[string]any{"amount": 100})
+	if status, _ := post(holdsURL+"/"+hold.HoldId+"/capture", map[string]any{"amount": 101}); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 при списании сверх блокировки, получен %d", status)
+	}
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWalletIdempotencyIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	resp, err := http.Post(baseURL+"/api/v1/wallets", "application/json", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatalf("ошибка при создании кошелька: %v", err)
+	}
+	var createResp struct {
+		WalletId string `json:"walletId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа создания кошелька: %v", err)
+	}
+	_ = resp.Body.Close()
+	walletID := createResp.WalletId
+
+	post := func(key string, amount int64) (int, string) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]interface{}{
+			"walletId":      walletID,
+			"operationType": "DEPOSIT",
+			"amount":        amount,
+		})
+		req, _ := http.NewRequest("POST", baseURL+"/api/v1/wallet", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка при пополнении: %v", err)
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(resp.Body)
+		var opResp struct {
+			OperationId string `json:"operationId"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&opResp)
+		return resp.StatusCode, opResp.OperationId
+	}
+
+	key := uuid.NewString()
+
+	// Первый запрос и два повтора с тем же ключом возвращают одну и ту же операцию
+	var firstOperationID string
+	for i := 0; i < 3; i++ {
+		status, operationID := post(key, 500)
+		if status != http.StatusOK {
+			t.Fatalf("попытка %d: ожидался статус 200, получен %d", i+1, status)
+		}
+		if i == 0 {
+			firstOperationID = operationID
+		} else if operationID != firstOperationID {
+			t.Errorf("попытка %d: ожидался operationId %s, получен %s", i+1, firstOperationID, operationID)
+		}
+	}
+
+	// Тот же ключ с другим телом запроса
+	if status, _ := post(key, 700); status != http.StatusUnprocessableEntity {
+		t.Errorf("ожидался статус 422, получен %d", status)
+	}
+
+	resp, err = http.Get(baseURL + "/api/v1/wallets/" + walletID)
+	if err != nil {
+		t.Fatalf("ошибка при получении баланса: %v", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	var balanceResp struct {
+		Balance int64 `json:"balance"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&balanceResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа: %v", err)
+	}
+	if balanceResp.Balance != 500 {
+		t.Errorf("ожидался баланс 500 после повторов, получен %d", balanceResp.Balance)
+	}
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestJournalIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+	pool := testPool(t)
+	ctx := context.Background()
+
+	from := createFundedWallet(t, baseURL, 1000)
+	to := createFundedWallet(t, baseURL, 0)
+
+	post := func(path string, payload map[string]any) {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		resp, err := http.Post(baseURL+path, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка запроса %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("запрос %s завершился со статусом %d", path, resp.StatusCode)
+		}
+	}
+	post("/api/v1/wallet", map[string]any{"walletId": from, "operationType": "WITHDRAW", "amount": 300})
+	post("/api/v1/wallet/transfer", map[string]any{"fromWalletId": from, "toWalletId": to, "amount": 200})
+
+	// 1. Баланс кошелька совпадает с суммой его строк в журнале
+	for _, walletID := range []string{from, to} {
+		var journalBalance int64
+		err := pool.QueryRow(ctx,
+			"SELECT COALESCE(sum(amount), 0) FROM journal_lines WHERE wallet_id = $1", walletID).Scan(&journalBalance)
+		if err != nil {
+			t.Fatalf("ошибка чтения журнала: %v", err)
+		}
+		if balance := getBalance(t, baseURL, walletID); balance != journalBalance {
+			t.Errorf("баланс кошелька %s равен %d, а по журналу %d", walletID, balance, journalBalance)
+		}
+	}
+
+	// 2. Каждая запись истории ссылается на сбалансированную проводку
+	var unbalanced int
+	err := pool.QueryRow(ctx, `SELECT count(*) FROM (
+			SELECT l.entry_id FROM journal_lines l
+			JOIN wallet_transactions t ON t.journal_entry_id = l.entry_id
+			WHERE t.wallet_id = ANY($1::uuid[])
+			GROUP BY l.entry_id, l.currency
+			HAVING sum(l.amount) <> 0
+		) u`, []string{from, to}).Scan(&unbalanced)
+	if err != nil {
+		t.Fatalf("ошибка проверки проводок: %v", err)
+	}
+	if unbalanced != 0 {
+		t.Errorf("найдено %d несбалансированных проводок", unbalanced)
+	}
+
+	var missing int
+	err = pool.QueryRow(ctx,
+		"SELECT count(*) FROM wallet_transactions WHERE wallet_id = ANY($1::uuid[]) AND journal_entry_id IS NULL",
+		[]string{from, to}).Scan(&missing)
+	if err != nil {
+		t.Fatalf("ошибка чтения истории: %v", err)
+	}
+	if missing != 0 {
+		t.Errorf("у %d записей истории нет проводки", missing)
+	}
+
+	// 3. База не даёт зафиксировать несбалансированную проводку
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("ошибка начала транзакции: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	entryID := uuid.New()
+	if _, err := tx.Exec(ctx, "INSERT INTO journal_entries (id, type) VALUES ($1, 'DEPOSIT')", entryID); err != nil {
+		t.Fatalf("ошибка записи проводки: %v", err)
+	}
+	_, err = tx.Exec(ctx, `INSERT INTO journal_lines (entry_id, wallet_id, system_account, currency, amount)
+		VALUES ($1, $2, NULL, 'RUB', 100), ($1, NULL, 'EXTERNAL_FUNDING', 'RUB', -99)`, entryID, to)
+	if err != nil {
+		t.Fatalf("ошибка записи строк проводки: %v", err)
+	}
+	if err := tx.Commit(ctx); err == nil {
+		t.Error("несбалансированная проводка не должна фиксироваться")
+	}
+}
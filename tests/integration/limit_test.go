@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestOperationLimitsIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	call := func(method, path string, payload any, out any) int {
+		t.Helper()
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewReader(data)
+		}
+		req, _ := http.NewRequest(method, baseURL+path, body)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка запроса %s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				t.Fatalf("ошибка декодирования ответа %s %s: %v", method, path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+	type limitError struct {
+		Message   string `json:"message"`
+		Operation string `json:"operation"`
+		Period    string `json:"period"`
+		Limit     int64  `json:"limit"`
+		Used      int64  `json:"used"`
+		Remaining int64  `json:"remaining"`
+	}
+	withdraw := func(walletID string, amount int64, out any) int {
+		t.Helper()
+		return call(http.MethodPost, "/api/v1/wallet",
+			map[string]any{"walletId": walletID, "operationType": "WITHDRAW", "amount": amount}, out)
+	}
+
+	walletID := createFundedWallet(t, baseURL, 10000)
+	limitsPath := "/api/v1/admin/wallets/" + walletID + "/limits/WITHDRAW"
+
+	// 1. Лимиты кошелька: не больше 1000 за операцию и 1500 за сутки
+	if status := call(http.MethodPut, limitsPath, map[string]any{"maxAmount": 1000, "daily": 1500}, nil); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при установке лимитов, получен %d", status)
+	}
+	if status := call(http.MethodPut, limitsPath, map[string]any{"daily": 0}, nil); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для нулевого лимита, получен %d", status)
+	}
+
+	var violation limitError
+	if status := withdraw(walletID, 1001, &violation); status != http.StatusForbidden {
+		t.Fatalf("ожидался статус 403 для суммы больше лимита операции, получен %d", status)
+	}
+	if violation.Period != "OPERATION" || violation.Remaining != 1000 {
+		t.Errorf("неожиданное нарушение лимита: %+v", violation)
+	}
+
+	// 2. Суточный лимит учитывает предыдущие списания, остаток возвращается в ответе
+	if status := withdraw(walletID, 1000, nil); status != http.StatusOK {
+		t.Fatalf("списание в пределах лимитов завершилось со статусом %d", status)
+	}
+	violation = limitError{}
+	if status := withdraw(walletID, 600, &violation); status != http.StatusForbidden {
+		t.Fatalf("ожидался статус 403 при превышении суточного лимита, получен %d", status)
+	}
+	expected := limitError{Operation: "WITHDRAW", Period: "DAILY", Limit: 1500, Used: 1000, Remaining: 500}
+	violation.Message = ""
+	if violation != expected {
+		t.Errorf("ожидалось %+v, получено %+v", expected, violation)
+	}
+	if balance := getBalance(t, baseURL, walletID); balance != 9000 {
+		t.Errorf("отклонённое списание не должно менять баланс: ожидался 9000, получен %d", balance)
+	}
+
+	// 3. Лимиты кошелька показывают использование и остаток
+	var limits struct {
+		Items []struct {
+			Operation string `json:"operation"`
+			Override  bool   `json:"override"`
+			Usage     struct {
+				Daily int64 `json:"daily"`
+			} `json:"usage"`
+			Remaining *int64 `json:"remaining"`
+		} `json:"items"`
+	}
+	if status := call(http.MethodGet, "/api/v1/admin/wallets/"+walletID+"/limits", nil, &limits); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при получении лимитов, получен %d", status)
+	}
+	for _, l := range limits.Items {
+		if l.Operation != "WITHDRAW" {
+			continue
+		}
+		if !l.Override || l.Usage.Daily != 1000 || l.Remaining == nil || *l.Remaining != 500 {
+			t.Errorf("неожиданные лимиты списаний: %+v", l)
+		}
+	}
+
+	// 4. Лимиты действуют и на операции пакета
+	var batch struct {
+		Results []struct {
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+	status := call(http.MethodPost, "/api/v1/wallet/batch", map[string]any{
+		"mode": "ATOMIC",
+		"items": []map[string]any{
+			{"walletId": walletID, "operationType": "WITHDRAW", "amount": 300},
+			{"walletId": walletID, "operationType": "WITHDRAW", "amount": 300},
+		},
+	}, &batch)
+	if status != http.StatusForbidden {
+		t.Errorf("ожидался статус 403 для пакета сверх суточного лимита, получен %d", status)
+	}
+
+	// 5. Лимиты по умолчанию действуют на кошельки без собственных лимитов
+	defer call(http.MethodDelete, "/api/v1/admin/limits/TRANSFER", nil, nil)
+	if status := call(http.MethodPut, "/api/v1/admin/limits/TRANSFER", map[string]any{"maxAmount": 100}, nil); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при установке лимитов по умолчанию, получен %d", status)
+	}
+	recipient := createFundedWallet(t, baseURL, 0)
+	transfer := func(from string) int {
+		return call(http.MethodPost, "/api/v1/transfers",
+			map[string]any{"fromWalletId": from, "toWalletId": recipient, "amount": 200}, nil)
+	}
+	if status := transfer(walletID); status != http.StatusForbidden {
+		t.Errorf("ожидался статус 403 для перевода сверх лимита по умолчанию, получен %d", status)
+	}
+
+	// Лимиты кошелька без значений снимают ограничения по умолчанию
+	transferLimits := "/api/v1/admin/wallets/" + walletID + "/limits/TRANSFER"
+	if status := call(http.MethodPut, transferLimits, map[string]any{}, nil); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при установке лимитов кошелька, получен %d", status)
+	}
+	if status := transfer(walletID); status != http.StatusOK {
+		t.Errorf("ожидался статус 200 для перевода без лимитов кошелька, получен %d", status)
+	}
+	if status := call(http.MethodDelete, transferLimits, nil, nil); status != http.StatusNoContent {
+		t.Errorf("ожидался статус 204 при удалении лимитов кошелька, получен %d", status)
+	}
+	if status := transfer(walletID); status != http.StatusForbidden {
+		t.Errorf("после удаления лимитов кошелька должны действовать лимиты по умолчанию, получен %d", status)
+	}
+	if status := call(http.MethodDelete, transferLimits, nil, nil); status != http.StatusNotFound {
+		t.Errorf("ожидался статус 404 при удалении отсутствующих лимитов, получен %d", status)
+	}
+}
@@ -0,0 +1,177 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+)
+
+func TestOutboxIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+	pool := testPool(t)
+	ctx := context.Background()
+
+	walletID := createFundedWallet(t, baseURL, 500)
+	body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": "WITHDRAW", "amount": 200})
+	resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("ошибка списания: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("списание завершилось со статусом %d", resp.StatusCode)
+	}
+
+	// 1. Каждое изменение кошелька записано в outbox в порядке выполнения
+	rows, err := pool.Query(ctx,
+		"SELECT type, payload FROM outbox_events WHERE wallet_id = $1 ORDER BY sequence", walletID)
+	if err != nil {
+		t.Fatalf("ошибка чтения outbox: %v", err)
+	}
+	var (
+		types    []events.Type
+		payloads []events.BalanceChangedPayload
+	)
+	for rows.Next() {
+		var (
+			eventType events.Type
+			payload   []byte
+		)
+		if err := rows.Scan(&eventType, &payload); err != nil {
+			t.Fatalf("ошибка чтения события: %v", err)
+		}
+		types = append(types, eventType)
+		if eventType != events.WalletCreated {
+			var p events.BalanceChangedPayload
+			if err := json.Unmarshal(payload, &p); err != nil {
+				t.Fatalf("ошибка декодирования события: %v", err)
+			}
+			payloads = append(payloads, p)
+		}
+	}
+	rows.Close()
+
+	expected := []events.Type{events.WalletCreated, events.Deposited, events.Withdrawn}
+	if len(types) != len(expected) {
+		t.Fatalf("ожидались события %v, получены %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Fatalf("ожидались события %v, получены %v", expected, types)
+		}
+	}
+	if payloads[0].Amount != 500 || payloads[0].BalanceAfter != 500 ||
+		payloads[1].Amount != -200 || payloads[1].BalanceAfter != 300 || payloads[1].Currency != "RUB" {
+		t.Errorf("неожиданное содержимое событий: %+v", payloads)
+	}
+
+	// 2. Фоновая доставка отмечает события доставленными
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var pending int
+		err := pool.QueryRow(ctx,
+			"SELECT count(*) FROM outbox_events WHERE wallet_id = $1 AND published_at IS NULL", walletID).Scan(&pending)
+		if err != nil {
+			t.Fatalf("ошибка чтения outbox: %v", err)
+		}
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("события не доставлены за 10 секунд, осталось %d", pending)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func TestOutboxOrderShardedIntegration(t *testing.T) {
+	cfg := testConfig()
+	if err := postgres.RunMigrations(cfg); err != nil {
+		t.Fatalf("не удалось применить миграции: %v", err)
+	}
+	pool := testPool(t)
+	txRetry := postgres.NewRetryPolicy(cfg)
+	repo := postgres.NewWalletRepository(pool, txRetry)
+	outbox := postgres.NewOutboxRepository(pool, txRetry)
+	ctx := context.Background()
+
+	wallet, err := repo.CreateWallet(ctx, repository.NewWallet{Currency: "RUB"})
+	if err != nil {
+		t.Fatalf("ошибка создания кошелька: %v", err)
+	}
+	if _, err := repo.SetBalanceBuckets(ctx, wallet.ID, 8); err != nil {
+		t.Fatalf("ошибка включения корзин: %v", err)
+	}
+
+	// Доставка идёт параллельно с операциями, чтобы застать незафиксированные события
+	var delivered []int64
+	publish := func(e repository.OutboxEvent) error {
+		if e.WalletID == wallet.ID {
+			delivered = append(delivered, e.Sequence)
+		}
+		return nil
+	}
+	done := make(chan struct{})
+	relayed := make(chan struct{})
+	go func() {
+		defer close(relayed)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if _, err := outbox.RelayOutbox(ctx, 10, publish); err != nil {
+				t.Errorf("ошибка доставки событий: %v", err)
+				return
+			}
+		}
+	}()
+
+	const workers, perWorker = 16, 25
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				if _, err := repo.Deposit(ctx, repository.Operation{WalletID: wallet.ID, Amount: 1}); err != nil {
+					t.Errorf("ошибка пополнения: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	<-relayed
+
+	for {
+		published, err := outbox.RelayOutbox(ctx, 100, publish)
+		if err != nil {
+			t.Fatalf("ошибка доставки событий: %v", err)
+		}
+		if published == 0 {
+			break
+		}
+	}
+
+	// Событие создания, смена корзин событий не пишет, и по событию на пополнение
+	if expected := 1 + workers*perWorker; len(delivered) != expected {
+		t.Fatalf("ожидалось %d событий кошелька, доставлено %d", expected, len(delivered))
+	}
+	for i := 1; i < len(delivered); i++ {
+		if delivered[i] <= delivered[i-1] {
+			t.Fatalf("событие %d доставлено после события %d", delivered[i], delivered[i-1])
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+)
+
+func TestReconciliationIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+	pool := testPool(t)
+	ctx := context.Background()
+	svc := service.NewReconciliationService(postgres.NewReconciliationRepository(pool, postgres.NewRetryPolicy(testConfig())))
+
+	walletID := createFundedWallet(t, baseURL, 500)
+	findDrift := func(run *repository.ReconciliationRun) *repository.BalanceDrift {
+		for i := range run.Drifts {
+			if run.Drifts[i].WalletID.String() == walletID {
+				return &run.Drifts[i]
+			}
+		}
+		return nil
+	}
+
+	// 1. Баланс, изменённый в обход журнала, обнаруживается сверкой
+	if _, err := pool.Exec(ctx, "UPDATE wallets SET balance = balance + 7 WHERE id = $1", walletID); err != nil {
+		t.Fatalf("ошибка изменения баланса: %v", err)
+	}
+	run, err := svc.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("ошибка сверки: %v", err)
+	}
+	drift := findDrift(run)
+	if drift == nil || drift.Balance != 507 || drift.LedgerBalance != 500 || drift.Drift != 7 || drift.Corrected {
+		t.Fatalf("ожидалось расхождение 7 без корректировки, получено %+v", drift)
+	}
+
+	// 2. Последний запуск доступен через административный эндпоинт
+	resp, err := http.Get(baseURL + "/api/v1/admin/reconciliation")
+	if err != nil {
+		t.Fatalf("ошибка получения результата сверки: %v", err)
+	}
+	var report struct {
+		ID     uuid.UUID `json:"id"`
+		Drifts []struct {
+			WalletID string `json:"walletId"`
+			Drift    int64  `json:"drift"`
+		} `json:"drifts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("ошибка декодирования результата сверки: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || report.ID != run.ID {
+		t.Errorf("ожидался результат запуска %s со статусом 200, получены %d и %s", run.ID, resp.StatusCode, report.ID)
+	}
+
+	// 3. Корректировка выравнивает журнал с балансом, не меняя баланс, и отражается в истории
+	run, err = svc.Reconcile(ctx, true)
+	if err != nil {
+		t.Fatalf("ошибка сверки с корректировкой: %v", err)
+	}
+	if drift := findDrift(run); drift == nil || !drift.Corrected || drift.Drift != 7 {
+		t.Errorf("ожидалась корректировка расхождения 7, получено %+v", drift)
+	}
+	if balance := getBalance(t, baseURL, walletID); balance != 507 {
+		t.Errorf("корректировка не должна менять баланс, получен %d", balance)
+	}
+	resp, err = http.Get(baseURL + "/api/v1/wallets/" + walletID + "/transactions?type=ADJUSTMENT")
+	if err != nil {
+		t.Fatalf("ошибка получения истории операций: %v", err)
+	}
+	var history struct {
+		Items []struct {
+			Amount       int64 `json:"amount"`
+			BalanceAfter int64 `json:"balanceAfter"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&history); err != nil {
+		t.Fatalf("ошибка декодирования истории операций: %v", err)
+	}
+	_ = resp.Body.Close()
+	if len(history.Items) != 1 || history.Items[0].Amount != 7 || history.Items[0].BalanceAfter != 507 {
+		t.Errorf("ожидалась запись ADJUSTMENT на 7, получено %+v", history.Items)
+	}
+	var reconciliationAmount int64
+	err = pool.QueryRow(ctx, `SELECT l.amount FROM wallet_transactions t
+		JOIN journal_lines l ON l.entry_id = t.journal_entry_id AND l.system_account = 'RECONCILIATION'
+		WHERE t.wallet_id = $1 AND t.type = 'ADJUSTMENT'`, walletID).Scan(&reconciliationAmount)
+	if err != nil || reconciliationAmount != -7 {
+		t.Errorf("ожидалась проводка ADJUSTMENT на счёт RECONCILIATION на -7, получено %d (%v)", reconciliationAmount, err)
+	}
+
+	run, err = svc.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatalf("ошибка повторной сверки: %v", err)
+	}
+	if drift := findDrift(run); drift != nil {
+		t.Errorf("после корректировки расхождений быть не должно, получено %+v", drift)
+	}
+}
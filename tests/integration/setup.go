@@ -10,14 +10,34 @@ import (
 
 	"github.com/devopesik/wallet-basic-operations/internal/app"
 	"github.com/devopesik/wallet-basic-operations/internal/config"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func testServer(t *testing.T) (string, func()) {
-
+// testConfig загружает конфигурацию тестового окружения
+func testConfig() *config.Config {
 	cfg := config.Load("../../config.env")
 	if dbHost, ok := os.LookupEnv("DB_HOST"); ok {
 		cfg.DBHost = dbHost
 	}
+	return cfg
+}
+
+// testPool открывает прямое подключение к базе для проверок, недоступных через API
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := postgres.NewPool(testConfig())
+	if err != nil {
+		t.Fatalf("не удалось подключиться к базе: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func testServer(t *testing.T) (string, func()) {
+
+	cfg := testConfig()
 
 	application, err := app.StartServer(cfg)
 	if err != nil {
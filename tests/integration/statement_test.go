@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+)
+
+func TestWalletStatementIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	// operation выполняет операцию и возвращает её время по часам базы
+	operation := func(walletID, opType string, amount int64) time.Time {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": opType, "amount": amount})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при операции: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		var op struct {
+			CreatedAt time.Time `json:"createdAt"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&op); err != nil {
+			t.Fatalf("ошибка декодирования ответа операции: %v", err)
+		}
+		return op.CreatedAt
+	}
+	statement := func(walletID string, query url.Values) *http.Response {
+		t.Helper()
+		resp, err := http.Get(baseURL + "/api/v1/wallets/" + walletID + "/statement?" + query.Encode())
+		if err != nil {
+			t.Fatalf("ошибка получения выписки: %v", err)
+		}
+		return resp
+	}
+
+	walletID := createFundedWallet(t, baseURL, 1000)
+	from := operation(walletID, "WITHDRAW", 300)
+	operation(walletID, "DEPOSIT", 50)
+	period := url.Values{"from": {from.Format(time.RFC3339Nano)}}
+
+	// 1. JSON: начальный баланс до from, операции с нарастающим балансом и итоговый баланс
+	resp := statement(walletID, period)
+	var doc generated.WalletStatement
+	err := json.NewDecoder(resp.Body).Decode(&doc)
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || err != nil {
+		t.Fatalf("ожидался статус 200 с JSON выпиской, получены %d и %v", resp.StatusCode, err)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Disposition"), "attachment; filename=statement-"+walletID) {
+		t.Errorf("некорректный Content-Disposition: %q", resp.Header.Get("Content-Disposition"))
+	}
+	if doc.OpeningBalance != 1000 || doc.ClosingBalance != 750 || len(doc.Transactions) != 2 {
+		t.Fatalf("ожидалась выписка 1000 -> 750 с двумя операциями, получена %+v", doc)
+	}
+	if doc.Transactions[0].Balance != 700 || doc.Transactions[1].Balance != 750 {
+		t.Errorf("некорректный нарастающий баланс: %+v", doc.Transactions)
+	}
+
+	// 2. NDJSON: по записи на строку
+	period.Set("format", "ndjson")
+	resp = statement(walletID, period)
+	var lines int
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines++
+	}
+	_ = resp.Body.Close()
+	if lines != 4 {
+		t.Errorf("ожидалось 4 строки NDJSON выписки, получено %d", lines)
+	}
+
+	// 3. CSV: заголовок, начальный баланс, операции и итоговый баланс
+	period.Set("format", "csv")
+	resp = statement(walletID, period)
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	_ = resp.Body.Close()
+	if err != nil || len(rows) != 5 {
+		t.Fatalf("ожидались 5 строк CSV выписки, получены %v и %v", rows, err)
+	}
+	if rows[1][0] != "OPENING_BALANCE" || rows[1][7] != "1000" || rows[4][0] != "CLOSING_BALANCE" || rows[4][7] != "750" {
+		t.Errorf("некорректные балансы CSV выписки: %v", rows)
+	}
+	if !strings.HasSuffix(resp.Header.Get("Content-Disposition"), ".csv") {
+		t.Errorf("ожидалось имя файла .csv, получен %q", resp.Header.Get("Content-Disposition"))
+	}
+
+	// 4. Ошибки: пустой период, неизвестный формат и несуществующий кошелёк
+	resp = statement(walletID, url.Values{"from": {from.Format(time.RFC3339Nano)}, "to": {from.Format(time.RFC3339Nano)}})
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для пустого периода, получен %d", resp.StatusCode)
+	}
+	resp = statement(walletID, url.Values{"format": {"xml"}})
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для неизвестного формата, получен %d", resp.StatusCode)
+	}
+	resp = statement("00000000-0000-0000-0000-000000000001", url.Values{})
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ожидался статус 404 для несуществующего кошелька, получен %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestWalletStatusIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	walletID := createFundedWallet(t, baseURL, 1000)
+	targetID := createFundedWallet(t, baseURL, 0)
+
+	changeStatus := func(walletID string, payload map[string]any) int {
+		t.Helper()
+		body, _ := json.Marshal(payload)
+		resp, err := http.Post(baseURL+"/api/v1/admin/wallets/"+walletID+"/status", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при смене статуса: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	operation := func(opType string, amount int64) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": opType, "amount": amount})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при операции: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// 1. Причина обязательна
+	if status := changeStatus(walletID, map[string]any{"status": "FROZEN", "reason": ""}); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 без причины, получен %d", status)
+	}
+
+	// 2. Замороженный кошелёк нельзя пополнять и списывать
+	if status := changeStatus(walletID, map[string]any{"status": "FROZEN", "reason": "подозрительная активность"}); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при заморозке, получен %d", status)
+	}
+	if status := operation("DEPOSIT", 100); status != http.StatusLocked {
+		t.Errorf("ожидался статус 423 при пополнении замороженного кошелька, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 100); status != http.StatusLocked {
+		t.Errorf("ожидался статус 423 при списании с замороженного кошелька, получен %d", status)
+	}
+
+	// 3. После разморозки операции снова доступны
+	if status := changeStatus(walletID, map[string]any{"status": "ACTIVE", "reason": "проверка пройдена"}); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при разморозке, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 100); status != http.StatusOK {
+		t.Errorf("ожидался статус 200 при списании после разморозки, получен %d", status)
+	}
+
+	// 4. Закрытие с остатком требует кошелька для перевода остатка
+	if status := changeStatus(walletID, map[string]any{"status": "CLOSED", "reason": "по заявлению"}); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при закрытии с остатком, получен %d", status)
+	}
+	if status := changeStatus(walletID, map[string]any{"status": "CLOSED", "reason": "по заявлению", "sweepToWalletId": targetID}); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 при закрытии с переводом остатка, получен %d", status)
+	}
+	if balance := getBalance(t, baseURL, walletID); balance != 0 {
+		t.Errorf("ожидался нулевой баланс закрытого кошелька, получен %d", balance)
+	}
+	if balance := getBalance(t, baseURL, targetID); balance != 900 {
+		t.Errorf("ожидался баланс 900 на кошельке для перевода остатка, получен %d", balance)
+	}
+
+	// 5. Закрытие окончательно
+	if status := operation("DEPOSIT", 100); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при пополнении закрытого кошелька, получен %d", status)
+	}
+	if status := changeStatus(walletID, map[string]any{"status": "ACTIVE", "reason": "ошибка"}); status != http.StatusConflict {
+		t.Errorf("ожидался статус 409 при открытии закрытого кошелька, получен %d", status)
+	}
+}
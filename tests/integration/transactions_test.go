@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestWalletTransactionsIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	resp, err := http.Post(baseURL+"/api/v1/wallets", "application/json", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		t.Fatalf("ошибка при создании кошелька: %v", err)
+	}
+	var createResp struct {
+		WalletId string `json:"walletId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа создания кошелька: %v", err)
+	}
+	_ = resp.Body.Close()
+	walletID := createResp.WalletId
+
+	// Три пополнения и одно списание
+	operations := []struct {
+		opType string
+		amount int64
+	}{
+		{"DEPOSIT", 1000},
+		{"DEPOSIT", 200},
+		{"WITHDRAW", 300},
+		{"DEPOSIT", 50},
+	}
+	for _, op := range operations {
+		body, _ := json.Marshal(map[string]interface{}{
+			"walletId":      walletID,
+			"operationType": op.opType,
+			"amount":        op.amount,
+		})
+		resp, err := http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при операции %s: %v", op.opType, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("ожидался статус 200, получен %d", resp.StatusCode)
+		}
+		_ = resp.Body.Close()
+	}
+
+	type transaction struct {
+		Type         string `json:"type"`
+		Amount       int64  `json:"amount"`
+		BalanceAfter int64  `json:"balanceAfter"`
+	}
+	type page struct {
+		Items      []transaction `json:"items"`
+		NextCursor string        `json:"nextCursor"`
+	}
+	fetch := func(query url.Values) page {
+		t.Helper()
+		resp, err := http.Get(baseURL + "/api/v1/wallets/" + walletID + "/transactions?" + query.Encode())
+		if err != nil {
+			t.Fatalf("ошибка при получении истории: %v", err)
+		}
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(resp.Body)
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("ожидался статус 200, получен %d, тело: %s", resp.StatusCode, string(body))
+		}
+		var p page
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			t.Fatalf("ошибка декодирования истории: %v", err)
+		}
+		return p
+	}
+
+	// Постраничный обход истории: от новых к старым
+	var all []transaction
+	query := url.Values{"limit": {"3"}}
+	for {
+		p := fetch(query)
+		all = append(all, p.Items...)
+		if p.NextCursor == "" {
+			break
+		}
+		query.Set("cursor", p.NextCursor)
+	}
+	if len(all) != len(operations) {
+		t.Fatalf("ожидалось %d записей, получено %d", len(operations), len(all))
+	}
+	if all[0].BalanceAfter != 950 {
+		t.Errorf("ожидался баланс 950 после последней операции, получен %d", all[0].BalanceAfter)
+	}
+	if all[1].Type != "WITHDRAW" || all[1].Amount != -300 {
+		t.Errorf("ожидалось списание -300, получено %+v", all[1])
+	}
+
+	// Фильтр по типу
+	withdrawals := fetch(url.Values{"type": {"WITHDRAW"}})
+	if len(withdrawals.Items) != 1 {
+		t.Errorf("ожидалось одно списание, получено %d", len(withdrawals.Items))
+	}
+
+	// Несуществующий кошелёк
+	resp, err = http.Get(baseURL + "/api/v1/wallets/00000000-0000-0000-0000-000000000001/transactions")
+	if err != nil {
+		t.Fatalf("ошибка при запросе истории: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ожидался статус 404, получен %d", resp.StatusCode)
+	}
+	_ = resp.Body.Close()
+}
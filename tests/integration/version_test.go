@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestWalletVersionPreconditionsIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	walletID := createFundedWallet(t, baseURL, 1000)
+
+	get := func(ifNoneMatch string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, baseURL+"/api/v1/wallets/"+walletID, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка при получении кошелька: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get("ETag")
+	}
+	operation := func(opType string, amount int64, ifMatch string) int {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"walletId": walletID, "operationType": opType, "amount": amount})
+		req, _ := http.NewRequest(http.MethodPost, baseURL+"/api/v1/wallet", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка при операции: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	setBuckets := func(buckets int) {
+		t.Helper()
+		body, _ := json.Marshal(map[string]any{"buckets": buckets})
+		req, _ := http.NewRequest(http.MethodPut, baseURL+"/api/v1/admin/wallets/"+walletID+"/balance-buckets", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка при смене числа корзин: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("ожидался статус 200 при смене числа корзин, получен %d", resp.StatusCode)
+		}
+	}
+
+	// 1. GET возвращает ETag, If-None-Match с ним - 304
+	status, etag := get("")
+	if status != http.StatusOK || etag == "" {
+		t.Fatalf("ожидался статус 200 с ETag, получены %d и %q", status, etag)
+	}
+	if status, _ := get(etag); status != http.StatusNotModified {
+		t.Errorf("ожидался статус 304 для текущего ETag, получен %d", status)
+	}
+	if status, _ := get("W/" + etag); status != http.StatusNotModified {
+		t.Errorf("If-None-Match сравнивает ETag слабо, ожидался статус 304, получен %d", status)
+	}
+
+	// 2. Изменение баланса меняет версию
+	if status := operation("DEPOSIT", 100, ""); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 для пополнения, получен %d", status)
+	}
+	status, current := get(etag)
+	if status != http.StatusOK || current == etag {
+		t.Fatalf("после пополнения ожидался статус 200 с новым ETag, получены %d и %q", status, current)
+	}
+
+	// 3. Списание с устаревшим ETag отклоняется, с текущим - выполняется
+	if status := operation("WITHDRAW", 100, etag); status != http.StatusPreconditionFailed {
+		t.Errorf("ожидался статус 412 для устаревшего ETag, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 100, "W/"+current); status != http.StatusPreconditionFailed {
+		t.Errorf("If-Match сравнивает ETag строго, ожидался статус 412, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 100, `"1", `+current); status != http.StatusOK {
+		t.Errorf("ожидался статус 200 для текущего ETag в списке, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 100, "*"); status != http.StatusOK {
+		t.Errorf("ожидался статус 200 для If-Match: *, получен %d", status)
+	}
+
+	// 4. Версия шардированного кошелька учитывает корзины и не меняется при их переносе
+	setBuckets(4)
+	_, etag = get("")
+	if status := operation("DEPOSIT", 50, ""); status != http.StatusOK {
+		t.Fatalf("ожидался статус 200 для пополнения корзины, получен %d", status)
+	}
+	_, current = get("")
+	if current == etag {
+		t.Fatalf("пополнение корзины должно менять ETag, получен прежний %q", current)
+	}
+	if status := operation("WITHDRAW", 50, etag); status != http.StatusPreconditionFailed {
+		t.Errorf("ожидался статус 412 для устаревшего ETag шардированного кошелька, получен %d", status)
+	}
+	if status := operation("WITHDRAW", 50, current); status != http.StatusOK {
+		t.Errorf("ожидался статус 200 для текущего ETag шардированного кошелька, получен %d", status)
+	}
+}
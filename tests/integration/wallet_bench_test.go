@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+)
+
+// BenchmarkHotWalletOperations измеряет пополнения и списания, параллельно идущие в один кошелёк:
+// все операции конкурируют за блокировку одной строки wallets, поэтому время удержания блокировки
+// определяет пропускную способность. Для сравнения реализаций бенчмарк запускается на обеих версиях
+// с -count и результаты сравниваются benchstat.
+func BenchmarkHotWalletOperations(b *testing.B) {
+	cfg := testConfig()
+	if err := postgres.RunMigrations(cfg); err != nil {
+		b.Fatalf("не удалось применить миграции: %v", err)
+	}
+	pool, err := postgres.NewPool(cfg)
+	if err != nil {
+		b.Fatalf("не удалось подключиться к базе: %v", err)
+	}
+	defer pool.Close()
+	repo := postgres.NewWalletRepository(pool, postgres.NewRetryPolicy(cfg))
+	ctx := context.Background()
+
+	wallet, err := repo.CreateWallet(ctx, repository.NewWallet{Currency: "RUB"})
+	if err != nil {
+		b.Fatalf("ошибка создания кошелька: %v", err)
+	}
+	if _, err := repo.Deposit(ctx, repository.Operation{WalletID: wallet.ID, Amount: 1 << 40}); err != nil {
+		b.Fatalf("ошибка начального пополнения: %v", err)
+	}
+
+	benchmarks := []struct {
+		name string
+		op   func(ctx context.Context, op repository.Operation) (*repository.Transaction, error)
+	}{
+		{"Deposit", repo.Deposit},
+		{"Withdraw", repo.Withdraw},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetParallelism(8)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := bm.op(ctx, repository.Operation{WalletID: wallet.ID, Amount: 1}); err != nil {
+						b.Errorf("ошибка операции: %v", err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWalletListIntegration(t *testing.T) {
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	// Отдельный владелец изолирует кошельки теста от остальных данных в БД
+	ownerID := "list-" + uuid.NewString()
+	for i, amount := range []int64{300, 100, 200} {
+		body, _ := json.Marshal(map[string]any{"ownerId": ownerID, "externalRef": string(rune('a' + i))})
+		resp, err := http.Post(baseURL+"/api/v1/wallets", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при создании кошелька: %v", err)
+		}
+		var created struct {
+			WalletId string `json:"walletId"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&created)
+		_ = resp.Body.Close()
+
+		body, _ = json.Marshal(map[string]any{"walletId": created.WalletId, "operationType": "DEPOSIT", "amount": amount})
+		resp, err = http.Post(baseURL+"/api/v1/wallet", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("ошибка при депозите: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	type listResponse struct {
+		Items []struct {
+			Balance int64 `json:"balance"`
+		} `json:"items"`
+		NextCursor string `json:"nextCursor"`
+	}
+	list := func(params url.Values) listResponse {
+		t.Helper()
+		params.Set("ownerId", ownerID)
+		resp, err := http.Get(baseURL + "/api/v1/wallets?" + params.Encode())
+		if err != nil {
+			t.Fatalf("ошибка при получении списка кошельков: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("ожидался статус 200, получен %d", resp.StatusCode)
+		}
+		var page listResponse
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			t.Fatalf("ошибка декодирования списка кошельков: %v", err)
+		}
+		return page
+	}
+
+	// 1. Сортировка по балансу с пагинацией
+	page := list(url.Values{"sort": {"balance"}, "order": {"asc"}, "limit": {"2"}})
+	if len(page.Items) != 2 || page.Items[0].Balance != 100 || page.Items[1].Balance != 200 || page.NextCursor == "" {
+		t.Fatalf("некорректная первая страница: %+v", page)
+	}
+	page = list(url.Values{"sort": {"balance"}, "order": {"asc"}, "limit": {"2"}, "cursor": {page.NextCursor}})
+	if len(page.Items) != 1 || page.Items[0].Balance != 300 || page.NextCursor != "" {
+		t.Errorf("некорректная вторая страница: %+v", page)
+	}
+
+	// 2. Фильтр по диапазону баланса
+	page = list(url.Values{"minBalance": {"150"}, "maxBalance": {"300"}})
+	if len(page.Items) != 2 {
+		t.Errorf("ожидалось 2 кошелька с балансом от 150 до 300, получено %d", len(page.Items))
+	}
+
+	// 3. Некорректная сортировка
+	resp, err := http.Get(baseURL + "/api/v1/wallets?sort=id")
+	if err != nil {
+		t.Fatalf("ошибка запроса: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для неизвестной сортировки, получен %d", resp.StatusCode)
+	}
+}
@@ -50,10 +50,23 @@ func TestWalletIntegration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ошибка при депозите: %v", err)
 	}
-	if resp.StatusCode != http.StatusNoContent {
-		t.Errorf("ожидался статус 204, получен %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("ожидался статус 200, получен %d", resp.StatusCode)
+	}
+	var depositResp struct {
+		OperationId string `json:"operationId"`
+		Balance     int64  `json:"balance"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&depositResp); err != nil {
+		t.Fatalf("ошибка декодирования ответа депозита: %v", err)
 	}
 	_ = resp.Body.Close()
+	if depositResp.OperationId == "" {
+		t.Error("operationId не был возвращен в ответе на депозит")
+	}
+	if depositResp.Balance != 1000 {
+		t.Errorf("ожидался баланс 1000 в ответе на депозит, получен %d", depositResp.Balance)
+	}
 
 	// 2. Получение баланса
 	resp, err = http.Get(baseURL + "/api/v1/wallets/" + walletID)
@@ -87,6 +100,8 @@ func TestWalletIntegration(t *testing.T) {
 	withdrawBody, _ := json.Marshal(withdrawReq)
 	req, _ := http.NewRequest("POST", baseURL+"/api/v1/wallet", bytes.NewReader(withdrawBody))
 	req.Header.Set("Content-Type", "application/json")
+	// Старые клиенты могут по-прежнему получать 204 без тела
+	req.Header.Set("Prefer", "return=minimal")
 	resp, err = http.DefaultClient.Do(req)
 	if err != nil {
 		t.Fatalf("ошибка при списании: %v", err)
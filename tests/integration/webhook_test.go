@@ -0,0 +1,171 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/webhook"
+)
+
+// webhookRequest - запрос, принятый тестовым получателем
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func TestWebhookIntegration(t *testing.T) {
+	// Быстрые повторы, чтобы доставка успела перейти в DEAD за время теста
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "2")
+	t.Setenv("WEBHOOK_RETRY_BASE_DELAY", "100ms")
+	baseURL, cleanup := testServer(t)
+	defer cleanup()
+
+	received := make(chan webhookRequest, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- webhookRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	var failing atomic.Bool
+	failing.Store(true)
+	flakyReceiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer flakyReceiver.Close()
+
+	call := func(method, path string, payload any, out any) int {
+		t.Helper()
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewReader(data)
+		}
+		req, _ := http.NewRequest(method, baseURL+path, body)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("ошибка запроса %s %s: %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				t.Fatalf("ошибка декодирования ответа %s %s: %v", method, path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+	type subscription struct {
+		ID     string  `json:"id"`
+		Secret *string `json:"secret"`
+	}
+
+	walletID := createFundedWallet(t, baseURL, 0)
+
+	// 1. Ключ подписи возвращается только при создании подписки
+	var sub subscription
+	status := call(http.MethodPost, "/api/v1/webhooks",
+		map[string]any{"url": receiver.URL, "eventTypes": []string{"Deposited"}, "walletId": walletID}, &sub)
+	if status != http.StatusCreated || sub.Secret == nil {
+		t.Fatalf("ожидалась подписка с ключом и статус 201, получены %d и %+v", status, sub)
+	}
+	defer call(http.MethodDelete, "/api/v1/webhooks/"+sub.ID, nil, nil)
+	var fetched subscription
+	if status := call(http.MethodGet, "/api/v1/webhooks/"+sub.ID, nil, &fetched); status != http.StatusOK || fetched.Secret != nil {
+		t.Errorf("ключ подписи не должен возвращаться при чтении подписки, получены %d и %+v", status, fetched)
+	}
+	if status := call(http.MethodPost, "/api/v1/webhooks", map[string]any{"url": "ftp://example.com"}, nil); status != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для адреса не http(s), получен %d", status)
+	}
+
+	var flaky subscription
+	if status := call(http.MethodPost, "/api/v1/webhooks", map[string]any{"url": flakyReceiver.URL, "walletId": walletID}, &flaky); status != http.StatusCreated {
+		t.Fatalf("ожидался статус 201 при создании подписки, получен %d", status)
+	}
+	defer call(http.MethodDelete, "/api/v1/webhooks/"+flaky.ID, nil, nil)
+
+	// 2. Пополнение доставляется подписанным запросом
+	if status := call(http.MethodPost, "/api/v1/wallet",
+		map[string]any{"walletId": walletID, "operationType": "DEPOSIT", "amount": 100}, nil); status != http.StatusOK {
+		t.Fatalf("пополнение завершилось со статусом %d", status)
+	}
+	select {
+	case req := <-received:
+		err := webhook.Verify(*sub.Secret, req.header.Get(webhook.HeaderTimestamp), req.header.Get(webhook.HeaderSignature),
+			req.body, 5*time.Minute, time.Now())
+		if err != nil {
+			t.Errorf("подпись запроса не прошла проверку: %v", err)
+		}
+		var event events.Event
+		if err := json.Unmarshal(req.body, &event); err != nil {
+			t.Fatalf("ошибка декодирования события: %v", err)
+		}
+		if event.Type != events.Deposited || event.WalletID.String() != walletID {
+			t.Errorf("неожиданное событие: %+v", event)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("событие не доставлено за 10 секунд")
+	}
+
+	// 3. После исчерпания попыток доставка переходит в DEAD
+	type deliveryList struct {
+		Items []struct {
+			ID       string `json:"id"`
+			Status   string `json:"status"`
+			Attempts int    `json:"attempts"`
+		} `json:"items"`
+	}
+	waitDeliveries := func(subscriptionID, status string, count int) deliveryList {
+		t.Helper()
+		deadline := time.Now().Add(15 * time.Second)
+		for {
+			var list deliveryList
+			if code := call(http.MethodGet, "/api/v1/webhooks/"+subscriptionID+"/deliveries?status="+status, nil, &list); code != http.StatusOK {
+				t.Fatalf("ошибка получения доставок, статус %d", code)
+			}
+			if len(list.Items) >= count {
+				return list
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("не дождались %d доставок в статусе %s", count, status)
+			}
+			time.Sleep(200 * time.Millisecond)
+		}
+	}
+	// Получатель второй подписки отвечает 503 на каждую попытку
+	dead := waitDeliveries(flaky.ID, "DEAD", 1)
+	if dead.Items[0].Attempts != 2 {
+		t.Errorf("ожидалось 2 попытки до перевода в DEAD, получено %d", dead.Items[0].Attempts)
+	}
+
+	// 4. Повторная отправка ставит доставку в очередь заново
+	failing.Store(false)
+	var replay struct {
+		Replayed int64 `json:"replayed"`
+	}
+	if status := call(http.MethodPost, "/api/v1/webhooks/"+flaky.ID+"/deliveries/replay",
+		map[string]any{"deliveryIds": []string{dead.Items[0].ID}}, &replay); status != http.StatusOK || replay.Replayed != 1 {
+		t.Fatalf("ожидалась повторная отправка 1 доставки, получены %d и %d", status, replay.Replayed)
+	}
+	waitDeliveries(flaky.ID, "DELIVERED", 1)
+
+	// 5. Удалённая подписка недоступна
+	if status := call(http.MethodDelete, "/api/v1/webhooks/"+flaky.ID, nil, nil); status != http.StatusNoContent {
+		t.Errorf("ожидался статус 204 при удалении подписки, получен %d", status)
+	}
+	if status := call(http.MethodGet, "/api/v1/webhooks/"+flaky.ID, nil, nil); status != http.StatusNotFound {
+		t.Errorf("ожидался статус 404 для удалённой подписки, получен %d", status)
+	}
+}
@@ -0,0 +1,44 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devopesik/wallet-basic-operations/internal/apimodel"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+)
+
+func TestNewIdempotencyKey_InvalidKey(t *testing.T) {
+	req := generated.WalletOperationRequest{WalletId: testWalletID, OperationType: generated.OperationTypeDEPOSIT, Amount: 100}
+	for _, key := range []string{"", strings.Repeat("k", apimodel.MaxIdempotencyKeyLength+1)} {
+		if _, err := apimodel.NewIdempotencyKey(key, req); !errors.Is(err, apperrors.ErrInvalidIdempotencyKey) {
+			t.Errorf("ключ длиной %d: ожидалась ошибка ErrInvalidIdempotencyKey, получено %v", len(key), err)
+		}
+	}
+}
+
+func TestNewIdempotencyKey_Fingerprint(t *testing.T) {
+	req := generated.WalletOperationRequest{WalletId: testWalletID, OperationType: generated.OperationTypeDEPOSIT, Amount: 100}
+	first, err := apimodel.NewIdempotencyKey("top-up-1", req)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	same, err := apimodel.NewIdempotencyKey("top-up-1", req)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if first.Fingerprint != same.Fingerprint {
+		t.Error("отпечатки одинаковых запросов различаются")
+	}
+
+	req.Amount = 200
+	other, err := apimodel.NewIdempotencyKey("top-up-1", req)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if first.Fingerprint == other.Fingerprint {
+		t.Error("отпечатки запросов с разной суммой совпадают")
+	}
+}
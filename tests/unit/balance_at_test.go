@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWalletService_GetBalanceAt(t *testing.T) {
+	repo := new(MockWalletRepository)
+	at := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)
+	expected := &repository.HistoricalBalance{WalletID: testWalletID, Currency: "RUB", At: at, Balance: 1500}
+	repo.On("GetBalanceAt", mock.Anything, testWalletID, at).Return(expected, nil)
+	svc := service.NewWalletService(repo)
+
+	balance, err := svc.GetBalanceAt(context.Background(), testWalletID, at)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if balance != expected {
+		t.Errorf("ожидался баланс %+v, получен %+v", expected, balance)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_GetBalanceAt_Future(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.GetBalanceAt(context.Background(), testWalletID, time.Now().Add(time.Hour))
+	if !errors.Is(err, apperrors.ErrInvalidBalanceTime) {
+		t.Errorf("ожидалась ошибка ErrInvalidBalanceTime, получено %v", err)
+	}
+	repo.AssertNotCalled(t, "GetBalanceAt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWalletService_GetBalanceAt_BeforeCreation(t *testing.T) {
+	repo := new(MockWalletRepository)
+	at := time.Now().Add(-24 * time.Hour)
+	repo.On("GetBalanceAt", mock.Anything, testWalletID, at).Return(nil, apperrors.ErrWalletNotFound)
+	svc := service.NewWalletService(repo)
+
+	if _, err := svc.GetBalanceAt(context.Background(), testWalletID, at); !errors.Is(err, apperrors.ErrWalletNotFound) {
+		t.Errorf("ожидалась ошибка ErrWalletNotFound, получено %v", err)
+	}
+}
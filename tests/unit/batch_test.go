@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+const testBatchMaxSize = 3
+
+func TestBatchService_ProcessBatch_Size(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewBatchService(repo, testBatchMaxSize)
+	op := repository.BatchOperation{Type: repository.TransactionDeposit, WalletID: testWalletID, Amount: 100}
+
+	for _, ops := range [][]repository.BatchOperation{nil, {op, op, op, op}} {
+		if _, err := svc.ProcessBatch(context.Background(), service.BatchAtomic, ops); !errors.Is(err, apperrors.ErrInvalidBatchSize) {
+			t.Errorf("для пакета из %d операций ожидалась ошибка ErrInvalidBatchSize, получена %v", len(ops), err)
+		}
+	}
+	if _, err := svc.ProcessBatch(context.Background(), "PARTIAL", []repository.BatchOperation{op}); !errors.Is(err, apperrors.ErrInvalidBatchMode) {
+		t.Errorf("ожидалась ошибка ErrInvalidBatchMode, получена %v", err)
+	}
+
+	repo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBatchService_ProcessBatch_AtomicInvalidItem(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewBatchService(repo, testBatchMaxSize)
+
+	ops := []repository.BatchOperation{
+		{Type: repository.TransactionDeposit, WalletID: testWalletID, Amount: 100},
+		{Type: repository.TransactionWithdraw, WalletID: testWalletID, Amount: 0},
+	}
+	_, err := svc.ProcessBatch(context.Background(), service.BatchAtomic, ops)
+
+	var itemErr *repository.BatchItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 1 || !errors.Is(err, apperrors.ErrInvalidAmount) {
+		t.Errorf("ожидалась ошибка ErrInvalidAmount операции 1, получена %v", err)
+	}
+	repo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBatchService_ProcessBatch_BestEffortKeepsIndexes(t *testing.T) {
+	repo := new(MockWalletRepository)
+	deposit := repository.BatchOperation{Type: repository.TransactionDeposit, WalletID: testWalletID, Amount: 100, Currency: "RUB"}
+	withdraw := repository.BatchOperation{Type: repository.TransactionWithdraw, WalletID: testWalletID, Amount: 500}
+	applied := []repository.BatchItemResult{
+		{Transaction: &repository.Transaction{WalletID: testWalletID, Amount: 100, BalanceAfter: 100}},
+		{Err: apperrors.ErrInsufficientFunds},
+	}
+	repo.On("ApplyBatch", mock.Anything, []repository.BatchOperation{deposit, withdraw}, false).Return(applied, nil)
+	svc := service.NewBatchService(repo, testBatchMaxSize)
+
+	results, err := svc.ProcessBatch(context.Background(), service.BatchBestEffort, []repository.BatchOperation{
+		{Type: "TRANSFER", WalletID: testWalletID, Amount: 1},
+		{Type: repository.TransactionDeposit, WalletID: testWalletID, Amount: 100, Currency: "rub"},
+		withdraw,
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("ожидалось 3 результата, получено %d", len(results))
+	}
+	if !errors.Is(results[0].Err, apperrors.ErrInvalidOperationType) {
+		t.Errorf("операция 0: ожидалась ошибка ErrInvalidOperationType, получена %v", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Transaction.BalanceAfter != 100 {
+		t.Errorf("операция 1: ожидалось успешное пополнение, получено %+v", results[1])
+	}
+	if !errors.Is(results[2].Err, apperrors.ErrInsufficientFunds) {
+		t.Errorf("операция 2: ожидалась ошибка ErrInsufficientFunds, получена %v", results[2].Err)
+	}
+
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,40 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWalletService_SetBalanceBuckets(t *testing.T) {
+	repo := new(MockWalletRepository)
+	expected := &repository.Wallet{ID: testWalletID, BalanceBuckets: 16}
+	repo.On("SetBalanceBuckets", mock.Anything, testWalletID, 16).Return(expected, nil)
+	svc := service.NewWalletService(repo)
+
+	wallet, err := svc.SetBalanceBuckets(context.Background(), testWalletID, 16)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if wallet.BalanceBuckets != 16 {
+		t.Errorf("ожидалось 16 корзин, получено %d", wallet.BalanceBuckets)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_SetBalanceBuckets_OutOfRange(t *testing.T) {
+	for _, buckets := range []int{-1, repository.MaxBalanceBuckets + 1} {
+		repo := new(MockWalletRepository)
+		svc := service.NewWalletService(repo)
+
+		if _, err := svc.SetBalanceBuckets(context.Background(), testWalletID, buckets); !errors.Is(err, apperrors.ErrInvalidBalanceBuckets) {
+			t.Errorf("%d корзин: ожидалась ErrInvalidBalanceBuckets, получено %v", buckets, err)
+		}
+		repo.AssertNotCalled(t, "SetBalanceBuckets")
+	}
+}
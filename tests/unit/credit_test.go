@@ -0,0 +1,63 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWallet_AvailableWithCredit(t *testing.T) {
+	cases := map[string]struct {
+		wallet     repository.Wallet
+		available  int64
+		creditUsed int64
+	}{
+		"без кредита":                    {repository.Wallet{Balance: 500, Held: 200}, 300, 0},
+		"кредит не использован":          {repository.Wallet{Balance: 500, Held: 200, CreditLimit: 1000}, 1300, 0},
+		"отрицательный баланс":           {repository.Wallet{Balance: -300, CreditLimit: 1000}, 700, 300},
+		"блокировка сверх баланса":       {repository.Wallet{Balance: 100, Held: 400, CreditLimit: 1000}, 700, 300},
+		"кредитный лимит исчерпан":       {repository.Wallet{Balance: -1000, CreditLimit: 1000}, 0, 1000},
+		"блокировки при нулевом балансе": {repository.Wallet{Held: 250, CreditLimit: 250}, 0, 250},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.wallet.Available(); got != tc.available {
+				t.Errorf("ожидался доступный баланс %d, получен %d", tc.available, got)
+			}
+			if got := tc.wallet.CreditUsed(); got != tc.creditUsed {
+				t.Errorf("ожидался использованный кредит %d, получен %d", tc.creditUsed, got)
+			}
+		})
+	}
+}
+
+func TestWalletService_SetCreditLimit(t *testing.T) {
+	repo := new(MockWalletRepository)
+	expected := &repository.Wallet{ID: testWalletID, CreditLimit: 5000}
+	repo.On("SetCreditLimit", mock.Anything, testWalletID, int64(5000)).Return(expected, nil)
+	svc := service.NewWalletService(repo)
+
+	wallet, err := svc.SetCreditLimit(context.Background(), testWalletID, 5000)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if wallet.CreditLimit != 5000 {
+		t.Errorf("ожидался кредитный лимит 5000, получен %d", wallet.CreditLimit)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_SetCreditLimit_Negative(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	if _, err := svc.SetCreditLimit(context.Background(), testWalletID, -1); !errors.Is(err, apperrors.ErrInvalidCreditLimit) {
+		t.Errorf("ожидалась ErrInvalidCreditLimit, получено %v", err)
+	}
+	repo.AssertNotCalled(t, "SetCreditLimit")
+}
@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devopesik/wallet-basic-operations/internal/currency"
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCurrency_Lookup(t *testing.T) {
+	cases := map[string]struct {
+		code     string
+		exponent int
+	}{
+		"RUB": {"RUB", 2},
+		"usd": {"USD", 2},
+		"KZT": {"KZT", 2},
+		"JPY": {"JPY", 0},
+		"KWD": {"KWD", 3},
+	}
+	for input, want := range cases {
+		c, ok := currency.Lookup(input)
+		if !ok {
+			t.Errorf("валюта %q должна быть в реестре", input)
+			continue
+		}
+		if c.Code != want.code || c.Exponent != want.exponent {
+			t.Errorf("для %q ожидалось %+v, получено %+v", input, want, c)
+		}
+	}
+
+	for _, code := range []string{"", "RU", "XXX", "XAU", "RUBL"} {
+		if _, ok := currency.Lookup(code); ok {
+			t.Errorf("код %q не должен проходить валидацию", code)
+		}
+	}
+}
+
+func TestWalletService_CreateWallet_WithCurrency(t *testing.T) {
+	repo := new(MockWalletRepository)
+	expectedWallet := &repository.Wallet{ID: testWalletID, Currency: "KZT"}
+	repo.On("CreateWallet", mock.Anything, repository.NewWallet{Currency: "KZT"}).Return(expectedWallet, nil)
+	svc := service.NewWalletService(repo)
+
+	wallet, err := svc.CreateWallet(context.Background(), repository.NewWallet{Currency: "kzt"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if wallet.Currency != "KZT" {
+		t.Errorf("ожидалась валюта KZT, получена %s", wallet.Currency)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_CreateWallet_InvalidCurrency(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.CreateWallet(context.Background(), repository.NewWallet{Currency: "ABC"})
+	if !errors.Is(err, apperrors.ErrInvalidCurrency) {
+		t.Errorf("ожидалась ошибка некорректной валюты, получена %v", err)
+	}
+
+	repo.AssertNotCalled(t, "CreateWallet", mock.Anything, mock.Anything)
+}
+
+func TestWalletService_Deposit_CurrencyMismatch(t *testing.T) {
+	repo := new(MockWalletRepository)
+	op := repository.Operation{WalletID: testWalletID, Amount: 100, Currency: "USD"}
+	repo.On("Deposit", mock.Anything, op).Return(nil, apperrors.ErrCurrencyMismatch)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 100, Currency: "usd"})
+	if !errors.Is(err, apperrors.ErrCurrencyMismatch) {
+		t.Errorf("ожидалась ошибка несовпадения валюты, получена %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_Withdraw_InvalidCurrency(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.Withdraw(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 100, Currency: "EURO"})
+	if !errors.Is(err, apperrors.ErrInvalidCurrency) {
+		t.Errorf("ожидалась ошибка некорректной валюты, получена %v", err)
+	}
+
+	repo.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,237 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+	// pending - события, которые RelayOutbox передаёт publish при очередном вызове
+	pending [][]repository.OutboxEvent
+}
+
+func (m *MockOutboxRepository) RelayOutbox(ctx context.Context, limit int, publish func(repository.OutboxEvent) error) (int, error) {
+	args := m.Called(ctx, limit)
+	if len(m.pending) == 0 {
+		return 0, args.Error(0)
+	}
+	batch := m.pending[0]
+	m.pending = m.pending[1:]
+	published := 0
+	for _, e := range batch {
+		if err := publish(e); err != nil {
+			continue
+		}
+		published++
+	}
+	return published, args.Error(0)
+}
+
+func (m *MockOutboxRepository) DeletePublishedOutboxEvents(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// recordingPublisher запоминает доставленные события и отклоняет события из failing
+type recordingPublisher struct {
+	published []events.Event
+	failing   map[uuid.UUID]bool
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, event events.Event) error {
+	if p.failing[event.ID] {
+		return errors.New("получатель недоступен")
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func testOutboxEvent(sequence int64, eventType events.Type) repository.OutboxEvent {
+	return repository.OutboxEvent{
+		Sequence:  sequence,
+		EventID:   uuid.New(),
+		WalletID:  testWalletID,
+		Type:      string(eventType),
+		Payload:   []byte(`{"amount":100}`),
+		CreatedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRelay_RelayPending_DrainsFullBatches(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	repo.pending = [][]repository.OutboxEvent{
+		{testOutboxEvent(1, events.WalletCreated), testOutboxEvent(2, events.Deposited)},
+		{testOutboxEvent(3, events.Withdrawn)},
+	}
+	repo.On("RelayOutbox", mock.Anything, 2).Return(nil)
+	publisher := &recordingPublisher{}
+
+	published, err := events.NewRelay(repo, publisher, 2).RelayPending(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	// Полный пакет означает, что в очереди могут быть ещё события
+	if published != 3 || len(publisher.published) != 3 {
+		t.Fatalf("ожидалась доставка 3 событий, доставлено %d", published)
+	}
+	repo.AssertNumberOfCalls(t, "RelayOutbox", 2)
+
+	first := publisher.published[0]
+	if first.Sequence != 1 || first.Type != events.WalletCreated || first.WalletID != testWalletID ||
+		!first.OccurredAt.Equal(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)) || string(first.Payload) != `{"amount":100}` {
+		t.Errorf("неожиданное событие: %+v", first)
+	}
+}
+
+func TestRelay_RelayPending_StopsOnFailures(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	failing := testOutboxEvent(1, events.Deposited)
+	repo.pending = [][]repository.OutboxEvent{
+		{failing, testOutboxEvent(2, events.Deposited)},
+		{testOutboxEvent(3, events.Deposited)},
+	}
+	repo.On("RelayOutbox", mock.Anything, 2).Return(nil)
+	publisher := &recordingPublisher{failing: map[uuid.UUID]bool{failing.EventID: true}}
+
+	published, err := events.NewRelay(repo, publisher, 2).RelayPending(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	// Неполный пакет из-за ошибки доставки откладывает остальное до следующего запуска
+	if published != 1 {
+		t.Errorf("ожидалась доставка 1 события, доставлено %d", published)
+	}
+	repo.AssertNumberOfCalls(t, "RelayOutbox", 1)
+}
+
+func TestRelay_RelayPending_RepositoryError(t *testing.T) {
+	repo := new(MockOutboxRepository)
+	dbErr := errors.New("connection lost")
+	repo.On("RelayOutbox", mock.Anything, 10).Return(dbErr)
+
+	if _, err := events.NewRelay(repo, &recordingPublisher{}, 10).RelayPending(context.Background()); !errors.Is(err, dbErr) {
+		t.Errorf("ожидалась ошибка репозитория, получено %v", err)
+	}
+}
+
+func TestTypeForTransaction(t *testing.T) {
+	cases := map[repository.TransactionType]events.Type{
+		repository.TransactionDeposit:     events.Deposited,
+		repository.TransactionWithdraw:    events.Withdrawn,
+		repository.TransactionTransferOut: events.TransferSent,
+		repository.TransactionTransferIn:  events.TransferReceived,
+		repository.TransactionHoldCapture: events.HoldCaptured,
+		repository.TransactionAdjustment:  events.BalanceAdjusted,
+	}
+	for txType, expected := range cases {
+		if got := events.TypeForTransaction(txType); got != expected {
+			t.Errorf("для %s ожидался тип события %s, получен %s", txType, expected, got)
+		}
+	}
+}
+
+func testEvent() events.Event {
+	return events.FromOutbox(testOutboxEvent(7, events.Deposited))
+}
+
+func TestWriterPublisher_WritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := events.NewWriterPublisher(&buf)
+	first, second := testEvent(), testEvent()
+
+	for _, e := range []events.Event{first, second} {
+		if err := publisher.Publish(context.Background(), e); err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ожидалось 2 строки, получено %d: %q", len(lines), buf.String())
+	}
+	var decoded events.Event
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		t.Fatalf("строка не является JSON: %v", err)
+	}
+	if decoded.ID != second.ID || decoded.Sequence != 7 || decoded.Type != events.Deposited {
+		t.Errorf("неожиданное событие: %+v", decoded)
+	}
+}
+
+func TestFilePublisher_AppendsEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	event := testEvent()
+
+	// Повторное открытие дописывает в конец файла, а не перезаписывает его
+	for i := 0; i < 2; i++ {
+		publisher, err := events.NewFilePublisher(path)
+		if err != nil {
+			t.Fatalf("не удалось создать публикатор: %v", err)
+		}
+		if err := publisher.Publish(context.Background(), event); err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+		if err := publisher.Close(); err != nil {
+			t.Fatalf("ошибка закрытия файла: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ошибка чтения файла: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("ожидалось 2 события в файле, получено %d", lines)
+	}
+}
+
+func TestHTTPPublisher_Publish(t *testing.T) {
+	event := testEvent()
+	var received events.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" ||
+			r.Header.Get("X-Event-Id") != event.ID.String() || r.Header.Get("X-Event-Type") != string(events.Deposited) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if err := events.NewHTTPPublisher(server.URL, time.Second).Publish(context.Background(), event); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if received.ID != event.ID {
+		t.Errorf("получатель получил событие %s, ожидалось %s", received.ID, event.ID)
+	}
+}
+
+func TestHTTPPublisher_Publish_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := events.NewHTTPPublisher(server.URL, time.Second).Publish(context.Background(), testEvent()); err == nil {
+		t.Error("ответ 503 не должен считаться доставкой")
+	}
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestWalletService_GetOrCreateWallet_Created(t *testing.T) {
+	repo := new(MockWalletRepository)
+	params := repository.NewWallet{Currency: "RUB", OwnerID: "users", ExternalRef: "user-42"}
+	created := &repository.Wallet{ID: testWalletID, Currency: "RUB", OwnerID: "users", ExternalRef: strPtr("user-42")}
+	repo.On("CreateWallet", mock.Anything, params).Return(created, nil)
+	svc := service.NewWalletService(repo)
+
+	wallet, isNew, err := svc.GetOrCreateWallet(context.Background(), repository.NewWallet{OwnerID: "users", ExternalRef: "user-42"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !isNew || wallet.ID != testWalletID {
+		t.Errorf("ожидался новый кошелёк %s, получен %s (created=%v)", testWalletID, wallet.ID, isNew)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_GetOrCreateWallet_ReturnsExisting(t *testing.T) {
+	repo := new(MockWalletRepository)
+	params := repository.NewWallet{Currency: "RUB", OwnerID: "users", ExternalRef: "user-42"}
+	existing := &repository.Wallet{ID: testWalletID, Balance: 500, Currency: "RUB", OwnerID: "users", ExternalRef: strPtr("user-42")}
+	repo.On("CreateWallet", mock.Anything, params).Return(nil, apperrors.ErrWalletAlreadyExists)
+	repo.On("FindWalletByExternalRef", mock.Anything, "users", "user-42").Return(existing, nil)
+	svc := service.NewWalletService(repo)
+
+	wallet, isNew, err := svc.GetOrCreateWallet(context.Background(), params)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if isNew || wallet.Balance != 500 {
+		t.Errorf("ожидался существующий кошелёк с балансом 500, получен %d (created=%v)", wallet.Balance, isNew)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_GetOrCreateWallet_ExistingMismatch(t *testing.T) {
+	repo := new(MockWalletRepository)
+	params := repository.NewWallet{ID: testWalletID, Currency: "USD"}
+	existing := &repository.Wallet{ID: testWalletID, Currency: "RUB"}
+	repo.On("CreateWallet", mock.Anything, params).Return(nil, apperrors.ErrWalletAlreadyExists)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(existing, nil)
+	svc := service.NewWalletService(repo)
+
+	_, _, err := svc.GetOrCreateWallet(context.Background(), params)
+	if !errors.Is(err, apperrors.ErrWalletAlreadyExists) {
+		t.Errorf("ожидалась ошибка ErrWalletAlreadyExists, получена %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_CreateWallet_ExternalRefTooLong(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.CreateWallet(context.Background(), repository.NewWallet{ExternalRef: strings.Repeat("a", 256)})
+	if !errors.Is(err, apperrors.ErrInvalidExternalRef) {
+		t.Errorf("ожидалась ошибка ErrInvalidExternalRef, получена %v", err)
+	}
+	repo.AssertNotCalled(t, "CreateWallet", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,237 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/groupcommit"
+	"github.com/devopesik/wallet-basic-operations/internal/metrics"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+// newDepositCoalescer создаёт группировщик пополнений поверх repo
+func newDepositCoalescer(t *testing.T, repo *MockWalletRepository, window time.Duration, maxBatch int) *groupcommit.DepositCoalescer {
+	t.Helper()
+	coalescer, err := groupcommit.NewDepositCoalescer(repo, window, maxBatch, time.Minute)
+	if err != nil {
+		t.Fatalf("ошибка создания группировщика: %v", err)
+	}
+	return coalescer
+}
+
+func TestDepositCoalescer_GroupsConcurrentDeposits(t *testing.T) {
+	repo := new(MockWalletRepository)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(&repository.Wallet{ID: testWalletID}, nil).Once()
+	// Порядок операций в группе не определён, поэтому результаты заполняются по пришедшим операциям;
+	// пополнение на 13 завершается ошибкой, остальные - успешно
+	results := make([]repository.BatchItemResult, 5)
+	repo.On("ApplyBatch", mock.Anything, mock.MatchedBy(func(ops []repository.BatchOperation) bool {
+		return len(ops) == 5
+	}), false).Run(func(args mock.Arguments) {
+		for i, op := range args.Get(1).([]repository.BatchOperation) {
+			if op.Amount == 13 {
+				results[i].Err = apperrors.ErrWalletFrozen
+				continue
+			}
+			results[i].Transaction = &repository.Transaction{WalletID: op.WalletID, Amount: op.Amount}
+		}
+	}).Return(results, nil).Once()
+	coalescer := newDepositCoalescer(t, repo, time.Hour, 5)
+
+	var wg sync.WaitGroup
+	for _, amount := range []int64{10, 11, 12, 13, 14} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := coalescer.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: amount})
+			if amount == 13 {
+				if !errors.Is(err, apperrors.ErrWalletFrozen) {
+					t.Errorf("ожидалась ошибка операции, получено %v", err)
+				}
+				return
+			}
+			if err != nil || tx.Amount != amount {
+				t.Errorf("неожиданный результат пополнения %d: %+v, %v", amount, tx, err)
+			}
+		}()
+	}
+	wg.Wait()
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_FlushesAfterWindow(t *testing.T) {
+	repo := new(MockWalletRepository)
+	op := repository.Operation{WalletID: testWalletID, Amount: 100}
+	repo.On("Deposit", mock.Anything, op).Return(&repository.Transaction{Amount: 100}, nil).Once()
+	coalescer := newDepositCoalescer(t, repo, 2*time.Millisecond, 100)
+
+	tx, err := coalescer.Deposit(context.Background(), op)
+	if err != nil || tx.Amount != 100 {
+		t.Fatalf("неожиданный результат пополнения: %+v, %v", tx, err)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_IdempotentDepositBypassesGroup(t *testing.T) {
+	repo := new(MockWalletRepository)
+	op := repository.Operation{
+		WalletID:       testWalletID,
+		Amount:         100,
+		IdempotencyKey: &repository.IdempotencyKey{Key: "key-1", Fingerprint: "hash"},
+	}
+	repo.On("Deposit", mock.Anything, op).Return(&repository.Transaction{Amount: 100}, nil).Once()
+	coalescer := newDepositCoalescer(t, repo, time.Hour, 100)
+
+	if _, err := coalescer.Deposit(context.Background(), op); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_InvalidSettings(t *testing.T) {
+	repo := new(MockWalletRepository)
+	if _, err := groupcommit.NewDepositCoalescer(repo, time.Millisecond, 0, time.Second); err == nil {
+		t.Error("ожидалась ошибка для размера группы 0")
+	}
+	if _, err := groupcommit.NewDepositCoalescer(repo, -time.Millisecond, 100, time.Second); err == nil {
+		t.Error("ожидалась ошибка для отрицательного окна")
+	}
+	if _, err := groupcommit.NewDepositCoalescer(repo, time.Millisecond, 100, 0); err == nil {
+		t.Error("ожидалась ошибка для нулевого таймаута применения группы")
+	}
+}
+
+func TestDepositCoalescer_CancelBeforeFlush(t *testing.T) {
+	repo := new(MockWalletRepository)
+	coalescer := newDepositCoalescer(t, repo, time.Hour, 100)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err := coalescer.Deposit(ctx, repository.Operation{WalletID: testWalletID, Amount: 100})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ожидалась ошибка отмены, получено %v", err)
+	}
+	repo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything)
+}
+
+func TestDepositCoalescer_CancelAfterFlushWaitsForResult(t *testing.T) {
+	repo := new(MockWalletRepository)
+	started, release := make(chan struct{}), make(chan struct{})
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(&repository.Wallet{ID: testWalletID}, nil).Once()
+	repo.On("ApplyBatch", mock.Anything, mock.Anything, false).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return([]repository.BatchItemResult{
+		{Transaction: &repository.Transaction{Amount: 100}},
+		{Transaction: &repository.Transaction{Amount: 100}},
+	}, nil).Once()
+	coalescer := newDepositCoalescer(t, repo, time.Hour, 2)
+
+	// Запрос отменяется, когда группа уже применяется: пополнение будет зафиксировано,
+	// и клиент должен получить его результат, а не ошибку отмены
+	ctx, cancel := context.WithCancel(context.Background())
+	results := make(chan error, 2)
+	for _, c := range []context.Context{ctx, context.Background()} {
+		go func() {
+			tx, err := coalescer.Deposit(c, repository.Operation{WalletID: testWalletID, Amount: 100})
+			if err == nil && tx == nil {
+				err = errors.New("нет записи истории")
+			}
+			results <- err
+		}()
+	}
+	<-started
+	cancel()
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("ожидалось зафиксированное пополнение, получено %v", err)
+		}
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_ShardedWalletDepositsSeparately(t *testing.T) {
+	repo := new(MockWalletRepository)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(&repository.Wallet{ID: testWalletID, BalanceBuckets: 8}, nil).Once()
+	repo.On("Deposit", mock.Anything, mock.Anything).Return(&repository.Transaction{Amount: 100}, nil).Times(3)
+	coalescer := newDepositCoalescer(t, repo, time.Hour, 3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := coalescer.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 100}); err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	repo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_WalletReadErrorDepositsSeparately(t *testing.T) {
+	repo := new(MockWalletRepository)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(nil, apperrors.ErrWalletNotFound).Once()
+	repo.On("Deposit", mock.Anything, mock.Anything).Return(nil, apperrors.ErrWalletNotFound).Times(2)
+	coalescer := newDepositCoalescer(t, repo, time.Hour, 2)
+
+	// Без сведений о кошельке пополнения выполняются по отдельности и возвращают свои ошибки
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := coalescer.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 100})
+			if !errors.Is(err, apperrors.ErrWalletNotFound) {
+				t.Errorf("ожидалась ошибка ErrWalletNotFound, получено %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	repo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_FlushHasDeadline(t *testing.T) {
+	repo := new(MockWalletRepository)
+	op := repository.Operation{WalletID: testWalletID, Amount: 100}
+	repo.On("Deposit", mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok
+	}), op).Return(&repository.Transaction{Amount: 100}, nil).Once()
+	coalescer := newDepositCoalescer(t, repo, time.Millisecond, 100)
+
+	// Запрос без срока: срок транзакции группы задаёт группировщик
+	if _, err := coalescer.Deposit(context.Background(), op); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestDepositCoalescer_MetricsCountEveryTransaction(t *testing.T) {
+	repo := new(MockWalletRepository)
+	op := repository.Operation{WalletID: testWalletID, Amount: 100}
+	repo.On("Deposit", mock.Anything, op).Return(&repository.Transaction{Amount: 100}, nil).Once()
+	coalescer := newDepositCoalescer(t, repo, time.Millisecond, 100)
+	batches, deposits := metrics.DepositBatches.Value(), metrics.DepositsCoalesced.Value()
+
+	// Одиночное пополнение - тоже транзакция группировки: без него коэффициент был бы завышен
+	if _, err := coalescer.Deposit(context.Background(), op); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if got := metrics.DepositBatches.Value() - batches; got != 1 {
+		t.Errorf("ожидалась 1 транзакция, учтено %d", got)
+	}
+	if got := metrics.DepositsCoalesced.Value() - deposits; got != 1 {
+		t.Errorf("ожидалось 1 пополнение, учтено %d", got)
+	}
+}
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated/walletpb"
+	"github.com/devopesik/wallet-basic-operations/internal/grpcapi"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"кошелёк не найден", apperrors.ErrWalletNotFound, codes.NotFound},
+		{"некорректная сумма", apperrors.ErrInvalidAmount, codes.InvalidArgument},
+		{"кошелёк уже существует", apperrors.ErrWalletAlreadyExists, codes.AlreadyExists},
+		{"недостаточно средств", apperrors.ErrInsufficientFunds, codes.FailedPrecondition},
+		{"кошелёк заморожен", apperrors.ErrWalletFrozen, codes.FailedPrecondition},
+		{"повтор ключа с другим телом", apperrors.ErrIdempotencyKeyMismatch, codes.FailedPrecondition},
+		{"версия не совпала", apperrors.ErrVersionMismatch, codes.Aborted},
+		{"превышен лимит", apperrors.ErrLimitExceeded, codes.ResourceExhausted},
+		{"ошибка БД", apperrors.NewDatabaseError("пополнение", errors.New("соединение закрыто")), codes.Internal},
+		{"отмена запроса", apperrors.NewDatabaseError("пополнение", context.Canceled), codes.Canceled},
+		{"таймаут", fmt.Errorf("чтение кошелька: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"не AppError", errors.New("неизвестная ошибка"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := grpcapi.StatusCode(tt.err); code != tt.code {
+				t.Errorf("ожидался статус %s, получен %s", tt.code, code)
+			}
+		})
+	}
+}
+
+func TestGRPCServer_ProcessWalletOperation_ExpectedVersion(t *testing.T) {
+	repo := new(MockWalletRepository)
+	op := repository.Operation{WalletID: testWalletID, Amount: 200, ExpectedVersions: []int64{7}}
+	repo.On("Withdraw", mock.Anything, op).Return(nil, apperrors.ErrVersionMismatch)
+	server := grpcapi.NewServer(service.NewWalletService(repo), 0)
+
+	version := int64(7)
+	_, err := server.ProcessWalletOperation(context.Background(), &walletpb.ProcessWalletOperationRequest{
+		WalletId:        testWalletID.String(),
+		OperationType:   walletpb.OperationType_OPERATION_TYPE_WITHDRAW,
+		Amount:          200,
+		ExpectedVersion: &version,
+	})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("ожидался статус ABORTED, получена ошибка %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestGRPCServer_ProcessWalletOperation_InvalidRequest(t *testing.T) {
+	repo := new(MockWalletRepository)
+	server := grpcapi.NewServer(service.NewWalletService(repo), 0)
+
+	requests := map[string]*walletpb.ProcessWalletOperationRequest{
+		"некорректный идентификатор": {WalletId: "не-uuid", OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT, Amount: 100},
+		"нулевая сумма":              {WalletId: testWalletID.String(), OperationType: walletpb.OperationType_OPERATION_TYPE_DEPOSIT},
+		"тип операции не задан":      {WalletId: testWalletID.String(), Amount: 100},
+	}
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			_, err := server.ProcessWalletOperation(context.Background(), req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ожидался статус INVALID_ARGUMENT, получена ошибка %v", err)
+			}
+		})
+	}
+
+	repo.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,145 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHoldRepository struct {
+	mock.Mock
+}
+
+func (m *MockHoldRepository) CreateHold(ctx context.Context, params repository.NewHold) (*repository.Hold, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Hold), args.Error(1)
+}
+
+func (m *MockHoldRepository) GetHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	args := m.Called(ctx, walletID, holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Hold), args.Error(1)
+}
+
+func (m *MockHoldRepository) CaptureHold(ctx context.Context, walletID, holdID uuid.UUID, amount int64) (*repository.Hold, error) {
+	args := m.Called(ctx, walletID, holdID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Hold), args.Error(1)
+}
+
+func (m *MockHoldRepository) VoidHold(ctx context.Context, walletID, holdID uuid.UUID) (*repository.Hold, error) {
+	args := m.Called(ctx, walletID, holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Hold), args.Error(1)
+}
+
+func (m *MockHoldRepository) ExpireHolds(ctx context.Context, now time.Time, limit int) (int64, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+const (
+	testHoldDefaultTTL = 15 * time.Minute
+	testHoldMaxTTL     = time.Hour
+)
+
+var testHoldID = mustUUID("9b2f6c1e-8d4a-4f3b-a1e2-7c5d9e0f1a2b")
+
+func TestHoldService_CreateHold_DefaultTTL(t *testing.T) {
+	repo := new(MockHoldRepository)
+	expected := &repository.Hold{ID: testHoldID, WalletID: testWalletID, Amount: 300, Status: repository.HoldActive}
+	repo.On("CreateHold", mock.Anything, mock.MatchedBy(func(p repository.NewHold) bool {
+		ttl := time.Until(p.ExpiresAt)
+		return p.WalletID == testWalletID && p.Amount == 300 && p.Currency == "RUB" &&
+			ttl > testHoldDefaultTTL-time.Minute && ttl <= testHoldDefaultTTL
+	})).Return(expected, nil)
+	svc := service.NewHoldService(repo, testHoldDefaultTTL, testHoldMaxTTL)
+
+	hold, err := svc.CreateHold(context.Background(), service.HoldRequest{
+		WalletID: testWalletID,
+		Amount:   300,
+		Currency: "rub",
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if hold.ID != testHoldID {
+		t.Errorf("ожидалась блокировка %s, получена %s", testHoldID, hold.ID)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestHoldService_CreateHold_Validation(t *testing.T) {
+	cases := map[string]struct {
+		req  service.HoldRequest
+		want error
+	}{
+		"нулевая сумма":      {service.HoldRequest{WalletID: testWalletID, Amount: 0}, apperrors.ErrInvalidAmount},
+		"неизвестная валюта": {service.HoldRequest{WalletID: testWalletID, Amount: 1, Currency: "XXX"}, apperrors.ErrInvalidCurrency},
+		"отрицательный TTL":  {service.HoldRequest{WalletID: testWalletID, Amount: 1, TTL: -time.Second}, apperrors.ErrInvalidHoldTTL},
+		"TTL больше предела": {service.HoldRequest{WalletID: testWalletID, Amount: 1, TTL: testHoldMaxTTL + time.Second}, apperrors.ErrInvalidHoldTTL},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockHoldRepository)
+			svc := service.NewHoldService(repo, testHoldDefaultTTL, testHoldMaxTTL)
+
+			_, err := svc.CreateHold(context.Background(), tc.req)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("ожидалась ошибка %v, получена %v", tc.want, err)
+			}
+			repo.AssertNotCalled(t, "CreateHold", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHoldService_CaptureHold(t *testing.T) {
+	repo := new(MockHoldRepository)
+	captured := &repository.Hold{ID: testHoldID, WalletID: testWalletID, Amount: 300, CapturedAmount: 200, Status: repository.HoldCaptured}
+	repo.On("CaptureHold", mock.Anything, testWalletID, testHoldID, int64(200)).Return(captured, nil)
+	svc := service.NewHoldService(repo, testHoldDefaultTTL, testHoldMaxTTL)
+
+	hold, err := svc.CaptureHold(context.Background(), testWalletID, testHoldID, 200)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if hold.CapturedAmount != 200 {
+		t.Errorf("ожидалась списанная сумма 200, получена %d", hold.CapturedAmount)
+	}
+
+	if _, err := svc.CaptureHold(context.Background(), testWalletID, testHoldID, -1); !errors.Is(err, apperrors.ErrInvalidAmount) {
+		t.Errorf("ожидалась ошибка ErrInvalidAmount, получена %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestHoldService_VoidHold_NotActive(t *testing.T) {
+	repo := new(MockHoldRepository)
+	repo.On("VoidHold", mock.Anything, testWalletID, testHoldID).Return(nil, apperrors.ErrHoldNotActive)
+	svc := service.NewHoldService(repo, testHoldDefaultTTL, testHoldMaxTTL)
+
+	_, err := svc.VoidHold(context.Background(), testWalletID, testHoldID)
+	if !errors.Is(err, apperrors.ErrHoldNotActive) {
+		t.Errorf("ожидалась ошибка ErrHoldNotActive, получена %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockLimitRepository) ListDefaultLimits(ctx context.Context) ([]repository.OperationLimit, error) {
+	args := m.Called(ctx)
+	limits, _ := args.Get(0).([]repository.OperationLimit)
+	return limits, args.Error(1)
+}
+
+func (m *MockLimitRepository) SetLimit(ctx context.Context, limit repository.OperationLimit) (*repository.OperationLimit, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.OperationLimit), args.Error(1)
+}
+
+func (m *MockLimitRepository) DeleteLimit(ctx context.Context, walletID *uuid.UUID, operation repository.LimitOperation) error {
+	args := m.Called(ctx, walletID, operation)
+	return args.Error(0)
+}
+
+func (m *MockLimitRepository) GetWalletLimits(ctx context.Context, walletID uuid.UUID) ([]repository.WalletLimitStatus, error) {
+	args := m.Called(ctx, walletID)
+	statuses, _ := args.Get(0).([]repository.WalletLimitStatus)
+	return statuses, args.Error(1)
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestOperationLimits_Check(t *testing.T) {
+	limits := repository.OperationLimits{
+		MaxAmount: int64Ptr(1000),
+		Daily:     int64Ptr(1500),
+		Monthly:   int64Ptr(5000),
+	}
+	cases := []struct {
+		name     string
+		amount   int64
+		usage    repository.LimitUsage
+		expected *repository.LimitViolation
+	}{
+		{"within limits", 1000, repository.LimitUsage{Daily: 500, Weekly: 500, Monthly: 500}, nil},
+		{"per operation", 1001, repository.LimitUsage{}, &repository.LimitViolation{
+			Operation: repository.LimitWithdraw, Period: repository.LimitPerOperation, Limit: 1000, Remaining: 1000,
+		}},
+		{"daily", 600, repository.LimitUsage{Daily: 1000, Weekly: 1000, Monthly: 1000}, &repository.LimitViolation{
+			Operation: repository.LimitWithdraw, Period: repository.LimitDaily, Limit: 1500, Used: 1000, Remaining: 500,
+		}},
+		// Остаток - самое строгое из ограничений, а не первое по порядку
+		{"monthly is strictest", 300, repository.LimitUsage{Monthly: 4800}, &repository.LimitViolation{
+			Operation: repository.LimitWithdraw, Period: repository.LimitMonthly, Limit: 5000, Used: 4800, Remaining: 200,
+		}},
+		// Использование сверх лимита (после его снижения) не даёт отрицательного остатка
+		{"exhausted", 1, repository.LimitUsage{Daily: 2000, Monthly: 2000}, &repository.LimitViolation{
+			Operation: repository.LimitWithdraw, Period: repository.LimitDaily, Limit: 1500, Used: 2000, Remaining: 0,
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			violation := limits.Check(repository.LimitWithdraw, tc.amount, tc.usage)
+			if (violation == nil) != (tc.expected == nil) || (violation != nil && *violation != *tc.expected) {
+				t.Errorf("ожидалось %+v, получено %+v", tc.expected, violation)
+			}
+		})
+	}
+}
+
+func TestOperationLimits_NoLimits(t *testing.T) {
+	var limits repository.OperationLimits
+	if violation := limits.Check(repository.LimitDeposit, 1<<40, repository.LimitUsage{Daily: 1 << 40}); violation != nil {
+		t.Errorf("без лимитов операция должна быть допустима, получено %+v", violation)
+	}
+	if _, _, limited := limits.Allowance(repository.LimitUsage{}); limited {
+		t.Error("без лимитов остаток не ограничен")
+	}
+}
+
+func TestLimitExceededError(t *testing.T) {
+	err := apperrors.NewLimitExceeded(apperrors.LimitExceededDetails{
+		Operation: "WITHDRAW", Period: "DAILY", Limit: 1500, Used: 1000, Remaining: 500,
+	})
+	if !errors.Is(err, apperrors.ErrLimitExceeded) {
+		t.Error("ошибка превышения лимита должна соответствовать ErrLimitExceeded")
+	}
+	if errors.Is(err, apperrors.ErrInsufficientFunds) {
+		t.Error("ошибки с разными кодами не должны совпадать")
+	}
+	details, ok := err.Details.(apperrors.LimitExceededDetails)
+	if !ok || details.Remaining != 500 {
+		t.Errorf("ожидался остаток 500 в деталях ошибки, получено %+v", err.Details)
+	}
+}
+
+func TestLimitService_SetLimit_Validation(t *testing.T) {
+	cases := []struct {
+		name     string
+		limit    repository.OperationLimit
+		expected error
+	}{
+		{"unknown operation", repository.OperationLimit{Operation: "REFUND"}, apperrors.ErrInvalidLimitOperation},
+		{"zero limit", repository.OperationLimit{
+			Operation: repository.LimitDeposit, Limits: repository.OperationLimits{Daily: int64Ptr(0)},
+		}, apperrors.ErrInvalidOperationLimits},
+		{"negative limit", repository.OperationLimit{
+			Operation: repository.LimitTransfer, Limits: repository.OperationLimits{MaxAmount: int64Ptr(-1)},
+		}, apperrors.ErrInvalidOperationLimits},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockLimitRepository)
+			svc := service.NewLimitService(repo)
+
+			if _, err := svc.SetLimit(context.Background(), tc.limit); !errors.Is(err, tc.expected) {
+				t.Errorf("ожидалась ошибка %v, получено %v", tc.expected, err)
+			}
+			repo.AssertNotCalled(t, "SetLimit")
+		})
+	}
+}
+
+func TestLimitService_SetLimit_WalletOverride(t *testing.T) {
+	repo := new(MockLimitRepository)
+	walletID := uuid.New()
+	limit := repository.OperationLimit{
+		WalletID:  &walletID,
+		Operation: repository.LimitWithdraw,
+		Limits:    repository.OperationLimits{MaxAmount: int64Ptr(100)},
+	}
+	repo.On("SetLimit", mock.Anything, limit).Return(&limit, nil)
+	svc := service.NewLimitService(repo)
+
+	if _, err := svc.SetLimit(context.Background(), limit); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestLimitService_GetWalletLimits_Remaining(t *testing.T) {
+	repo := new(MockLimitRepository)
+	walletID := uuid.New()
+	repo.On("GetWalletLimits", mock.Anything, walletID).Return([]repository.WalletLimitStatus{
+		{Operation: repository.LimitDeposit},
+		{
+			Operation: repository.LimitWithdraw,
+			Limits:    repository.OperationLimits{MaxAmount: int64Ptr(1000), Weekly: int64Ptr(3000)},
+			Override:  true,
+			Usage:     repository.LimitUsage{Daily: 500, Weekly: 2500, Monthly: 2500},
+		},
+	}, nil)
+	svc := service.NewLimitService(repo)
+
+	limits, err := svc.GetWalletLimits(context.Background(), walletID)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if limits[0].Remaining != nil {
+		t.Errorf("без лимитов остаток не должен быть задан, получено %d", *limits[0].Remaining)
+	}
+	if limits[1].Remaining == nil || *limits[1].Remaining != 500 {
+		t.Errorf("ожидался остаток 500 по недельному лимиту, получено %v", limits[1].Remaining)
+	}
+}
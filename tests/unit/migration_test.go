@@ -0,0 +1,17 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devopesik/wallet-basic-operations/internal/config"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+)
+
+func TestMigrate_UnknownCommand(t *testing.T) {
+	// Неизвестная команда отклоняется до подключения к БД
+	err := postgres.Migrate(context.Background(), &config.Config{}, "reset")
+	if err == nil {
+		t.Fatal("ожидалась ошибка для неизвестной команды миграций")
+	}
+}
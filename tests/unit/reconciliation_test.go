@@ -0,0 +1,159 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReconciliationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationRepository) FindBalanceDrifts(ctx context.Context) (int64, []repository.BalanceDrift, error) {
+	args := m.Called(ctx)
+	drifts, _ := args.Get(1).([]repository.BalanceDrift)
+	return args.Get(0).(int64), drifts, args.Error(2)
+}
+
+func (m *MockReconciliationRepository) PostAdjustment(ctx context.Context, walletID uuid.UUID) (*repository.BalanceDrift, error) {
+	args := m.Called(ctx, walletID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.BalanceDrift), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) SaveReconciliationRun(ctx context.Context, run *repository.ReconciliationRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationRepository) GetLastReconciliationRun(ctx context.Context) (*repository.ReconciliationRun, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ReconciliationRun), args.Error(1)
+}
+
+var testDriftWalletID = mustUUID("4e1d2c3b-5a6f-4b7c-8d9e-0f1a2b3c4d5e")
+
+func TestReconciliationService_Reconcile_ReportsDrifts(t *testing.T) {
+	repo := new(MockReconciliationRepository)
+	drift := repository.BalanceDrift{WalletID: testDriftWalletID, Currency: "RUB", Balance: 1000, LedgerBalance: 900, Drift: 100}
+	repo.On("FindBalanceDrifts", mock.Anything).Return(int64(5), []repository.BalanceDrift{drift}, nil)
+	repo.On("SaveReconciliationRun", mock.Anything, mock.AnythingOfType("*repository.ReconciliationRun")).Return(nil)
+	svc := service.NewReconciliationService(repo)
+
+	run, err := svc.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if run.WalletsChecked != 5 || len(run.Drifts) != 1 || run.Drifts[0] != drift || run.AutoCorrect {
+		t.Errorf("неожиданный результат сверки: %+v", run)
+	}
+	if run.FinishedAt.Before(run.StartedAt) {
+		t.Errorf("время окончания %v раньше времени начала %v", run.FinishedAt, run.StartedAt)
+	}
+	// Без автоматической корректировки журнал не меняется
+	repo.AssertNotCalled(t, "PostAdjustment", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestReconciliationService_Reconcile_NoDrifts(t *testing.T) {
+	repo := new(MockReconciliationRepository)
+	repo.On("FindBalanceDrifts", mock.Anything).Return(int64(3), nil, nil)
+	repo.On("SaveReconciliationRun", mock.Anything, mock.Anything).Return(nil)
+	svc := service.NewReconciliationService(repo)
+
+	run, err := svc.Reconcile(context.Background(), true)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	// Пустой список расхождений отдаётся как [], а не null
+	if run.Drifts == nil || len(run.Drifts) != 0 {
+		t.Errorf("ожидался пустой список расхождений, получен %#v", run.Drifts)
+	}
+	repo.AssertNotCalled(t, "PostAdjustment", mock.Anything, mock.Anything)
+}
+
+func TestReconciliationService_Reconcile_AutoCorrect(t *testing.T) {
+	repo := new(MockReconciliationRepository)
+	otherWalletID := uuid.New()
+	repo.On("FindBalanceDrifts", mock.Anything).Return(int64(10), []repository.BalanceDrift{
+		{WalletID: testDriftWalletID, Currency: "RUB", Balance: 1000, LedgerBalance: 900, Drift: 100},
+		{WalletID: otherWalletID, Currency: "USD", Balance: 50, LedgerBalance: 70, Drift: -20},
+	}, nil)
+	// Расхождение пересчитано под блокировкой: баланс успел измениться
+	repo.On("PostAdjustment", mock.Anything, testDriftWalletID).Return(&repository.BalanceDrift{
+		WalletID: testDriftWalletID, Currency: "RUB", Balance: 1200, LedgerBalance: 1100, Drift: 100, Corrected: true,
+	}, nil)
+	// Расхождение исчезло к моменту корректировки
+	repo.On("PostAdjustment", mock.Anything, otherWalletID).Return(nil, nil)
+	repo.On("SaveReconciliationRun", mock.Anything, mock.Anything).Return(nil)
+	svc := service.NewReconciliationService(repo)
+
+	run, err := svc.Reconcile(context.Background(), true)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !run.AutoCorrect || len(run.Drifts) != 2 {
+		t.Fatalf("неожиданный результат сверки: %+v", run)
+	}
+	if d := run.Drifts[0]; !d.Corrected || d.Balance != 1200 || d.LedgerBalance != 1100 {
+		t.Errorf("ожидалось скорректированное расхождение с актуальными балансами, получено %+v", d)
+	}
+	if d := run.Drifts[1]; d.Corrected || d.Drift != -20 {
+		t.Errorf("расхождение без корректировки не должно отмечаться исправленным, получено %+v", d)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestReconciliationService_Reconcile_AdjustmentError(t *testing.T) {
+	repo := new(MockReconciliationRepository)
+	otherWalletID := uuid.New()
+	dbErr := apperrors.NewDatabaseError("корректировке", errors.New("connection lost"))
+	repo.On("FindBalanceDrifts", mock.Anything).Return(int64(2), []repository.BalanceDrift{
+		{WalletID: testDriftWalletID, Currency: "RUB", Balance: 10, Drift: 10},
+		{WalletID: otherWalletID, Currency: "RUB", Balance: 5, Drift: 5},
+	}, nil)
+	repo.On("PostAdjustment", mock.Anything, testDriftWalletID).Return(nil, dbErr)
+	repo.On("PostAdjustment", mock.Anything, otherWalletID).Return(&repository.BalanceDrift{
+		WalletID: otherWalletID, Currency: "RUB", Balance: 5, Drift: 5, Corrected: true,
+	}, nil)
+	repo.On("SaveReconciliationRun", mock.Anything, mock.Anything).Return(nil)
+	svc := service.NewReconciliationService(repo)
+
+	// Ошибка одного кошелька не прерывает сверку: остальные корректируются, результат сохраняется
+	run, err := svc.Reconcile(context.Background(), true)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(run.Drifts) != 2 {
+		t.Fatalf("неожиданный результат сверки: %+v", run)
+	}
+	if d := run.Drifts[0]; d.Corrected || d.Error != dbErr.Error() {
+		t.Errorf("ожидалось нескорректированное расхождение с ошибкой, получено %+v", d)
+	}
+	if d := run.Drifts[1]; !d.Corrected || d.Error != "" {
+		t.Errorf("ожидалось скорректированное расхождение, получено %+v", d)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestReconciliationService_LastRun_NotFound(t *testing.T) {
+	repo := new(MockReconciliationRepository)
+	repo.On("GetLastReconciliationRun", mock.Anything).Return(nil, apperrors.ErrReconciliationNotFound)
+	svc := service.NewReconciliationService(repo)
+
+	if _, err := svc.LastRun(context.Background()); !errors.Is(err, apperrors.ErrReconciliationNotFound) {
+		t.Errorf("ожидалась ошибка ErrReconciliationNotFound, получено %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/generated"
+	"github.com/devopesik/wallet-basic-operations/internal/handlers"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	statementFrom = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	statementTo   = time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// statementRepo возвращает репозиторий, который отдаёт выписку с начальным балансом 1000
+// и двумя операциями: пополнением на 500 и списанием 300
+func statementRepo() *MockWalletRepository {
+	repo := new(MockWalletRepository)
+	repo.On("StreamStatement", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		visitor := args.Get(2).(repository.StatementVisitor)
+		_ = visitor.Begin(repository.Statement{
+			WalletID: testWalletID, Currency: "RUB", From: statementFrom, To: statementTo, OpeningBalance: 1000,
+		})
+		_ = visitor.Entry(repository.StatementEntry{ID: mustUUID("00000000-0000-0000-0000-000000000001"),
+			Type: repository.TransactionDeposit, Amount: 500, Balance: 1500, CreatedAt: statementFrom.Add(time.Hour)})
+		_ = visitor.Entry(repository.StatementEntry{ID: mustUUID("00000000-0000-0000-0000-000000000002"),
+			Type: repository.TransactionWithdraw, Amount: -300, Balance: 1200, CreatedAt: statementFrom.Add(2 * time.Hour)})
+		_ = visitor.End(1200)
+	}).Return(nil)
+	return repo
+}
+
+// getStatement запрашивает выписку через HTTP обработчики
+func getStatement(repo *MockWalletRepository, query string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	generated.HandlerFromMux(handler.NewWalletHandler(service.NewWalletService(repo), nil, nil, nil, nil, nil), r)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/wallets/"+testWalletID.String()+"/statement"+query, nil))
+	return rec
+}
+
+func TestWalletService_StreamStatement_InvalidRange(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	err := svc.StreamStatement(context.Background(), testWalletID, service.StatementQuery{From: &statementTo, To: &statementFrom}, nil)
+	if !errors.Is(err, apperrors.ErrInvalidTimeRange) {
+		t.Errorf("ожидалась ошибка ErrInvalidTimeRange, получено: %v", err)
+	}
+	repo.AssertNotCalled(t, "StreamStatement", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWalletService_StreamStatement_FutureEnd(t *testing.T) {
+	repo := new(MockWalletRepository)
+	before := time.Now()
+	future := before.Add(24 * time.Hour)
+	repo.On("StreamStatement", mock.Anything, mock.MatchedBy(func(q repository.StatementQuery) bool {
+		return !q.To.Before(before) && !q.To.After(time.Now())
+	}), mock.Anything).Return(nil)
+	svc := service.NewWalletService(repo)
+
+	if err := svc.StreamStatement(context.Background(), testWalletID, service.StatementQuery{To: &future}, nil); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestWalletStatement_JSON(t *testing.T) {
+	rec := getStatement(statementRepo(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ожидался статус 200, получен %d", rec.Code)
+	}
+	disposition := rec.Header().Get("Content-Disposition")
+	if disposition != `attachment; filename=statement-`+testWalletID.String()+`-20250101T000000Z-20250201T000000Z.json` {
+		t.Errorf("некорректный Content-Disposition: %q", disposition)
+	}
+
+	var statement generated.WalletStatement
+	if err := json.Unmarshal(rec.Body.Bytes(), &statement); err != nil {
+		t.Fatalf("выписка не является корректным JSON: %v\n%s", err, rec.Body.String())
+	}
+	if statement.OpeningBalance != 1000 || statement.ClosingBalance != 1200 || len(statement.Transactions) != 2 {
+		t.Errorf("некорректная выписка: %+v", statement)
+	}
+	if statement.Transactions[1].Balance != 1200 || statement.MinorUnits == nil || *statement.MinorUnits != 2 {
+		t.Errorf("некорректные операции или валюта выписки: %+v", statement)
+	}
+}
+
+func TestWalletStatement_NDJSON(t *testing.T) {
+	rec := getStatement(statementRepo(), "?format=ndjson")
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("ожидался Content-Type application/x-ndjson, получен %q", ct)
+	}
+
+	var records []generated.StatementRecord
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var record generated.StatementRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("некорректная строка выписки %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 4 {
+		t.Fatalf("ожидалось 4 строки выписки, получено %d", len(records))
+	}
+	if records[0].Record != generated.OPENINGBALANCE || records[0].Balance != 1000 || !records[0].CreatedAt.Equal(statementFrom) {
+		t.Errorf("некорректная строка начального баланса: %+v", records[0])
+	}
+	if records[2].Record != generated.TRANSACTION || *records[2].Amount != -300 || records[2].Balance != 1200 {
+		t.Errorf("некорректная строка операции: %+v", records[2])
+	}
+	if records[3].Record != generated.CLOSINGBALANCE || records[3].Balance != 1200 || !records[3].CreatedAt.Equal(statementTo) {
+		t.Errorf("некорректная строка итогового баланса: %+v", records[3])
+	}
+}
+
+func TestWalletStatement_CSV(t *testing.T) {
+	rec := getStatement(statementRepo(), "?format=csv")
+	if !strings.HasSuffix(rec.Header().Get("Content-Disposition"), ".csv") {
+		t.Errorf("ожидалось имя файла .csv, получен %q", rec.Header().Get("Content-Disposition"))
+	}
+
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("выписка не является корректным CSV: %v", err)
+	}
+	if len(rows) != 5 || rows[0][0] != "record" {
+		t.Fatalf("ожидались заголовок и 4 строки выписки, получено %v", rows)
+	}
+	if got := strings.Join(rows[2], ","); got != "TRANSACTION,"+testWalletID.String()+",RUB,2025-01-01T01:00:00Z,00000000-0000-0000-0000-000000000001,DEPOSIT,500,1500," {
+		t.Errorf("некорректная строка операции: %s", got)
+	}
+	if rows[4][0] != "CLOSING_BALANCE" || rows[4][7] != "1200" {
+		t.Errorf("некорректная строка итогового баланса: %v", rows[4])
+	}
+}
+
+func TestWalletStatement_Errors(t *testing.T) {
+	if rec := getStatement(new(MockWalletRepository), "?format=pdf"); rec.Code != http.StatusBadRequest {
+		t.Errorf("ожидался статус 400 для неизвестного формата, получен %d", rec.Code)
+	}
+
+	repo := new(MockWalletRepository)
+	repo.On("StreamStatement", mock.Anything, mock.Anything, mock.Anything).Return(apperrors.ErrWalletNotFound)
+	if rec := getStatement(repo, "?format=csv"); rec.Code != http.StatusNotFound || rec.Header().Get("Content-Disposition") != "" {
+		t.Errorf("ожидался статус 404 без вложения, получен %d", rec.Code)
+	}
+}
@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/stretchr/testify/mock"
+)
+
+var testSweepWalletID = mustUUID("5f0c3a2b-1d4e-4a6b-9c8d-7e6f5a4b3c2d")
+
+func TestWalletService_ChangeWalletStatus_TrimsReason(t *testing.T) {
+	repo := new(MockWalletRepository)
+	expected := &repository.Wallet{ID: testWalletID, Status: repository.WalletFrozen}
+	repo.On("ChangeWalletStatus", mock.Anything, repository.StatusChange{
+		WalletID: testWalletID,
+		Status:   repository.WalletFrozen,
+		Reason:   "подозрительная активность",
+	}).Return(expected, nil)
+	svc := service.NewWalletService(repo)
+
+	wallet, err := svc.ChangeWalletStatus(context.Background(), repository.StatusChange{
+		WalletID: testWalletID,
+		Status:   repository.WalletFrozen,
+		Reason:   "  подозрительная активность \n",
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if wallet.Status != repository.WalletFrozen {
+		t.Errorf("ожидался статус FROZEN, получен %s", wallet.Status)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_ChangeWalletStatus_Validation(t *testing.T) {
+	sameWallet := testWalletID
+	cases := map[string]struct {
+		change repository.StatusChange
+		want   error
+	}{
+		"неизвестный статус": {
+			repository.StatusChange{WalletID: testWalletID, Status: "DELETED", Reason: "причина"},
+			apperrors.ErrInvalidWalletStatus,
+		},
+		"пустая причина": {
+			repository.StatusChange{WalletID: testWalletID, Status: repository.WalletFrozen, Reason: "   "},
+			apperrors.ErrStatusReasonRequired,
+		},
+		"перевод остатка без закрытия": {
+			repository.StatusChange{WalletID: testWalletID, Status: repository.WalletFrozen, Reason: "причина", SweepToWalletID: &testSweepWalletID},
+			apperrors.ErrInvalidWalletStatus,
+		},
+		"перевод остатка на тот же кошелёк": {
+			repository.StatusChange{WalletID: testWalletID, Status: repository.WalletClosed, Reason: "причина", SweepToWalletID: &sameWallet},
+			apperrors.ErrSameWallet,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockWalletRepository)
+			svc := service.NewWalletService(repo)
+
+			_, err := svc.ChangeWalletStatus(context.Background(), tc.change)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("ожидалась ошибка %v, получена %v", tc.want, err)
+			}
+			repo.AssertNotCalled(t, "ChangeWalletStatus", mock.Anything, mock.Anything)
+		})
+	}
+}
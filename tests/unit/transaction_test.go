@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func makeTransactions(n int) []repository.Transaction {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := make([]repository.Transaction, n)
+	for i := range items {
+		items[i] = repository.Transaction{
+			ID:           uuid.New(),
+			WalletID:     testWalletID,
+			Type:         repository.TransactionDeposit,
+			Amount:       100,
+			BalanceAfter: int64(100 * (n - i)),
+			CreatedAt:    base.Add(-time.Duration(i) * time.Minute),
+		}
+	}
+	return items
+}
+
+func TestWalletService_ListTransactions_LastPage(t *testing.T) {
+	repo := new(MockWalletRepository)
+	items := makeTransactions(3)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(&repository.Wallet{ID: testWalletID}, nil)
+	repo.On("ListTransactions", mock.Anything, mock.MatchedBy(func(f repository.TransactionFilter) bool {
+		return f.WalletID == testWalletID && f.Limit == 11 && f.After == nil
+	})).Return(items, nil)
+	svc := service.NewWalletService(repo)
+
+	page, err := svc.ListTransactions(context.Background(), testWalletID, service.TransactionQuery{Limit: 10})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 3 {
+		t.Errorf("ожидалось 3 записи, получено %d", len(page.Items))
+	}
+	if page.NextCursor != "" {
+		t.Errorf("на последней странице не должно быть курсора, получен %q", page.NextCursor)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_ListTransactions_CursorRoundTrip(t *testing.T) {
+	repo := new(MockWalletRepository)
+	items := makeTransactions(3)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(&repository.Wallet{ID: testWalletID}, nil)
+	repo.On("ListTransactions", mock.Anything, mock.MatchedBy(func(f repository.TransactionFilter) bool {
+		return f.After == nil
+	})).Return(items, nil).Once()
+	svc := service.NewWalletService(repo)
+
+	page, err := svc.ListTransactions(context.Background(), testWalletID, service.TransactionQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 2 {
+		t.Fatalf("ожидалось 2 записи, получено %d", len(page.Items))
+	}
+	if page.NextCursor == "" {
+		t.Fatal("ожидался курсор следующей страницы")
+	}
+
+	last := items[1]
+	repo.On("ListTransactions", mock.Anything, mock.MatchedBy(func(f repository.TransactionFilter) bool {
+		return f.After != nil && f.After.ID == last.ID && f.After.CreatedAt.Equal(last.CreatedAt)
+	})).Return(items[2:], nil).Once()
+
+	page, err = svc.ListTransactions(context.Background(), testWalletID, service.TransactionQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != items[2].ID {
+		t.Errorf("некорректная вторая страница: %+v", page.Items)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_ListTransactions_InvalidQuery(t *testing.T) {
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	cases := map[string]struct {
+		query service.TransactionQuery
+		want  error
+	}{
+		"limit too large": {service.TransactionQuery{Limit: 101}, apperrors.ErrInvalidLimit},
+		"negative limit":  {service.TransactionQuery{Limit: -1}, apperrors.ErrInvalidLimit},
+		"broken cursor":   {service.TransactionQuery{Cursor: "не-курсор"}, apperrors.ErrInvalidCursor},
+		"inverted range":  {service.TransactionQuery{From: &from, To: &to}, apperrors.ErrInvalidTimeRange},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockWalletRepository)
+			svc := service.NewWalletService(repo)
+
+			_, err := svc.ListTransactions(context.Background(), testWalletID, tc.query)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("ожидалась ошибка %v, получена %v", tc.want, err)
+			}
+			repo.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWalletService_ListTransactions_WalletNotFound(t *testing.T) {
+	repo := new(MockWalletRepository)
+	repo.On("GetWallet", mock.Anything, testWalletID).Return(nil, apperrors.ErrWalletNotFound)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.ListTransactions(context.Background(), testWalletID, service.TransactionQuery{})
+	if !errors.Is(err, apperrors.ErrWalletNotFound) {
+		t.Errorf("ожидалась ошибка 'кошелёк не найден', получена %v", err)
+	}
+	repo.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+}
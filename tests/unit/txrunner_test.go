@@ -0,0 +1,52 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository/postgres"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestRetryableCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      string
+		retryable bool
+	}{
+		{"ошибка сериализации", &pgconn.PgError{Code: pgerrcode.SerializationFailure}, pgerrcode.SerializationFailure, true},
+		{"взаимоблокировка", &pgconn.PgError{Code: pgerrcode.DeadlockDetected}, pgerrcode.DeadlockDetected, true},
+		{"обёрнутая в AppError", apperrors.NewDatabaseError("фиксация транзакции пополнения",
+			&pgconn.PgError{Code: pgerrcode.SerializationFailure}), pgerrcode.SerializationFailure, true},
+		{"нарушение уникальности", &pgconn.PgError{Code: pgerrcode.UniqueViolation}, "", false},
+		{"ошибка приложения", apperrors.ErrInsufficientFunds, "", false},
+		{"другая ошибка", errors.New("соединение закрыто"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, retryable := postgres.RetryableCode(tt.err)
+			if code != tt.code || retryable != tt.retryable {
+				t.Errorf("ожидалось (%q, %v), получено (%q, %v)", tt.code, tt.retryable, code, retryable)
+			}
+		})
+	}
+}
+
+func TestTxRetryPolicy_Delay(t *testing.T) {
+	policy := postgres.RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	expected := map[int]time.Duration{
+		1: 10 * time.Millisecond,
+		2: 20 * time.Millisecond,
+		3: 40 * time.Millisecond,
+		4: 50 * time.Millisecond,
+	}
+	for attempts, delay := range expected {
+		if got := policy.Delay(attempts); got != delay {
+			t.Errorf("после %d попыток ожидалась задержка %s, получено %s", attempts, delay, got)
+		}
+	}
+}
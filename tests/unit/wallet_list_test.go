@@ -0,0 +1,120 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func makeWallets(n int) []repository.Wallet {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := make([]repository.Wallet, n)
+	for i := range items {
+		items[i] = repository.Wallet{
+			ID:        uuid.New(),
+			Balance:   int64(1000 * (n - i)),
+			Currency:  "RUB",
+			Status:    repository.WalletActive,
+			CreatedAt: base.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return items
+}
+
+func TestWalletService_ListWallets_CursorRoundTrip(t *testing.T) {
+	repo := new(MockWalletRepository)
+	items := makeWallets(3)
+	repo.On("ListWallets", mock.Anything, mock.MatchedBy(func(f repository.WalletFilter) bool {
+		return f.After == nil && f.Sort == repository.WalletSortBalance && f.Descending && f.Limit == 3
+	})).Return(items, nil).Once()
+	svc := service.NewWalletService(repo)
+
+	query := service.WalletQuery{Sort: repository.WalletSortBalance, Descending: true, Limit: 2}
+	page, err := svc.ListWallets(context.Background(), query)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("ожидалось 2 кошелька и курсор, получено %d и %q", len(page.Items), page.NextCursor)
+	}
+
+	last := items[1]
+	repo.On("ListWallets", mock.Anything, mock.MatchedBy(func(f repository.WalletFilter) bool {
+		return f.After != nil && f.After.ID == last.ID && f.After.Balance == last.Balance
+	})).Return(items[2:], nil).Once()
+
+	query.Cursor = page.NextCursor
+	page, err = svc.ListWallets(context.Background(), query)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 1 || page.NextCursor != "" {
+		t.Errorf("некорректная вторая страница: %d кошельков, курсор %q", len(page.Items), page.NextCursor)
+	}
+
+	// Курсор привязан к сортировке и не принимается для другого порядка
+	query.Descending = false
+	if _, err := svc.ListWallets(context.Background(), query); !errors.Is(err, apperrors.ErrInvalidCursor) {
+		t.Errorf("ожидалась ошибка ErrInvalidCursor, получена %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestWalletService_ListWallets_Validation(t *testing.T) {
+	minBalance, maxBalance := int64(500), int64(100)
+	unknownStatus := repository.WalletStatus("DELETED")
+	from := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+	cases := map[string]struct {
+		query service.WalletQuery
+		want  error
+	}{
+		"лимит больше максимума":   {service.WalletQuery{Limit: service.MaxPageLimit + 1}, apperrors.ErrInvalidLimit},
+		"неизвестная сортировка":   {service.WalletQuery{Sort: "id"}, apperrors.ErrInvalidSort},
+		"неизвестный статус":       {service.WalletQuery{Status: &unknownStatus}, apperrors.ErrInvalidWalletStatus},
+		"неизвестная валюта":       {service.WalletQuery{Currency: "XXX"}, apperrors.ErrInvalidCurrency},
+		"минимум больше максимума": {service.WalletQuery{MinBalance: &minBalance, MaxBalance: &maxBalance}, apperrors.ErrInvalidBalanceRange},
+		"пустой интервал создания": {service.WalletQuery{CreatedFrom: &from, CreatedTo: &to}, apperrors.ErrInvalidTimeRange},
+		"некорректный курсор":      {service.WalletQuery{Cursor: "%%%"}, apperrors.ErrInvalidCursor},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockWalletRepository)
+			svc := service.NewWalletService(repo)
+
+			_, err := svc.ListWallets(context.Background(), tc.query)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("ожидалась ошибка %v, получена %v", tc.want, err)
+			}
+			repo.AssertNotCalled(t, "ListWallets", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWalletService_ListWallets_ExternalRefWithoutOwner(t *testing.T) {
+	repo := new(MockWalletRepository)
+	repo.On("ListWallets", mock.Anything, mock.MatchedBy(func(f repository.WalletFilter) bool {
+		return f.OwnerID != nil && *f.OwnerID == "" && f.ExternalRef != nil && *f.ExternalRef == "user-42" &&
+			f.Sort == repository.WalletSortCreatedAt
+	})).Return([]repository.Wallet{}, nil)
+	svc := service.NewWalletService(repo)
+
+	externalRef := "user-42"
+	page, err := svc.ListWallets(context.Background(), service.WalletQuery{ExternalRef: &externalRef})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 0 {
+		t.Errorf("ожидался пустой список, получено %d", len(page.Items))
+	}
+
+	repo.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/devopesik/wallet-basic-operations/internal/repository"
 	"github.com/devopesik/wallet-basic-operations/internal/service"
@@ -15,8 +16,16 @@ type MockWalletRepository struct {
 	mock.Mock
 }
 
-func (m *MockWalletRepository) CreateWallet(ctx context.Context) (*repository.Wallet, error) {
-	args := m.Called(ctx)
+func (m *MockWalletRepository) CreateWallet(ctx context.Context, params repository.NewWallet) (*repository.Wallet, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) FindWalletByExternalRef(ctx context.Context, ownerID, externalRef string) (*repository.Wallet, error) {
+	args := m.Called(ctx, ownerID, externalRef)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -31,16 +40,101 @@ func (m *MockWalletRepository) GetWallet(ctx context.Context, walletID uuid.UUID
 	return args.Get(0).(*repository.Wallet), args.Error(1)
 }
 
-func (m *MockWalletRepository) Deposit(ctx context.Context, walletID uuid.UUID, amount int64) error {
-	args := m.Called(ctx, walletID, amount)
-	return args.Error(0)
+func (m *MockWalletRepository) Deposit(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	args := m.Called(ctx, op)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Transaction), args.Error(1)
+}
+
+func (m *MockWalletRepository) Withdraw(ctx context.Context, op repository.Operation) (*repository.Transaction, error) {
+	args := m.Called(ctx, op)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Transaction), args.Error(1)
+}
+
+func (m *MockWalletRepository) ApplyBatch(ctx context.Context, ops []repository.BatchOperation, atomic bool) ([]repository.BatchItemResult, error) {
+	args := m.Called(ctx, ops, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.BatchItemResult), args.Error(1)
+}
+
+func (m *MockWalletRepository) Transfer(ctx context.Context, t repository.Transfer) (*repository.TransferResult, error) {
+	args := m.Called(ctx, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TransferResult), args.Error(1)
+}
+
+func (m *MockWalletRepository) ChangeWalletStatus(ctx context.Context, change repository.StatusChange) (*repository.Wallet, error) {
+	args := m.Called(ctx, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) SetCreditLimit(ctx context.Context, walletID uuid.UUID, creditLimit int64) (*repository.Wallet, error) {
+	args := m.Called(ctx, walletID, creditLimit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) SetBalanceBuckets(ctx context.Context, walletID uuid.UUID, buckets int) (*repository.Wallet, error) {
+	args := m.Called(ctx, walletID, buckets)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) ListWallets(ctx context.Context, filter repository.WalletFilter) ([]repository.Wallet, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.Wallet), args.Error(1)
+}
+
+func (m *MockWalletRepository) ListTransactions(ctx context.Context, filter repository.TransactionFilter) ([]repository.Transaction, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.Transaction), args.Error(1)
+}
+
+func (m *MockWalletRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockWalletRepository) Withdraw(ctx context.Context, walletID uuid.UUID, amount int64) error {
-	args := m.Called(ctx, walletID, amount)
+func (m *MockWalletRepository) GetBalanceAt(ctx context.Context, walletID uuid.UUID, at time.Time) (*repository.HistoricalBalance, error) {
+	args := m.Called(ctx, walletID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.HistoricalBalance), args.Error(1)
+}
+
+func (m *MockWalletRepository) StreamStatement(ctx context.Context, query repository.StatementQuery, visitor repository.StatementVisitor) error {
+	args := m.Called(ctx, query, visitor)
 	return args.Error(0)
 }
 
+func (m *MockWalletRepository) SnapshotBalances(ctx context.Context, upTo time.Time) (int64, error) {
+	args := m.Called(ctx, upTo)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func mustUUID(s string) uuid.UUID {
 	id, err := uuid.Parse(s)
 	if err != nil {
@@ -58,7 +152,7 @@ func TestWalletService_Deposit_InvalidAmount(t *testing.T) {
 	cases := []int64{0, -1, -1000}
 	for _, amount := range cases {
 		t.Run("amount="+string(rune(amount)), func(t *testing.T) {
-			err := svc.Deposit(context.Background(), testWalletID, amount)
+			_, err := svc.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: amount})
 			if err == nil {
 				t.Fatal("ожидалась ошибка при недопустимой сумме")
 			}
@@ -71,23 +165,27 @@ func TestWalletService_Deposit_InvalidAmount(t *testing.T) {
 
 func TestWalletService_Deposit_Success(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("Deposit", mock.Anything, testWalletID, int64(500)).Return(nil)
+	entry := &repository.Transaction{WalletID: testWalletID, Type: repository.TransactionDeposit, Amount: 500, BalanceAfter: 1500}
+	repo.On("Deposit", mock.Anything, repository.Operation{WalletID: testWalletID, Amount: 500}).Return(entry, nil)
 	svc := service.NewWalletService(repo)
 
-	err := svc.Deposit(context.Background(), testWalletID, 500)
+	result, err := svc.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 500})
 	if err != nil {
 		t.Fatalf("неожиданная ошибка: %v", err)
 	}
+	if result.BalanceAfter != 1500 {
+		t.Errorf("ожидался баланс 1500 после операции, получен %d", result.BalanceAfter)
+	}
 
 	repo.AssertExpectations(t)
 }
 
 func TestWalletService_Deposit_WalletNotFound(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("Deposit", mock.Anything, testWalletID, int64(100)).Return(errors.New("кошелёк не найден"))
+	repo.On("Deposit", mock.Anything, repository.Operation{WalletID: testWalletID, Amount: 100}).Return(nil, errors.New("кошелёк не найден"))
 	svc := service.NewWalletService(repo)
 
-	err := svc.Deposit(context.Background(), testWalletID, 100)
+	_, err := svc.Deposit(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 100})
 	if err == nil {
 		t.Fatal("ожидалась ошибка 'кошелёк не найден'")
 	}
@@ -105,7 +203,7 @@ func TestWalletService_Withdraw_InvalidAmount(t *testing.T) {
 	cases := []int64{0, -1, -1000}
 	for _, amount := range cases {
 		t.Run("amount="+string(rune(amount)), func(t *testing.T) {
-			err := svc.Withdraw(context.Background(), testWalletID, amount)
+			_, err := svc.Withdraw(context.Background(), repository.Operation{WalletID: testWalletID, Amount: amount})
 			if err == nil {
 				t.Fatal("ожидалась ошибка при недопустимой сумме")
 			}
@@ -118,23 +216,27 @@ func TestWalletService_Withdraw_InvalidAmount(t *testing.T) {
 
 func TestWalletService_Withdraw_Success(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("Withdraw", mock.Anything, testWalletID, int64(200)).Return(nil)
+	entry := &repository.Transaction{WalletID: testWalletID, Type: repository.TransactionWithdraw, Amount: -200, BalanceAfter: 300}
+	repo.On("Withdraw", mock.Anything, repository.Operation{WalletID: testWalletID, Amount: 200}).Return(entry, nil)
 	svc := service.NewWalletService(repo)
 
-	err := svc.Withdraw(context.Background(), testWalletID, 200)
+	result, err := svc.Withdraw(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 200})
 	if err != nil {
 		t.Fatalf("неожиданная ошибка: %v", err)
 	}
+	if result.BalanceAfter != 300 {
+		t.Errorf("ожидался баланс 300 после операции, получен %d", result.BalanceAfter)
+	}
 
 	repo.AssertExpectations(t)
 }
 
 func TestWalletService_Withdraw_InsufficientFunds(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("Withdraw", mock.Anything, testWalletID, int64(1000)).Return(errors.New("недостаточно средств"))
+	repo.On("Withdraw", mock.Anything, repository.Operation{WalletID: testWalletID, Amount: 1000}).Return(nil, errors.New("недостаточно средств"))
 	svc := service.NewWalletService(repo)
 
-	err := svc.Withdraw(context.Background(), testWalletID, 1000)
+	_, err := svc.Withdraw(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 1000})
 	if err == nil {
 		t.Fatal("ожидалась ошибка 'недостаточно средств'")
 	}
@@ -147,10 +249,10 @@ func TestWalletService_Withdraw_InsufficientFunds(t *testing.T) {
 
 func TestWalletService_Withdraw_WalletNotFound(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("Withdraw", mock.Anything, testWalletID, int64(100)).Return(errors.New("кошелёк не найден"))
+	repo.On("Withdraw", mock.Anything, repository.Operation{WalletID: testWalletID, Amount: 100}).Return(nil, errors.New("кошелёк не найден"))
 	svc := service.NewWalletService(repo)
 
-	err := svc.Withdraw(context.Background(), testWalletID, 100)
+	_, err := svc.Withdraw(context.Background(), repository.Operation{WalletID: testWalletID, Amount: 100})
 	if err == nil {
 		t.Fatal("ожидалась ошибка 'кошелёк не найден'")
 	}
@@ -161,6 +263,51 @@ func TestWalletService_Withdraw_WalletNotFound(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+var testRecipientID = mustUUID("123e4567-e89b-12d3-a456-426614174001")
+
+func TestWalletService_Transfer_InvalidAmount(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.Transfer(context.Background(), repository.Transfer{FromWalletID: testWalletID, ToWalletID: testRecipientID, Amount: 0})
+	if err == nil {
+		t.Fatal("ожидалась ошибка при недопустимой сумме")
+	}
+	if err.Error() != "сумма должна быть положительной" {
+		t.Errorf("некорректное сообщение об ошибке: %v", err)
+	}
+
+	repo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything)
+}
+
+func TestWalletService_Transfer_SameWallet(t *testing.T) {
+	repo := new(MockWalletRepository)
+	svc := service.NewWalletService(repo)
+
+	_, err := svc.Transfer(context.Background(), repository.Transfer{FromWalletID: testWalletID, ToWalletID: testWalletID, Amount: 100})
+	if err == nil {
+		t.Fatal("ожидалась ошибка при переводе на тот же кошелёк")
+	}
+	if err.Error() != "кошельки отправителя и получателя должны различаться" {
+		t.Errorf("некорректное сообщение об ошибке: %v", err)
+	}
+
+	repo.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything)
+}
+
+func TestWalletService_Transfer_Success(t *testing.T) {
+	repo := new(MockWalletRepository)
+	transfer := repository.Transfer{FromWalletID: testWalletID, ToWalletID: testRecipientID, Amount: 300}
+	repo.On("Transfer", mock.Anything, transfer).Return(&repository.TransferResult{}, nil)
+	svc := service.NewWalletService(repo)
+
+	if _, err := svc.Transfer(context.Background(), transfer); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	repo.AssertExpectations(t)
+}
+
 func TestWalletService_GetWallet_Success(t *testing.T) {
 	repo := new(MockWalletRepository)
 	expectedWallet := &repository.Wallet{
@@ -206,10 +353,10 @@ func TestWalletService_CreateWallet_Success(t *testing.T) {
 		ID:      testWalletID,
 		Balance: 0,
 	}
-	repo.On("CreateWallet", mock.Anything).Return(expectedWallet, nil)
+	repo.On("CreateWallet", mock.Anything, repository.NewWallet{Currency: "RUB"}).Return(expectedWallet, nil)
 	svc := service.NewWalletService(repo)
 
-	wallet, err := svc.CreateWallet(context.Background())
+	wallet, err := svc.CreateWallet(context.Background(), repository.NewWallet{})
 	if err != nil {
 		t.Fatalf("неожиданная ошибка: %v", err)
 	}
@@ -225,10 +372,10 @@ func TestWalletService_CreateWallet_Success(t *testing.T) {
 
 func TestWalletService_CreateWallet_AlreadyExists(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("CreateWallet", mock.Anything).Return(nil, errors.New("кошелёк уже существует"))
+	repo.On("CreateWallet", mock.Anything, repository.NewWallet{Currency: "RUB"}).Return(nil, errors.New("кошелёк уже существует"))
 	svc := service.NewWalletService(repo)
 
-	_, err := svc.CreateWallet(context.Background())
+	_, err := svc.CreateWallet(context.Background(), repository.NewWallet{})
 	if err == nil {
 		t.Fatal("ожидалась ошибка 'кошелёк уже существует'")
 	}
@@ -241,10 +388,10 @@ func TestWalletService_CreateWallet_AlreadyExists(t *testing.T) {
 
 func TestWalletService_CreateWallet_RepositoryError(t *testing.T) {
 	repo := new(MockWalletRepository)
-	repo.On("CreateWallet", mock.Anything).Return(nil, errors.New("ошибка подключения к базе данных"))
+	repo.On("CreateWallet", mock.Anything, repository.NewWallet{Currency: "RUB"}).Return(nil, errors.New("ошибка подключения к базе данных"))
 	svc := service.NewWalletService(repo)
 
-	_, err := svc.CreateWallet(context.Background())
+	_, err := svc.CreateWallet(context.Background(), repository.NewWallet{})
 	if err == nil {
 		t.Fatal("ожидалась ошибка от репозитория")
 	}
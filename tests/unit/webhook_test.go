@@ -0,0 +1,382 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	apperrors "github.com/devopesik/wallet-basic-operations/internal/errors"
+	"github.com/devopesik/wallet-basic-operations/internal/events"
+	"github.com/devopesik/wallet-basic-operations/internal/repository"
+	"github.com/devopesik/wallet-basic-operations/internal/service"
+	"github.com/devopesik/wallet-basic-operations/internal/webhook"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateWebhookSubscription(ctx context.Context, params repository.WebhookSubscriptionParams, secret string) (*repository.WebhookSubscription, error) {
+	args := m.Called(ctx, params, secret)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetWebhookSubscription(ctx context.Context, id uuid.UUID) (*repository.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListWebhookSubscriptions(ctx context.Context) ([]repository.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	subs, _ := args.Get(0).([]repository.WebhookSubscription)
+	return subs, args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateWebhookSubscription(ctx context.Context, id uuid.UUID, params repository.WebhookSubscriptionParams) (*repository.WebhookSubscription, error) {
+	args := m.Called(ctx, id, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListWebhookDeliveries(ctx context.Context, filter repository.WebhookDeliveryFilter) ([]repository.WebhookDelivery, error) {
+	args := m.Called(ctx, filter)
+	deliveries, _ := args.Get(0).([]repository.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookRepository) ReplayWebhookDeliveries(ctx context.Context, subscriptionID uuid.UUID, deliveryIDs []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, subscriptionID, deliveryIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) EnqueueWebhookDeliveries(ctx context.Context, event repository.WebhookEvent) (int64, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]repository.WebhookDispatch, error) {
+	args := m.Called(ctx, limit, leaseUntil)
+	dispatches, _ := args.Get(0).([]repository.WebhookDispatch)
+	return dispatches, args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordWebhookAttempt(ctx context.Context, attempt repository.WebhookAttempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
+}
+
+const testWebhookSecret = "whsec_test"
+
+var testSubscriptionID = mustUUID("7a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+func TestWebhookSignature_Verify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"type":"Deposited"}`)
+	timestamp := now.Add(-time.Minute)
+	signature := webhook.Sign(testWebhookSecret, timestamp, body)
+
+	if err := webhook.Verify(testWebhookSecret, strconv.FormatInt(timestamp.Unix(), 10), signature, body, 5*time.Minute, now); err != nil {
+		t.Errorf("корректная подпись отклонена: %v", err)
+	}
+	if err := webhook.Verify(testWebhookSecret, strconv.FormatInt(timestamp.Unix(), 10), signature, []byte(`{"type":"Withdrawn"}`), 5*time.Minute, now); !errors.Is(err, webhook.ErrInvalidSignature) {
+		t.Errorf("изменённое тело должно отклоняться, получено %v", err)
+	}
+	if err := webhook.Verify("whsec_other", strconv.FormatInt(timestamp.Unix(), 10), signature, body, 5*time.Minute, now); !errors.Is(err, webhook.ErrInvalidSignature) {
+		t.Errorf("подпись другим ключом должна отклоняться, получено %v", err)
+	}
+	// Время входит в подпись: подменить заголовок времени нельзя
+	if err := webhook.Verify(testWebhookSecret, strconv.FormatInt(now.Unix(), 10), signature, body, 5*time.Minute, now); !errors.Is(err, webhook.ErrInvalidSignature) {
+		t.Errorf("подменённое время должно отклоняться, получено %v", err)
+	}
+	if err := webhook.Verify(testWebhookSecret, strconv.FormatInt(timestamp.Unix(), 10), signature, body, 30*time.Second, now); !errors.Is(err, webhook.ErrTimestampExpired) {
+		t.Errorf("устаревший запрос должен отклоняться, получено %v", err)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := webhook.RetryPolicy{MaxAttempts: 10, BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+	expected := map[int]time.Duration{
+		1: 10 * time.Second,
+		2: 20 * time.Second,
+		3: 40 * time.Second,
+		4: time.Minute,
+		9: time.Minute,
+	}
+	for attempts, delay := range expected {
+		if got := policy.Delay(attempts); got != delay {
+			t.Errorf("после %d попыток ожидалась задержка %s, получено %s", attempts, delay, got)
+		}
+	}
+}
+
+func TestWebhookPublisher_EnqueuesEventEnvelope(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	event := testEvent()
+	expectedBody, _ := json.Marshal(event)
+	repo.On("EnqueueWebhookDeliveries", mock.Anything, repository.WebhookEvent{
+		ID:       event.ID,
+		WalletID: event.WalletID,
+		Type:     string(events.Deposited),
+		Payload:  expectedBody,
+	}).Return(int64(2), nil)
+
+	if err := webhook.NewPublisher(repo).Publish(context.Background(), event); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	repo.AssertExpectations(t)
+}
+
+// testDispatch возвращает доставку на url после attempts неудачных попыток
+func testDispatch(url string, attempts int) repository.WebhookDispatch {
+	return repository.WebhookDispatch{
+		WebhookDelivery: repository.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: testSubscriptionID,
+			EventID:        uuid.New(),
+			EventType:      string(events.Deposited),
+			Payload:        []byte(`{"type":"Deposited","payload":{"amount":100}}`),
+			Status:         repository.WebhookDeliveryPending,
+			Attempts:       attempts,
+			NextAttemptAt:  time.Now().Add(time.Minute).Truncate(time.Microsecond),
+		},
+		URL:    url,
+		Secret: testWebhookSecret,
+	}
+}
+
+var testRetryPolicy = webhook.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+
+func TestDispatcher_DeliversSignedRequest(t *testing.T) {
+	var verifyErr error
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verifyErr = webhook.Verify(testWebhookSecret, r.Header.Get(webhook.HeaderTimestamp),
+			r.Header.Get(webhook.HeaderSignature), body, 5*time.Minute, time.Now())
+		if verifyErr != nil || r.Header.Get(webhook.HeaderDeliveryID) == "" || r.Header.Get("X-Event-Type") != "Deposited" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	repo := new(MockWebhookRepository)
+	dispatch := testDispatch(receiver.URL, 0)
+	repo.On("ClaimWebhookDeliveries", mock.Anything, 10, mock.AnythingOfType("time.Time")).
+		Return([]repository.WebhookDispatch{dispatch}, nil)
+	repo.On("RecordWebhookAttempt", mock.Anything, repository.WebhookAttempt{
+		DeliveryID:   dispatch.ID,
+		ClaimedUntil: dispatch.NextAttemptAt,
+		Status:       repository.WebhookDeliveryDelivered,
+		StatusCode:   http.StatusNoContent,
+	}).Return(nil)
+
+	delivered, err := webhook.NewDispatcher(repo, testRetryPolicy, time.Second, 10).DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if verifyErr != nil {
+		t.Fatalf("получатель не принял подпись: %v", verifyErr)
+	}
+	if delivered != 1 {
+		t.Errorf("ожидалась 1 доставка, получено %d", delivered)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestDispatcher_SchedulesRetryWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := new(MockWebhookRepository)
+	// Вторая неудачная попытка из трёх: следующая через BaseDelay * 2
+	dispatch := testDispatch(receiver.URL, 1)
+	repo.On("ClaimWebhookDeliveries", mock.Anything, 10, mock.AnythingOfType("time.Time")).
+		Return([]repository.WebhookDispatch{dispatch}, nil)
+	var recorded repository.WebhookAttempt
+	repo.On("RecordWebhookAttempt", mock.Anything, mock.AnythingOfType("repository.WebhookAttempt")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(repository.WebhookAttempt) }).
+		Return(nil)
+
+	before := time.Now()
+	delivered, err := webhook.NewDispatcher(repo, testRetryPolicy, time.Second, 10).DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if delivered != 0 {
+		t.Errorf("неудачная попытка не должна считаться доставкой")
+	}
+	if recorded.Status != repository.WebhookDeliveryPending || recorded.StatusCode != http.StatusInternalServerError || recorded.Error == "" {
+		t.Errorf("неожиданный результат попытки: %+v", recorded)
+	}
+	if delay := recorded.NextAttemptAt.Sub(before); delay < 2*time.Minute || delay > 2*time.Minute+5*time.Second {
+		t.Errorf("ожидалась задержка около 2 минут, получено %s", delay)
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	// Получатель недоступен, попытка последняя
+	dispatch := testDispatch("http://127.0.0.1:1/unreachable", testRetryPolicy.MaxAttempts-1)
+	repo.On("ClaimWebhookDeliveries", mock.Anything, 10, mock.AnythingOfType("time.Time")).
+		Return([]repository.WebhookDispatch{dispatch}, nil)
+	var recorded repository.WebhookAttempt
+	repo.On("RecordWebhookAttempt", mock.Anything, mock.AnythingOfType("repository.WebhookAttempt")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(repository.WebhookAttempt) }).
+		Return(nil)
+
+	if _, err := webhook.NewDispatcher(repo, testRetryPolicy, time.Second, 10).DispatchDue(context.Background()); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if recorded.Status != repository.WebhookDeliveryDead || recorded.StatusCode != 0 || recorded.Error == "" {
+		t.Errorf("ожидался перевод доставки в DEAD, получено %+v", recorded)
+	}
+}
+
+func TestDispatcher_DrainsFullBatches(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := new(MockWebhookRepository)
+	repo.On("ClaimWebhookDeliveries", mock.Anything, 2, mock.AnythingOfType("time.Time")).
+		Return([]repository.WebhookDispatch{testDispatch(receiver.URL, 0), testDispatch(receiver.URL, 0)}, nil).Once()
+	repo.On("ClaimWebhookDeliveries", mock.Anything, 2, mock.AnythingOfType("time.Time")).
+		Return([]repository.WebhookDispatch{testDispatch(receiver.URL, 0)}, nil).Once()
+	repo.On("RecordWebhookAttempt", mock.Anything, mock.AnythingOfType("repository.WebhookAttempt")).Return(nil)
+
+	delivered, err := webhook.NewDispatcher(repo, testRetryPolicy, time.Second, 2).DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if delivered != 3 {
+		t.Errorf("ожидалось 3 доставки, получено %d", delivered)
+	}
+	repo.AssertNumberOfCalls(t, "ClaimWebhookDeliveries", 2)
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	expectedParams := repository.WebhookSubscriptionParams{
+		URL:        "https://merchant.example.com/hooks",
+		EventTypes: []string{"Deposited", "Withdrawn"},
+		Active:     true,
+	}
+	repo.On("CreateWebhookSubscription", mock.Anything, expectedParams, mock.AnythingOfType("string")).
+		Return(&repository.WebhookSubscription{ID: testSubscriptionID}, nil)
+	svc := service.NewWebhookService(repo)
+
+	// Повторяющиеся типы событий удаляются
+	_, err := svc.CreateSubscription(context.Background(), repository.WebhookSubscriptionParams{
+		URL:        "https://merchant.example.com/hooks",
+		EventTypes: []string{"Deposited", "Withdrawn", "Deposited"},
+		Active:     true,
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	secret := repo.Calls[0].Arguments.Get(2).(string)
+	if len(secret) < 32 {
+		t.Errorf("слишком короткий ключ подписи: %q", secret)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_CreateSubscription_Validation(t *testing.T) {
+	cases := []struct {
+		name     string
+		params   repository.WebhookSubscriptionParams
+		expected error
+	}{
+		{"relative url", repository.WebhookSubscriptionParams{URL: "/hooks"}, apperrors.ErrInvalidWebhookURL},
+		{"unsupported scheme", repository.WebhookSubscriptionParams{URL: "ftp://merchant.example.com"}, apperrors.ErrInvalidWebhookURL},
+		{"unknown event type", repository.WebhookSubscriptionParams{
+			URL: "https://merchant.example.com", EventTypes: []string{"Refunded"},
+		}, apperrors.ErrInvalidEventType},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := new(MockWebhookRepository)
+			svc := service.NewWebhookService(repo)
+
+			if _, err := svc.CreateSubscription(context.Background(), tc.params); !errors.Is(err, tc.expected) {
+				t.Errorf("ожидалась ошибка %v, получено %v", tc.expected, err)
+			}
+			repo.AssertNotCalled(t, "CreateWebhookSubscription")
+		})
+	}
+}
+
+func TestWebhookService_ListDeliveries_Pagination(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	deliveries := []repository.WebhookDelivery{
+		{ID: uuid.New(), CreatedAt: now},
+		{ID: uuid.New(), CreatedAt: now.Add(-time.Second)},
+		{ID: uuid.New(), CreatedAt: now.Add(-2 * time.Second)},
+	}
+	dead := repository.WebhookDeliveryDead
+	repo.On("ListWebhookDeliveries", mock.Anything, repository.WebhookDeliveryFilter{
+		SubscriptionID: testSubscriptionID,
+		Status:         &dead,
+		Limit:          3,
+	}).Return(deliveries, nil)
+	svc := service.NewWebhookService(repo)
+
+	page, err := svc.ListDeliveries(context.Background(), testSubscriptionID, service.WebhookDeliveryQuery{Status: &dead, Limit: 2})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("ожидалась страница из 2 доставок с курсором, получено %d, %q", len(page.Items), page.NextCursor)
+	}
+
+	// Курсор указывает на последнюю доставку страницы
+	repo.On("ListWebhookDeliveries", mock.Anything, repository.WebhookDeliveryFilter{
+		SubscriptionID: testSubscriptionID,
+		After:          &repository.WebhookDeliveryCursor{CreatedAt: deliveries[1].CreatedAt, ID: deliveries[1].ID},
+		Limit:          3,
+	}).Return(deliveries[2:], nil)
+	page, err = svc.ListDeliveries(context.Background(), testSubscriptionID, service.WebhookDeliveryQuery{Limit: 2, Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Items) != 1 || page.NextCursor != "" {
+		t.Errorf("ожидалась последняя страница из 1 доставки, получено %d, %q", len(page.Items), page.NextCursor)
+	}
+}
+
+func TestWebhookService_ListDeliveries_InvalidStatus(t *testing.T) {
+	repo := new(MockWebhookRepository)
+	svc := service.NewWebhookService(repo)
+	status := repository.WebhookDeliveryStatus("FAILED")
+
+	_, err := svc.ListDeliveries(context.Background(), testSubscriptionID, service.WebhookDeliveryQuery{Status: &status})
+	if !errors.Is(err, apperrors.ErrInvalidDeliveryStatus) {
+		t.Errorf("ожидалась ErrInvalidDeliveryStatus, получено %v", err)
+	}
+}